@@ -0,0 +1,177 @@
+package main
+
+import (
+	"time"
+)
+
+// Resolution of investigation (camt.029.001.09)
+// Only the answer to a cancellation request is modelled: modification, claim non receipt and
+// charges details are left out, as are the original payment information of CxlDtls
+
+const camt029Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.029.001.09"
+
+type ResolutionOfInvestigationV09 struct {
+	Assgnmt     *CaseAssignment5            `xml:"Assgnmt" json:"Assgnmt"`
+	RslvdCase   *Case5                      `xml:"RslvdCase,omitempty" json:"RslvdCase,omitempty"`
+	Sts         *InvestigationStatus5Choice `xml:"Sts" json:"Sts"`
+	CxlDtls     []*UnderlyingTransaction22  `xml:"CxlDtls,omitempty" json:"CxlDtls,omitempty"`
+	SplmtryData []*SupplementaryData1       `xml:"SplmtryData,omitempty" json:"SplmtryData,omitempty"`
+}
+
+type InvestigationStatus5Choice struct {
+	Conf           *ExternalInvestigationExecutionConfirmation1Code `xml:"Conf,omitempty" json:"Conf,omitempty"`
+	AssgnmtCxlConf bool                                             `xml:"AssgnmtCxlConf,omitempty" json:"AssgnmtCxlConf,omitempty"`
+}
+
+type UnderlyingTransaction22 struct {
+	OrgnlGrpInfAndSts *OriginalGroupHeader14   `xml:"OrgnlGrpInfAndSts,omitempty" json:"OrgnlGrpInfAndSts,omitempty"`
+	TxInfAndSts       []*PaymentTransaction102 `xml:"TxInfAndSts,omitempty" json:"TxInfAndSts,omitempty"`
+}
+
+type OriginalGroupHeader14 struct {
+	OrgnlGrpCxlId    *Max35Text                        `xml:"OrgnlGrpCxlId,omitempty" json:"OrgnlGrpCxlId,omitempty"`
+	RslvdCase        *Case5                            `xml:"RslvdCase,omitempty" json:"RslvdCase,omitempty"`
+	OrgnlMsgId       *Max35Text                        `xml:"OrgnlMsgId" json:"OrgnlMsgId"`
+	OrgnlMsgNmId     *Max35Text                        `xml:"OrgnlMsgNmId" json:"OrgnlMsgNmId"`
+	OrgnlCreDtTm     *ISODateTime                      `xml:"OrgnlCreDtTm,omitempty" json:"OrgnlCreDtTm,omitempty"`
+	OrgnlNbOfTxs     *Max15NumericText                 `xml:"OrgnlNbOfTxs,omitempty" json:"OrgnlNbOfTxs,omitempty"`
+	OrgnlCtrlSum     *DecimalNumber                    `xml:"OrgnlCtrlSum,omitempty" json:"OrgnlCtrlSum,omitempty"`
+	GrpCxlSts        *GroupCancellationStatus1Code     `xml:"GrpCxlSts,omitempty" json:"GrpCxlSts,omitempty"`
+	CxlStsRsnInf     []*CancellationStatusReason4      `xml:"CxlStsRsnInf,omitempty" json:"CxlStsRsnInf,omitempty"`
+	NbOfTxsPerCxlSts []*NumberOfTransactionsPerStatus1 `xml:"NbOfTxsPerCxlSts,omitempty" json:"NbOfTxsPerCxlSts,omitempty"`
+}
+
+type NumberOfTransactionsPerStatus1 struct {
+	DtldNbOfTxs *Max15NumericText                  `xml:"DtldNbOfTxs" json:"DtldNbOfTxs"`
+	DtldSts     *CancellationIndividualStatus1Code `xml:"DtldSts" json:"DtldSts"`
+	DtldCtrlSum *DecimalNumber                     `xml:"DtldCtrlSum,omitempty" json:"DtldCtrlSum,omitempty"`
+}
+
+type PaymentTransaction102 struct {
+	CxlStsId            *Max35Text                         `xml:"CxlStsId,omitempty" json:"CxlStsId,omitempty"`
+	RslvdCase           *Case5                             `xml:"RslvdCase,omitempty" json:"RslvdCase,omitempty"`
+	OrgnlGrpInf         *OriginalGroupInformation29        `xml:"OrgnlGrpInf,omitempty" json:"OrgnlGrpInf,omitempty"`
+	OrgnlInstrId        *Max35Text                         `xml:"OrgnlInstrId,omitempty" json:"OrgnlInstrId,omitempty"`
+	OrgnlEndToEndId     *Max35Text                         `xml:"OrgnlEndToEndId,omitempty" json:"OrgnlEndToEndId,omitempty"`
+	OrgnlTxId           *Max35Text                         `xml:"OrgnlTxId,omitempty" json:"OrgnlTxId,omitempty"`
+	OrgnlClrSysRef      *Max35Text                         `xml:"OrgnlClrSysRef,omitempty" json:"OrgnlClrSysRef,omitempty"`
+	OrgnlUETR           *UUIDv4Identifier                  `xml:"OrgnlUETR,omitempty" json:"OrgnlUETR,omitempty"`
+	TxCxlSts            *CancellationIndividualStatus1Code `xml:"TxCxlSts,omitempty" json:"TxCxlSts,omitempty"`
+	CxlStsRsnInf        []*CancellationStatusReason4       `xml:"CxlStsRsnInf,omitempty" json:"CxlStsRsnInf,omitempty"`
+	OrgnlIntrBkSttlmAmt *ActiveOrHistoricCurrencyAndAmount `xml:"OrgnlIntrBkSttlmAmt,omitempty" json:"OrgnlIntrBkSttlmAmt,omitempty"`
+	OrgnlIntrBkSttlmDt  *ISODate                           `xml:"OrgnlIntrBkSttlmDt,omitempty" json:"OrgnlIntrBkSttlmDt,omitempty"`
+	Assgnr              *Party40Choice                     `xml:"Assgnr,omitempty" json:"Assgnr,omitempty"`
+	Assgne              *Party40Choice                     `xml:"Assgne,omitempty" json:"Assgne,omitempty"`
+}
+
+type CancellationStatusReason4 struct {
+	Orgtr    *PartyIdentification135          `xml:"Orgtr,omitempty" json:"Orgtr,omitempty"`
+	Rsn      *CancellationStatusReason3Choice `xml:"Rsn,omitempty" json:"Rsn,omitempty"`
+	AddtlInf []*Max105Text                    `xml:"AddtlInf,omitempty" json:"AddtlInf,omitempty"`
+}
+
+type CancellationStatusReason3Choice struct {
+	Cd    *ExternalPaymentCancellationRejection1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                                 `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
+}
+
+// ExternalInvestigationExecutionConfirmation1Code May be no more than 4 items long
+type ExternalInvestigationExecutionConfirmation1Code string
+
+func (v ExternalInvestigationExecutionConfirmation1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
+// ExternalPaymentCancellationRejection1Code May be no more than 4 items long
+type ExternalPaymentCancellationRejection1Code string
+
+func (v ExternalPaymentCancellationRejection1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
+// CancellationIndividualStatus1Code May be one of RJCR, ACCR, PDCR
+type CancellationIndividualStatus1Code string
+
+func (v CancellationIndividualStatus1Code) Validate() error {
+	return checkEnumeration(string(v), "RJCR", "ACCR", "PDCR")
+}
+
+// GroupCancellationStatus1Code May be one of PACR, RJCR, ACCR, PDCR
+type GroupCancellationStatus1Code string
+
+func (v GroupCancellationStatus1Code) Validate() error {
+	return checkEnumeration(string(v), "PACR", "RJCR", "ACCR", "PDCR")
+}
+
+// Cancellation status of each transaction of a case in each state
+var transactionCancellationStatus = map[string]CancellationIndividualStatus1Code{
+	caseStatusCancelled: "ACCR",
+	caseStatusRejected:  "RJCR",
+	caseStatusPending:   "PDCR",
+}
+
+// Build camt.029 resolution answering request, the camt.056 opening case c, with the state of c
+// The answer goes back to the assigner of the request, rejection reason is given for every transaction
+func NewResolution(request BusMsg, c *InvestigationCase) BusMsg {
+	now := ISODateTime(time.Now().UTC().Truncate(time.Millisecond))
+	id := Max35Text(newMessageId())
+
+	resolution := BusMsg{AppHdr: replyHeader(request.AppHdr, "camt.029.001.09", id, now)}
+
+	caseId := Max35Text(c.Id)
+	resolved := &Case5{Id: &caseId}
+	assignment := &CaseAssignment5{Id: &id, CreDtTm: &now}
+	if req := request.Document.FIToFIPmtCxlReq; req != nil {
+		if req.Assgnmt != nil {
+			assignment.Assgnr, assignment.Assgne = req.Assgnmt.Assgne, req.Assgnmt.Assgnr
+			resolved.Cretr = req.Assgnmt.Assgnr
+		}
+		if req.Case != nil && req.Case.Cretr != nil {
+			resolved.Cretr = req.Case.Cretr
+		}
+	}
+
+	confirmation := ExternalInvestigationExecutionConfirmation1Code(c.Status)
+	txStatus := transactionCancellationStatus[c.Status]
+	var reasons []*CancellationStatusReason4
+	if c.Status == caseStatusRejected && c.Reason != "" {
+		code := ExternalPaymentCancellationRejection1Code(c.Reason)
+		reasons = append(reasons, &CancellationStatusReason4{Rsn: &CancellationStatusReason3Choice{Cd: &code}})
+	}
+
+	details := &UnderlyingTransaction22{}
+	for _, tx := range c.Transactions {
+		txResolution := &PaymentTransaction102{
+			CxlStsId:        optionalText(tx.CxlId),
+			OrgnlEndToEndId: optionalText(tx.OrgnlEndToEndId),
+			OrgnlTxId:       optionalText(tx.OrgnlTxId),
+			TxCxlSts:        &txStatus,
+			CxlStsRsnInf:    reasons,
+		}
+		if tx.OrgnlMsgId != "" && tx.OrgnlMsgNmId != "" {
+			txResolution.OrgnlGrpInf = &OriginalGroupInformation29{OrgnlMsgId: optionalText(tx.OrgnlMsgId), OrgnlMsgNmId: optionalText(tx.OrgnlMsgNmId)}
+		}
+		if tx.OrgnlUETR != "" {
+			uetr := UUIDv4Identifier(tx.OrgnlUETR)
+			txResolution.OrgnlUETR = &uetr
+		}
+		details.TxInfAndSts = append(details.TxInfAndSts, txResolution)
+	}
+
+	resolution.Document.RsltnOfInvstgtn = &ResolutionOfInvestigationV09{
+		Assgnmt:   assignment,
+		RslvdCase: resolved,
+		Sts:       &InvestigationStatus5Choice{Conf: &confirmation},
+		CxlDtls:   []*UnderlyingTransaction22{details},
+	}
+	return resolution
+}
+
+// Return text element of value, nil when value is empty
+func optionalText(value string) *Max35Text {
+	if value == "" {
+		return nil
+	}
+	text := Max35Text(value)
+	return &text
+}
@@ -0,0 +1,448 @@
+package main
+
+import (
+	"strconv"
+	"time"
+)
+
+// Bank to customer statement (camt.053.001.08)
+// Only what is needed to report booked credit transfers is modelled: pagination, interest, card, charges and
+// batch details are left out, as are amount details and the proprietary elements of entries
+
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"
+
+type BankToCustomerStatementV08 struct {
+	GrpHdr      *GroupHeader81        `xml:"GrpHdr" json:"GrpHdr"`
+	Stmt        []*AccountStatement9  `xml:"Stmt" json:"Stmt"`
+	SplmtryData []*SupplementaryData1 `xml:"SplmtryData,omitempty" json:"SplmtryData,omitempty"`
+}
+
+type GroupHeader81 struct {
+	MsgId   *Max35Text              `xml:"MsgId" json:"MsgId"`
+	CreDtTm *ISODateTime            `xml:"CreDtTm" json:"CreDtTm"`
+	MsgRcpt *PartyIdentification135 `xml:"MsgRcpt,omitempty" json:"MsgRcpt,omitempty"`
+}
+
+type AccountStatement9 struct {
+	Id        *Max35Text          `xml:"Id" json:"Id"`
+	CreDtTm   *ISODateTime        `xml:"CreDtTm" json:"CreDtTm"`
+	FrToDt    *DateTimePeriod1    `xml:"FrToDt,omitempty" json:"FrToDt,omitempty"`
+	Acct      *CashAccount39      `xml:"Acct" json:"Acct"`
+	Bal       []*CashBalance8     `xml:"Bal" json:"Bal"`
+	TxsSummry *TotalTransactions6 `xml:"TxsSummry,omitempty" json:"TxsSummry,omitempty"`
+	Ntry      []*ReportEntry10    `xml:"Ntry,omitempty" json:"Ntry,omitempty"`
+}
+
+type DateTimePeriod1 struct {
+	FrDtTm *ISODateTime `xml:"FrDtTm" json:"FrDtTm"`
+	ToDtTm *ISODateTime `xml:"ToDtTm" json:"ToDtTm"`
+}
+
+type CashAccount39 struct {
+	Id   *AccountIdentification4Choice                 `xml:"Id" json:"Id"`
+	Tp   *CashAccountType2Choice                       `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	Ccy  *ActiveOrHistoricCurrencyCode                 `xml:"Ccy,omitempty" json:"Ccy,omitempty"`
+	Nm   *Max70Text                                    `xml:"Nm,omitempty" json:"Nm,omitempty"`
+	Prxy *ProxyAccountIdentification1                  `xml:"Prxy,omitempty" json:"Prxy,omitempty"`
+	Ownr *PartyIdentification135                       `xml:"Ownr,omitempty" json:"Ownr,omitempty"`
+	Svcr *BranchAndFinancialInstitutionIdentification6 `xml:"Svcr,omitempty" json:"Svcr,omitempty"`
+}
+
+type CashBalance8 struct {
+	Tp        *BalanceType13                     `xml:"Tp" json:"Tp"`
+	Amt       *ActiveOrHistoricCurrencyAndAmount `xml:"Amt" json:"Amt"`
+	CdtDbtInd *CreditDebitCode                   `xml:"CdtDbtInd" json:"CdtDbtInd"`
+	Dt        *DateAndDateTime2Choice            `xml:"Dt" json:"Dt"`
+}
+
+type BalanceType13 struct {
+	CdOrPrtry *BalanceType10Choice `xml:"CdOrPrtry" json:"CdOrPrtry"`
+}
+
+type BalanceType10Choice struct {
+	Cd    *ExternalBalanceType1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
+}
+
+type DateAndDateTime2Choice struct {
+	Dt   *ISODate     `xml:"Dt,omitempty" json:"Dt,omitempty"`
+	DtTm *ISODateTime `xml:"DtTm,omitempty" json:"DtTm,omitempty"`
+}
+
+type TotalTransactions6 struct {
+	TtlNtries    *NumberAndSumOfTransactions4 `xml:"TtlNtries,omitempty" json:"TtlNtries,omitempty"`
+	TtlCdtNtries *NumberAndSumOfTransactions1 `xml:"TtlCdtNtries,omitempty" json:"TtlCdtNtries,omitempty"`
+	TtlDbtNtries *NumberAndSumOfTransactions1 `xml:"TtlDbtNtries,omitempty" json:"TtlDbtNtries,omitempty"`
+}
+
+type NumberAndSumOfTransactions4 struct {
+	NbOfNtries *Max15NumericText     `xml:"NbOfNtries,omitempty" json:"NbOfNtries,omitempty"`
+	Sum        *DecimalNumber        `xml:"Sum,omitempty" json:"Sum,omitempty"`
+	TtlNetNtry *AmountAndDirection35 `xml:"TtlNetNtry,omitempty" json:"TtlNetNtry,omitempty"`
+}
+
+type AmountAndDirection35 struct {
+	Amt       *DecimalNumber   `xml:"Amt" json:"Amt"`
+	CdtDbtInd *CreditDebitCode `xml:"CdtDbtInd" json:"CdtDbtInd"`
+}
+
+type NumberAndSumOfTransactions1 struct {
+	NbOfNtries *Max15NumericText `xml:"NbOfNtries,omitempty" json:"NbOfNtries,omitempty"`
+	Sum        *DecimalNumber    `xml:"Sum,omitempty" json:"Sum,omitempty"`
+}
+
+type ReportEntry10 struct {
+	NtryRef     *Max35Text                         `xml:"NtryRef,omitempty" json:"NtryRef,omitempty"`
+	Amt         *ActiveOrHistoricCurrencyAndAmount `xml:"Amt" json:"Amt"`
+	CdtDbtInd   *CreditDebitCode                   `xml:"CdtDbtInd" json:"CdtDbtInd"`
+	RvslInd     bool                               `xml:"RvslInd,omitempty" json:"RvslInd,omitempty"`
+	Sts         *EntryStatus1Choice                `xml:"Sts" json:"Sts"`
+	BookgDt     *DateAndDateTime2Choice            `xml:"BookgDt,omitempty" json:"BookgDt,omitempty"`
+	ValDt       *DateAndDateTime2Choice            `xml:"ValDt,omitempty" json:"ValDt,omitempty"`
+	AcctSvcrRef *Max35Text                         `xml:"AcctSvcrRef,omitempty" json:"AcctSvcrRef,omitempty"`
+	BkTxCd      *BankTransactionCodeStructure4     `xml:"BkTxCd" json:"BkTxCd"`
+	NtryDtls    []*EntryDetails9                   `xml:"NtryDtls,omitempty" json:"NtryDtls,omitempty"`
+}
+
+type EntryStatus1Choice struct {
+	Cd    *ExternalEntryStatus1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
+}
+
+type BankTransactionCodeStructure4 struct {
+	Domn *BankTransactionCodeStructure5 `xml:"Domn,omitempty" json:"Domn,omitempty"`
+}
+
+type BankTransactionCodeStructure5 struct {
+	Cd   *ExternalBankTransactionDomain1Code `xml:"Cd" json:"Cd"`
+	Fmly *BankTransactionCodeStructure6      `xml:"Fmly" json:"Fmly"`
+}
+
+type BankTransactionCodeStructure6 struct {
+	Cd        *ExternalBankTransactionFamily1Code    `xml:"Cd" json:"Cd"`
+	SubFmlyCd *ExternalBankTransactionSubFamily1Code `xml:"SubFmlyCd" json:"SubFmlyCd"`
+}
+
+type EntryDetails9 struct {
+	TxDtls []*EntryTransaction10 `xml:"TxDtls,omitempty" json:"TxDtls,omitempty"`
+}
+
+type EntryTransaction10 struct {
+	Refs      *TransactionReferences6            `xml:"Refs,omitempty" json:"Refs,omitempty"`
+	Amt       *ActiveOrHistoricCurrencyAndAmount `xml:"Amt,omitempty" json:"Amt,omitempty"`
+	CdtDbtInd *CreditDebitCode                   `xml:"CdtDbtInd,omitempty" json:"CdtDbtInd,omitempty"`
+	RltdPties *TransactionParties6               `xml:"RltdPties,omitempty" json:"RltdPties,omitempty"`
+	RltdAgts  *TransactionAgents5                `xml:"RltdAgts,omitempty" json:"RltdAgts,omitempty"`
+	Purp      *Purpose2Choice                    `xml:"Purp,omitempty" json:"Purp,omitempty"`
+	RmtInf    *RemittanceInformation16           `xml:"RmtInf,omitempty" json:"RmtInf,omitempty"`
+	RltdDts   *TransactionDates3                 `xml:"RltdDts,omitempty" json:"RltdDts,omitempty"`
+}
+
+type TransactionReferences6 struct {
+	MsgId       *Max35Text        `xml:"MsgId,omitempty" json:"MsgId,omitempty"`
+	AcctSvcrRef *Max35Text        `xml:"AcctSvcrRef,omitempty" json:"AcctSvcrRef,omitempty"`
+	InstrId     *Max35Text        `xml:"InstrId,omitempty" json:"InstrId,omitempty"`
+	EndToEndId  *Max35Text        `xml:"EndToEndId,omitempty" json:"EndToEndId,omitempty"`
+	UETR        *UUIDv4Identifier `xml:"UETR,omitempty" json:"UETR,omitempty"`
+	TxId        *Max35Text        `xml:"TxId,omitempty" json:"TxId,omitempty"`
+}
+
+type TransactionParties6 struct {
+	InitgPty  *Party40Choice `xml:"InitgPty,omitempty" json:"InitgPty,omitempty"`
+	Dbtr      *Party40Choice `xml:"Dbtr,omitempty" json:"Dbtr,omitempty"`
+	DbtrAcct  *CashAccount38 `xml:"DbtrAcct,omitempty" json:"DbtrAcct,omitempty"`
+	UltmtDbtr *Party40Choice `xml:"UltmtDbtr,omitempty" json:"UltmtDbtr,omitempty"`
+	Cdtr      *Party40Choice `xml:"Cdtr,omitempty" json:"Cdtr,omitempty"`
+	CdtrAcct  *CashAccount38 `xml:"CdtrAcct,omitempty" json:"CdtrAcct,omitempty"`
+	UltmtCdtr *Party40Choice `xml:"UltmtCdtr,omitempty" json:"UltmtCdtr,omitempty"`
+}
+
+type TransactionAgents5 struct {
+	InstgAgt   *BranchAndFinancialInstitutionIdentification6 `xml:"InstgAgt,omitempty" json:"InstgAgt,omitempty"`
+	InstdAgt   *BranchAndFinancialInstitutionIdentification6 `xml:"InstdAgt,omitempty" json:"InstdAgt,omitempty"`
+	DbtrAgt    *BranchAndFinancialInstitutionIdentification6 `xml:"DbtrAgt,omitempty" json:"DbtrAgt,omitempty"`
+	CdtrAgt    *BranchAndFinancialInstitutionIdentification6 `xml:"CdtrAgt,omitempty" json:"CdtrAgt,omitempty"`
+	IntrmyAgt1 *BranchAndFinancialInstitutionIdentification6 `xml:"IntrmyAgt1,omitempty" json:"IntrmyAgt1,omitempty"`
+	IntrmyAgt2 *BranchAndFinancialInstitutionIdentification6 `xml:"IntrmyAgt2,omitempty" json:"IntrmyAgt2,omitempty"`
+	IntrmyAgt3 *BranchAndFinancialInstitutionIdentification6 `xml:"IntrmyAgt3,omitempty" json:"IntrmyAgt3,omitempty"`
+}
+
+type TransactionDates3 struct {
+	AccptncDtTm   *ISODateTime `xml:"AccptncDtTm,omitempty" json:"AccptncDtTm,omitempty"`
+	IntrBkSttlmDt *ISODate     `xml:"IntrBkSttlmDt,omitempty" json:"IntrBkSttlmDt,omitempty"`
+}
+
+// ExternalBalanceType1Code May be no more than 4 items long
+type ExternalBalanceType1Code string
+
+func (v ExternalBalanceType1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
+// ExternalEntryStatus1Code May be no more than 4 items long
+type ExternalEntryStatus1Code string
+
+func (v ExternalEntryStatus1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
+// ExternalBankTransactionDomain1Code May be no more than 4 items long
+type ExternalBankTransactionDomain1Code string
+
+func (v ExternalBankTransactionDomain1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
+// ExternalBankTransactionFamily1Code May be no more than 4 items long
+type ExternalBankTransactionFamily1Code string
+
+func (v ExternalBankTransactionFamily1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
+// ExternalBankTransactionSubFamily1Code May be no more than 4 items long
+type ExternalBankTransactionSubFamily1Code string
+
+func (v ExternalBankTransactionSubFamily1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
+// StatementAccount is the account a statement or notification reports on, as it appeared in its latest transaction
+// Owner and servicer are the creditor and creditor agent, or debtor and debtor agent, of that transaction
+type StatementAccount struct {
+	Account  *CashAccount38
+	Owner    *PartyIdentification135
+	Servicer *BranchAndFinancialInstitutionIdentification6
+	Currency string
+}
+
+// StatementEntry is a booked transaction of an account: a pacs.008 transaction and the side of it the account is on
+type StatementEntry struct {
+	MsgId       string
+	Tx          *CreditTransferTransaction43
+	CdtDbtInd   CreditDebitCode
+	BookingDate time.Time
+}
+
+// Return the account of tx on side indicator: the creditor account when it is credited, the debtor account when debited
+// Currency is the currency of the account, or else of the transaction
+func transactionAccount(tx *CreditTransferTransaction43, indicator CreditDebitCode) StatementAccount {
+	account := StatementAccount{Account: tx.CdtrAcct, Owner: tx.Cdtr, Servicer: tx.CdtrAgt}
+	if indicator == "DBIT" {
+		account = StatementAccount{Account: tx.DbtrAcct, Owner: tx.Dbtr, Servicer: tx.DbtrAgt}
+	}
+	if account.Account != nil && account.Account.Ccy != nil {
+		account.Currency = string(*account.Account.Ccy)
+	} else if tx.IntrBkSttlmAmt != nil && tx.IntrBkSttlmAmt.Ccy != nil {
+		account.Currency = string(*tx.IntrBkSttlmAmt.Ccy)
+	}
+	return account
+}
+
+// Build camt.053 statement of account for date, holding every entry booked that day between the opening and closing
+// balances, which may be negative
+// The statement is sent by the account servicer to the account owner
+func NewStatement(account StatementAccount, date time.Time, opening Decimal, closing Decimal, entries []StatementEntry) BusMsg {
+	now := ISODateTime(time.Now().UTC().Truncate(time.Millisecond))
+	id := Max35Text(newMessageId())
+	day := ISODate(date)
+
+	statement := BusMsg{AppHdr: accountHeader(account, "camt.053.001.08", id, now)}
+
+	from, to := ISODateTime(date), ISODateTime(date.AddDate(0, 0, 1).Add(-time.Second))
+	stmt := &AccountStatement9{
+		Id:      &id,
+		CreDtTm: &now,
+		FrToDt:  &DateTimePeriod1{FrDtTm: &from, ToDtTm: &to},
+		Acct:    statementAccount(account),
+		Bal: []*CashBalance8{
+			newBalance("OPBD", opening, account.Currency, day),
+			newBalance("CLBD", closing, account.Currency, day),
+		},
+	}
+
+	stmt.Ntry = newEntries(entries)
+	stmt.TxsSummry = entriesSummary(stmt.Ntry)
+
+	statement.Document.BkToCstmrStmt = &BankToCustomerStatementV08{
+		GrpHdr: &GroupHeader81{MsgId: &id, CreDtTm: &now, MsgRcpt: account.Owner},
+		Stmt:   []*AccountStatement9{stmt},
+	}
+	return statement
+}
+
+// Return net amount of entries: credits less debits
+func entriesNet(entries []StatementEntry) Decimal {
+	net := Decimal{}
+	for _, entry := range entries {
+		if entry.Tx.IntrBkSttlmAmt == nil {
+			continue
+		}
+		if entry.CdtDbtInd == "CRDT" {
+			net = net.Add(entry.Tx.IntrBkSttlmAmt.Value)
+		} else {
+			net = net.Sub(entry.Tx.IntrBkSttlmAmt.Value)
+		}
+	}
+	return net
+}
+
+// Build entries numbered from 1
+func newEntries(entries []StatementEntry) []*ReportEntry10 {
+	var ntries []*ReportEntry10
+	for i, entry := range entries {
+		ntry := newEntry(entry)
+		ntry.NtryRef = optionalText(strconv.Itoa(i + 1))
+		ntries = append(ntries, ntry)
+	}
+	return ntries
+}
+
+// Return number and sum of credit and debit entries, and their net amount
+func entriesSummary(ntries []*ReportEntry10) *TotalTransactions6 {
+	credits, debits := Decimal{}, Decimal{}
+	nbOfCredits, nbOfDebits := 0, 0
+	for _, ntry := range ntries {
+		if *ntry.CdtDbtInd == "CRDT" {
+			credits, nbOfCredits = credits.Add(ntry.Amt.Value), nbOfCredits+1
+		} else {
+			debits, nbOfDebits = debits.Add(ntry.Amt.Value), nbOfDebits+1
+		}
+	}
+
+	net := credits.Sub(debits)
+	direction := CreditDebitCode("CRDT")
+	if net.Sign() < 0 {
+		direction = "DBIT"
+	}
+	netAmount, sum := DecimalNumber(net.Abs()), DecimalNumber(credits.Add(debits))
+	return &TotalTransactions6{
+		TtlNtries:    &NumberAndSumOfTransactions4{NbOfNtries: entryCount(len(ntries)), Sum: &sum, TtlNetNtry: &AmountAndDirection35{Amt: &netAmount, CdtDbtInd: &direction}},
+		TtlCdtNtries: &NumberAndSumOfTransactions1{NbOfNtries: entryCount(nbOfCredits), Sum: (*DecimalNumber)(&credits)},
+		TtlDbtNtries: &NumberAndSumOfTransactions1{NbOfNtries: entryCount(nbOfDebits), Sum: (*DecimalNumber)(&debits)},
+	}
+}
+
+// Build header of a message from the servicer of account to its owner
+func accountHeader(account StatementAccount, msgDefIdr string, id Max35Text, now ISODateTime) AppHdr {
+	header := AppHdr{BizMsgIdr: id, MsgDefIdr: Max35Text(msgDefIdr), CreDt: now}
+	if account.Servicer != nil {
+		header.Fr = &Party44Choice{FIId: account.Servicer}
+	}
+	if account.Owner != nil {
+		header.To = &Party44Choice{OrgId: account.Owner}
+	}
+	return header
+}
+
+// Return account element of a statement or notification, in the currency it reports on
+func statementAccount(account StatementAccount) *CashAccount39 {
+	acct := &CashAccount39{Ownr: account.Owner, Svcr: account.Servicer}
+	if a := account.Account; a != nil {
+		acct.Id, acct.Tp, acct.Nm, acct.Prxy = a.Id, a.Tp, a.Nm, a.Prxy
+	}
+	ccy := ActiveOrHistoricCurrencyCode(account.Currency)
+	acct.Ccy = &ccy
+	return acct
+}
+
+// Build balance of type code, a negative amount is a debit balance
+func newBalance(code string, amount Decimal, currency string, date ISODate) *CashBalance8 {
+	balanceType := ExternalBalanceType1Code(code)
+	indicator := CreditDebitCode("CRDT")
+	if amount.Sign() < 0 {
+		indicator = "DBIT"
+	}
+	return &CashBalance8{
+		Tp:        &BalanceType13{CdOrPrtry: &BalanceType10Choice{Cd: &balanceType}},
+		Amt:       currencyAmount(amount.Abs(), currency),
+		CdtDbtInd: &indicator,
+		Dt:        &DateAndDateTime2Choice{Dt: &date},
+	}
+}
+
+// Build booked entry of a credit transfer, its details carry the references, parties, agents and remittance
+// information of the transfer
+func newEntry(entry StatementEntry) *ReportEntry10 {
+	tx := entry.Tx
+	date := ISODate(entry.BookingDate)
+	indicator := entry.CdtDbtInd
+	status := ExternalEntryStatus1Code("BOOK")
+	domain, subFamily := ExternalBankTransactionDomain1Code("PMNT"), ExternalBankTransactionSubFamily1Code("OTHR")
+	family := ExternalBankTransactionFamily1Code("RCDT")
+	if indicator == "DBIT" {
+		family = "ICDT"
+	}
+
+	var amount *ActiveOrHistoricCurrencyAndAmount
+	if tx.IntrBkSttlmAmt != nil {
+		ccy := ""
+		if tx.IntrBkSttlmAmt.Ccy != nil {
+			ccy = string(*tx.IntrBkSttlmAmt.Ccy)
+		}
+		amount = currencyAmount(tx.IntrBkSttlmAmt.Value, ccy)
+	}
+
+	details := &EntryTransaction10{
+		Refs:      &TransactionReferences6{MsgId: optionalText(entry.MsgId)},
+		Amt:       amount,
+		CdtDbtInd: &indicator,
+		RltdPties: &TransactionParties6{
+			InitgPty:  partyChoice(tx.InitgPty),
+			Dbtr:      partyChoice(tx.Dbtr),
+			DbtrAcct:  tx.DbtrAcct,
+			UltmtDbtr: partyChoice(tx.UltmtDbtr),
+			Cdtr:      partyChoice(tx.Cdtr),
+			CdtrAcct:  tx.CdtrAcct,
+			UltmtCdtr: partyChoice(tx.UltmtCdtr),
+		},
+		RltdAgts: &TransactionAgents5{
+			InstgAgt:   tx.InstgAgt,
+			InstdAgt:   tx.InstdAgt,
+			DbtrAgt:    tx.DbtrAgt,
+			CdtrAgt:    tx.CdtrAgt,
+			IntrmyAgt1: tx.IntrmyAgt1,
+			IntrmyAgt2: tx.IntrmyAgt2,
+			IntrmyAgt3: tx.IntrmyAgt3,
+		},
+		Purp:    tx.Purp,
+		RmtInf:  tx.RmtInf,
+		RltdDts: &TransactionDates3{AccptncDtTm: tx.AccptncDtTm, IntrBkSttlmDt: &date},
+	}
+	if id := tx.PmtId; id != nil {
+		details.Refs.InstrId, details.Refs.EndToEndId, details.Refs.TxId, details.Refs.UETR = id.InstrId, id.EndToEndId, id.TxId, id.UETR
+	}
+
+	return &ReportEntry10{
+		Amt:       amount,
+		CdtDbtInd: &indicator,
+		Sts:       &EntryStatus1Choice{Cd: &status},
+		BookgDt:   &DateAndDateTime2Choice{Dt: &date},
+		ValDt:     &DateAndDateTime2Choice{Dt: &date},
+		BkTxCd: &BankTransactionCodeStructure4{Domn: &BankTransactionCodeStructure5{
+			Cd:   &domain,
+			Fmly: &BankTransactionCodeStructure6{Cd: &family, SubFmlyCd: &subFamily},
+		}},
+		NtryDtls: []*EntryDetails9{{TxDtls: []*EntryTransaction10{details}}},
+	}
+}
+
+// Return amount in currency
+func currencyAmount(value Decimal, currency string) *ActiveOrHistoricCurrencyAndAmount {
+	ccy := ActiveOrHistoricCurrencyCode(currency)
+	return &ActiveOrHistoricCurrencyAndAmount{Value: value, Ccy: &ccy}
+}
+
+// Return party as a party choice, nil when it is missing
+func partyChoice(party *PartyIdentification135) *Party40Choice {
+	if party == nil {
+		return nil
+	}
+	return &Party40Choice{Pty: party}
+}
+
+// Return number of entries as text
+func entryCount(n int) *Max15NumericText {
+	count := Max15NumericText(strconv.Itoa(n))
+	return &count
+}
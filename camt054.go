@@ -0,0 +1,47 @@
+package main
+
+import (
+	"time"
+)
+
+// Bank to customer debit credit notification (camt.054.001.08)
+// Entries share the camt.053 model, pagination and interest are left out as for statements
+
+const camt054Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.054.001.08"
+
+type BankToCustomerDebitCreditNotificationV08 struct {
+	GrpHdr      *GroupHeader81           `xml:"GrpHdr" json:"GrpHdr"`
+	Ntfctn      []*AccountNotification17 `xml:"Ntfctn" json:"Ntfctn"`
+	SplmtryData []*SupplementaryData1    `xml:"SplmtryData,omitempty" json:"SplmtryData,omitempty"`
+}
+
+type AccountNotification17 struct {
+	Id        *Max35Text          `xml:"Id" json:"Id"`
+	CreDtTm   *ISODateTime        `xml:"CreDtTm" json:"CreDtTm"`
+	Acct      *CashAccount39      `xml:"Acct" json:"Acct"`
+	TxsSummry *TotalTransactions6 `xml:"TxsSummry,omitempty" json:"TxsSummry,omitempty"`
+	Ntry      []*ReportEntry10    `xml:"Ntry,omitempty" json:"Ntry,omitempty"`
+}
+
+// Build camt.054 notifying the owner of account of entries, every entry being on the same side of the account
+// The notification is sent by the account servicer to the account owner
+func NewNotification(account StatementAccount, entries []StatementEntry) BusMsg {
+	now := ISODateTime(time.Now().UTC().Truncate(time.Millisecond))
+	id := Max35Text(newMessageId())
+
+	notification := BusMsg{AppHdr: accountHeader(account, "camt.054.001.08", id, now)}
+
+	ntfctn := &AccountNotification17{
+		Id:      &id,
+		CreDtTm: &now,
+		Acct:    statementAccount(account),
+		Ntry:    newEntries(entries),
+	}
+	ntfctn.TxsSummry = entriesSummary(ntfctn.Ntry)
+
+	notification.Document.BkToCstmrDbtCdtNtfctn = &BankToCustomerDebitCreditNotificationV08{
+		GrpHdr: &GroupHeader81{MsgId: &id, CreDtTm: &now, MsgRcpt: account.Owner},
+		Ntfctn: []*AccountNotification17{ntfctn},
+	}
+	return notification
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// FIToFI payment cancellation request (camt.056.001.08)
+// Cancellations of payment initiations (OrgnlPmtInfAndCxl) and original transaction references are not modelled
+
+const camt056Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.056.001.08"
+
+type FIToFIPaymentCancellationRequestV08 struct {
+	Assgnmt     *CaseAssignment5           `xml:"Assgnmt" json:"Assgnmt"`
+	Case        *Case5                     `xml:"Case,omitempty" json:"Case,omitempty"`
+	CtrlData    *ControlData1              `xml:"CtrlData,omitempty" json:"CtrlData,omitempty"`
+	Undrlyg     []*UnderlyingTransaction23 `xml:"Undrlyg" json:"Undrlyg"`
+	SplmtryData []*SupplementaryData1      `xml:"SplmtryData,omitempty" json:"SplmtryData,omitempty"`
+}
+
+type CaseAssignment5 struct {
+	Id      *Max35Text     `xml:"Id" json:"Id"`
+	Assgnr  *Party40Choice `xml:"Assgnr" json:"Assgnr"`
+	Assgne  *Party40Choice `xml:"Assgne" json:"Assgne"`
+	CreDtTm *ISODateTime   `xml:"CreDtTm" json:"CreDtTm"`
+}
+
+type Case5 struct {
+	Id             *Max35Text     `xml:"Id" json:"Id"`
+	Cretr          *Party40Choice `xml:"Cretr" json:"Cretr"`
+	ReopCaseIndctn bool           `xml:"ReopCaseIndctn,omitempty" json:"ReopCaseIndctn,omitempty"`
+}
+
+type Party40Choice struct {
+	Pty *PartyIdentification135                       `xml:"Pty,omitempty" json:"Pty,omitempty"`
+	Agt *BranchAndFinancialInstitutionIdentification6 `xml:"Agt,omitempty" json:"Agt,omitempty"`
+}
+
+type ControlData1 struct {
+	NbOfTxs *Max15NumericText `xml:"NbOfTxs" json:"NbOfTxs"`
+	CtrlSum *DecimalNumber    `xml:"CtrlSum,omitempty" json:"CtrlSum,omitempty"`
+}
+
+type UnderlyingTransaction23 struct {
+	OrgnlGrpInfAndCxl *OriginalGroupHeader15   `xml:"OrgnlGrpInfAndCxl,omitempty" json:"OrgnlGrpInfAndCxl,omitempty"`
+	TxInf             []*PaymentTransaction106 `xml:"TxInf,omitempty" json:"TxInf,omitempty"`
+}
+
+type OriginalGroupHeader15 struct {
+	GrpCxlId     *Max35Text                    `xml:"GrpCxlId,omitempty" json:"GrpCxlId,omitempty"`
+	Case         *Case5                        `xml:"Case,omitempty" json:"Case,omitempty"`
+	OrgnlMsgId   *Max35Text                    `xml:"OrgnlMsgId" json:"OrgnlMsgId"`
+	OrgnlMsgNmId *Max35Text                    `xml:"OrgnlMsgNmId" json:"OrgnlMsgNmId"`
+	OrgnlCreDtTm *ISODateTime                  `xml:"OrgnlCreDtTm,omitempty" json:"OrgnlCreDtTm,omitempty"`
+	NbOfTxs      *Max15NumericText             `xml:"NbOfTxs,omitempty" json:"NbOfTxs,omitempty"`
+	CtrlSum      *DecimalNumber                `xml:"CtrlSum,omitempty" json:"CtrlSum,omitempty"`
+	GrpCxl       bool                          `xml:"GrpCxl,omitempty" json:"GrpCxl,omitempty"`
+	CxlRsnInf    []*PaymentCancellationReason5 `xml:"CxlRsnInf,omitempty" json:"CxlRsnInf,omitempty"`
+}
+
+type PaymentTransaction106 struct {
+	CxlId               *Max35Text                         `xml:"CxlId,omitempty" json:"CxlId,omitempty"`
+	Case                *Case5                             `xml:"Case,omitempty" json:"Case,omitempty"`
+	OrgnlGrpInf         *OriginalGroupInformation29        `xml:"OrgnlGrpInf,omitempty" json:"OrgnlGrpInf,omitempty"`
+	OrgnlInstrId        *Max35Text                         `xml:"OrgnlInstrId,omitempty" json:"OrgnlInstrId,omitempty"`
+	OrgnlEndToEndId     *Max35Text                         `xml:"OrgnlEndToEndId,omitempty" json:"OrgnlEndToEndId,omitempty"`
+	OrgnlTxId           *Max35Text                         `xml:"OrgnlTxId,omitempty" json:"OrgnlTxId,omitempty"`
+	OrgnlUETR           *UUIDv4Identifier                  `xml:"OrgnlUETR,omitempty" json:"OrgnlUETR,omitempty"`
+	OrgnlClrSysRef      *Max35Text                         `xml:"OrgnlClrSysRef,omitempty" json:"OrgnlClrSysRef,omitempty"`
+	OrgnlIntrBkSttlmAmt *ActiveOrHistoricCurrencyAndAmount `xml:"OrgnlIntrBkSttlmAmt,omitempty" json:"OrgnlIntrBkSttlmAmt,omitempty"`
+	OrgnlIntrBkSttlmDt  *ISODate                           `xml:"OrgnlIntrBkSttlmDt,omitempty" json:"OrgnlIntrBkSttlmDt,omitempty"`
+	Assgnr              *Party40Choice                     `xml:"Assgnr,omitempty" json:"Assgnr,omitempty"`
+	Assgne              *Party40Choice                     `xml:"Assgne,omitempty" json:"Assgne,omitempty"`
+	CxlRsnInf           []*PaymentCancellationReason5      `xml:"CxlRsnInf,omitempty" json:"CxlRsnInf,omitempty"`
+	SplmtryData         []*SupplementaryData1              `xml:"SplmtryData,omitempty" json:"SplmtryData,omitempty"`
+}
+
+type PaymentCancellationReason5 struct {
+	Orgtr    *PartyIdentification135     `xml:"Orgtr,omitempty" json:"Orgtr,omitempty"`
+	Rsn      *CancellationReason33Choice `xml:"Rsn,omitempty" json:"Rsn,omitempty"`
+	AddtlInf []*Max105Text               `xml:"AddtlInf,omitempty" json:"AddtlInf,omitempty"`
+}
+
+type CancellationReason33Choice struct {
+	Cd    *ExternalCancellationReason1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                       `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
+}
+
+// ExternalCancellationReason1Code May be no more than 4 items long
+type ExternalCancellationReason1Code string
+
+func (v ExternalCancellationReason1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
+// Return case identifier of a cancellation request: its Case, or else its assignment
+func (req *FIToFIPaymentCancellationRequestV08) caseId() string {
+	if req.Case != nil && req.Case.Id != nil {
+		return string(*req.Case.Id)
+	}
+	if req.Assgnmt != nil {
+		return textValue(req.Assgnmt.Id)
+	}
+	return ""
+}
+
+// Check CtrlData against the cancelled transactions
+func validateCancellation(msg BusMsg) ValidationErrors {
+	var errs ValidationErrors
+	req := msg.Document.FIToFIPmtCxlReq
+	if req == nil || req.CtrlData == nil || req.CtrlData.NbOfTxs == nil {
+		return errs
+	}
+
+	count := 0
+	for _, underlying := range req.Undrlyg {
+		if underlying != nil {
+			count += len(underlying.TxInf)
+		}
+	}
+	if n, err := strconv.Atoi(string(*req.CtrlData.NbOfTxs)); err == nil && n != count {
+		errs = append(errs, ruleError("/Document/FIToFIPmtCxlReq/CtrlData/NbOfTxs", reasonInvalidNumberOfTxs,
+			"NbOfTxs is %d but request cancels %d transactions", n, count))
+	}
+	return errs
+}
+
+// Open an investigation case for msg, a cancellation request, linking every cancelled transaction to
+// a stored pacs.008 transaction found by OrgnlUETR or by OrgnlMsgId and OrgnlEndToEndId
+// A transaction with a pending cancellation, in a state it can not be cancelled from or listed twice can not be cancelled
+// A request without any transaction opens no case
+func handleCancellation(finder MessageFinder, msg *StoredMessage) (ValidationErrors, error) {
+	var errs ValidationErrors
+	req := msg.Message.Document.FIToFIPmtCxlReq
+	if req == nil {
+		return errs, nil
+	}
+
+	const root = "/Document/FIToFIPmtCxlReq"
+	investigation := &InvestigationCase{
+		Id:        req.caseId(),
+		Status:    caseStatusPending,
+		CreatedAt: msg.ReceivedAt,
+	}
+	if investigation.Id == "" {
+		errs = append(errs, ruleError(root+"/Assgnmt/Id", reasonMissingElement, "Case/Id or Assgnmt/Id is required to open a case"))
+		return errs, nil
+	}
+	existing, err := finder.FindCase(investigation.Id)
+	if err != nil && !errors.Is(err, ErrCaseNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		errs = append(errs, ruleError(root+"/Case/Id", reasonDuplication, "case %s was already opened by message %s", investigation.Id, existing.MessageID))
+		return errs, nil
+	}
+
+	seq := 0
+	requested := make(map[TransactionRef]string) // location of each transaction already in the case
+	for u, underlying := range req.Undrlyg {
+		if underlying == nil {
+			continue
+		}
+		var groupMsgId, groupMsgNmId *Max35Text
+		if group := underlying.OrgnlGrpInfAndCxl; group != nil {
+			groupMsgId, groupMsgNmId = group.OrgnlMsgId, group.OrgnlMsgNmId
+		}
+
+		for i, tx := range underlying.TxInf {
+			if tx == nil {
+				continue
+			}
+			location := fmt.Sprintf("%s/Undrlyg[%d]/TxInf[%d]", root, u, i)
+			cancelled := CaseTransaction{
+				Seq:             seq,
+				CxlId:           textValue(tx.CxlId),
+				OrgnlMsgId:      textValue(groupMsgId),
+				OrgnlMsgNmId:    textValue(groupMsgNmId),
+				OrgnlEndToEndId: textValue(tx.OrgnlEndToEndId),
+				OrgnlTxId:       textValue(tx.OrgnlTxId),
+			}
+			seq++
+			if tx.OrgnlGrpInf != nil {
+				cancelled.OrgnlMsgId = textValue(tx.OrgnlGrpInf.OrgnlMsgId)
+				cancelled.OrgnlMsgNmId = textValue(tx.OrgnlGrpInf.OrgnlMsgNmId)
+			}
+			if tx.OrgnlUETR != nil {
+				cancelled.OrgnlUETR = string(*tx.OrgnlUETR)
+			}
+			if cancelled.OrgnlUETR == "" && (cancelled.OrgnlMsgId == "" || cancelled.OrgnlEndToEndId == "") {
+				errs = append(errs, ruleError(location, reasonMissingElement,
+					"OrgnlUETR, or OrgnlMsgId and OrgnlEndToEndId, are required to find the cancelled transaction"))
+				continue
+			}
+
+			original, err := finder.FindTransaction(cancelled.OrgnlMsgId, cancelled.OrgnlEndToEndId, cancelled.OrgnlUETR)
+			if err != nil {
+				return nil, err
+			}
+			if original == nil {
+				errs = append(errs, ruleError(location, reasonUnknownOriginal, "no pacs.008 transaction %s was received", cancelled.reference()))
+				continue
+			}
+			if !canTransition(original.State, stateCancelled) {
+				errs = append(errs, transitionError(location, original, stateCancelled))
+				continue
+			}
+			cancelled.Original = TransactionRef{MessageID: original.MessageID, Seq: original.Seq}
+			cancelled.OrgnlMsgId, cancelled.OrgnlEndToEndId, cancelled.OrgnlTxId, cancelled.OrgnlUETR =
+				original.MsgId, original.EndToEndId, original.TxId, original.UETR
+
+			if earlier, ok := requested[cancelled.Original]; ok {
+				errs = append(errs, ruleError(location, reasonDuplication, "cancellation of %s is already requested at %s", cancelled.reference(), earlier))
+				continue
+			}
+			pending, err := finder.FindPendingCase(cancelled.Original)
+			if err != nil {
+				return nil, err
+			}
+			if pending != nil {
+				errs = append(errs, ruleError(location, reasonDuplication, "cancellation of %s is already pending in case %s", cancelled.reference(), pending.Id))
+				continue
+			}
+			investigation.Transactions = append(investigation.Transactions, cancelled)
+			requested[cancelled.Original] = location
+		}
+	}
+	if seq == 0 {
+		errs = append(errs, ruleError(root+"/Undrlyg", reasonMissingElement, "cancellation request references no transactions"))
+		return errs, nil
+	}
+
+	msg.Case = investigation
+	return errs, nil
+}
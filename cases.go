@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Investigation case states, as camt.029 confirmation codes
+const (
+	caseStatusPending   = "PDCR"
+	caseStatusCancelled = "CNCL"
+	caseStatusRejected  = "RJCR"
+)
+
+// InvestigationCase is a cancellation request and its resolution
+// MessageID is the camt.056 opening the case, ResolutionID the camt.029 resolving it
+type InvestigationCase struct {
+	XMLName      xml.Name          `xml:"Case" json:"-"`
+	Id           string            `xml:"Id" json:"Id"`
+	MessageID    string            `xml:"MessageId" json:"MessageId"`
+	Status       string            `xml:"Status" json:"Status"`
+	Reason       string            `xml:"Reason,omitempty" json:"Reason,omitempty"` // rejection reason
+	CreatedAt    time.Time         `xml:"CreatedAt" json:"CreatedAt"`
+	ResolvedAt   *time.Time        `xml:"ResolvedAt,omitempty" json:"ResolvedAt,omitempty"`
+	ResolutionID string            `xml:"ResolutionId,omitempty" json:"ResolutionId,omitempty"`
+	Transactions []CaseTransaction `xml:"Transaction" json:"Transactions"`
+}
+
+// CaseTransaction is a transaction a case asks to cancel, Original is the stored pacs.008 transaction
+type CaseTransaction struct {
+	Seq             int            `xml:"Seq" json:"Seq"`
+	CxlId           string         `xml:"CxlId,omitempty" json:"CxlId,omitempty"`
+	OrgnlMsgId      string         `xml:"OrgnlMsgId,omitempty" json:"OrgnlMsgId,omitempty"`
+	OrgnlMsgNmId    string         `xml:"OrgnlMsgNmId,omitempty" json:"OrgnlMsgNmId,omitempty"`
+	OrgnlEndToEndId string         `xml:"OrgnlEndToEndId,omitempty" json:"OrgnlEndToEndId,omitempty"`
+	OrgnlTxId       string         `xml:"OrgnlTxId,omitempty" json:"OrgnlTxId,omitempty"`
+	OrgnlUETR       string         `xml:"OrgnlUETR,omitempty" json:"OrgnlUETR,omitempty"`
+	Original        TransactionRef `xml:"Original" json:"Original"`
+}
+
+// Return how the cancelled transaction is referred to in issues
+func (tx CaseTransaction) reference() string {
+	return transactionReference(tx.OrgnlMsgId, tx.OrgnlEndToEndId, tx.OrgnlUETR)
+}
+
+// Return how a transaction looked up by UETR, or else by MsgId and EndToEndId, is referred to in issues
+func transactionReference(msgId string, endToEndId string, uetr string) string {
+	if uetr != "" {
+		return "with UETR " + uetr
+	}
+	return fmt.Sprintf("%s in message %s", endToEndId, msgId)
+}
+
+var ErrCaseNotFound = errors.New("case not found")
+
+// Return investigation case by its identifier
+func getCase(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ipReq := getIP(r)
+	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new Case Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
+
+	var response Response
+
+	format := acceptFormat(r)
+	id := mux.Vars(r)["id"]
+
+	c, err := finder.FindCase(id)
+	if errors.Is(err, ErrCaseNotFound) {
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Case %s not found", id)
+		log.Print(response.Message)
+		responseFormatter(w, format, response, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = "Error reading case"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
+	}
+	responseFormatter(w, format, c, http.StatusOK)
+}
+
+// Resolve a pending case with status CNCL or RJCR, and reason code when rejected
+// The camt.029 resolution is saved with the other messages and returned, CNCL moves the cancelled transactions to Cancelled
+func resolveCase(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ipReq := getIP(r)
+	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new Case Resolution Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
+
+	var response Response
+
+	format := acceptFormat(r)
+	id := mux.Vars(r)["id"]
+	status := r.URL.Query().Get("status")
+	reason := r.URL.Query().Get("reason")
+
+	switch {
+	case status != caseStatusCancelled && status != caseStatusRejected:
+		response.Message = fmt.Sprintf("Invalid status %q, %s or %s expected", status, caseStatusCancelled, caseStatusRejected)
+	case status == caseStatusRejected && ExternalPaymentCancellationRejection1Code(reason).Validate() != nil:
+		response.Message = fmt.Sprintf("Invalid reason %q, a rejection reason code is required", reason)
+	}
+	if response.Message != "" {
+		response.Status = statusRejected
+		log.Print(response.Message)
+		responseFormatter(w, format, response, http.StatusBadRequest)
+		return
+	}
+	if status == caseStatusCancelled {
+		reason = ""
+	}
+
+	// Resolve and save as one step, so a case is not resolved twice
+	ingestMu.Lock()
+	defer ingestMu.Unlock()
+
+	c, request, ok := loadCase(w, format, id)
+	if !ok {
+		return
+	}
+	if c.Status != caseStatusPending {
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Case %s is already resolved as %s", c.Id, c.Status)
+		log.Print(response.Message)
+		responseFormatter(w, format, response, http.StatusConflict)
+		return
+	}
+
+	c.Status, c.Reason, c.ResolvedAt = status, reason, &start
+	resolution := NewResolution(request, c)
+	msg := &StoredMessage{
+		BizMsgIdr:  string(resolution.AppHdr.BizMsgIdr),
+		MsgDefIdr:  string(resolution.AppHdr.MsgDefIdr),
+		MsgId:      string(*resolution.Document.RsltnOfInvstgtn.Assgnmt.Id),
+		ClientIP:   ipReq,
+		Format:     format,
+		Status:     statusAccepted,
+		ReceivedAt: start,
+		Message:    &resolution,
+		Case:       c,
+	}
+
+	// an accepted cancellation cancels every transaction of the case, unless one was since returned or rejected
+	if status == caseStatusCancelled {
+		for _, tx := range c.Transactions {
+			original, err := finder.FindPayment(tx.Original)
+			if err != nil {
+				response.Status = statusRejected
+				response.Message = "Error reading case"
+				log.Printf("%s: %s", response.Message, err.Error())
+				responseFormatter(w, format, response, http.StatusInternalServerError)
+				return
+			}
+			if original == nil || !canTransition(original.State, stateCancelled) {
+				response.Status = statusRejected
+				response.Message = fmt.Sprintf("Case %s can not be cancelled", c.Id)
+				if original != nil {
+					response.Issues = ValidationErrors{transitionError(fmt.Sprintf("/Case/Transaction[%d]", tx.Seq), original, stateCancelled)}
+				}
+				log.Print(response.Message)
+				responseFormatter(w, format, response, http.StatusConflict)
+				return
+			}
+			msg.Transitions = append(msg.Transitions, Transition{
+				Transaction: tx.Original,
+				From:        original.State,
+				To:          stateCancelled,
+				Event:       msg.MsgDefIdr,
+				At:          start,
+			})
+		}
+	}
+
+	var err error
+	if msg.Payload, err = marshalBusMsg(resolution, format); err == nil {
+		msg.Document, err = marshalIndent(resolution.Document, format)
+	}
+	if err == nil {
+		msg.Digest, err = documentDigest(resolution.Document)
+	}
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = "Error building resolution"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
+	}
+
+	if err := store.Save(msg); err != nil {
+		response.Status = statusRejected
+		response.Message = "Error saving message"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Case %s resolved as %s by %s", c.Id, c.Status, msg.ID)
+	writeBusMsg(w, format, resolution, http.StatusOK)
+}
+
+// Return camt.029 resolution of a case: the one saved when it was resolved, or a PDCR one while it is pending
+func getResolution(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ipReq := getIP(r)
+	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new Case Resolution Retrieve Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
+
+	var response Response
+
+	format := acceptFormat(r)
+	id := mux.Vars(r)["id"]
+
+	c, request, ok := loadCase(w, format, id)
+	if !ok {
+		return
+	}
+	if c.Status == caseStatusPending {
+		writeBusMsg(w, format, NewResolution(request, c), http.StatusOK)
+		return
+	}
+
+	msg, err := finder.Find(c.ResolutionID)
+	if err == nil {
+		var resolution Iso20022
+		if resolution, _, _, err = decodeMessage(msg.Payload, msg.Format); err == nil {
+			writeBusMsg(w, format, resolution.BusMsg, http.StatusOK)
+			return
+		}
+	}
+	response.Status = statusRejected
+	response.Message = fmt.Sprintf("Error reading resolution %s", c.ResolutionID)
+	log.Printf("%s: %s", response.Message, err.Error())
+	responseFormatter(w, format, response, http.StatusInternalServerError)
+}
+
+// Read case id and the cancellation request opening it, writing the error response when that fails
+func loadCase(w http.ResponseWriter, format string, id string) (*InvestigationCase, BusMsg, bool) {
+	var response Response
+
+	c, err := finder.FindCase(id)
+	if errors.Is(err, ErrCaseNotFound) {
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Case %s not found", id)
+		log.Print(response.Message)
+		responseFormatter(w, format, response, http.StatusNotFound)
+		return nil, BusMsg{}, false
+	}
+
+	var request Iso20022
+	if err == nil {
+		var msg *StoredMessage
+		if msg, err = finder.Find(c.MessageID); err == nil {
+			request, _, _, err = decodeMessage(msg.Payload, msg.Format)
+		}
+	}
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = "Error reading case"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return nil, BusMsg{}, false
+	}
+	return c, request.BusMsg, true
+}
+
+// Marshal BusMsg as a payload of format, as it would be received
+func marshalBusMsg(msg BusMsg, format string) ([]byte, error) {
+	if format == formatXML {
+		return MarshalBusMsgXML(msg)
+	}
+	return json.MarshalIndent(Iso20022{BusMsg: msg}, "", "  ")
+}
+
+// Write BusMsg generated by this service, e.g. a status report or resolution
+func writeBusMsg(w http.ResponseWriter, format string, msg BusMsg, statusCode int) {
+	if format == formatXML {
+		setHeadNamespace(&msg)
+		responseFormatter(w, format, msg, statusCode)
+		return
+	}
+	responseFormatter(w, format, Iso20022{BusMsg: msg}, statusCode)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIngestCancellation(t *testing.T) {
+	tests := []struct {
+		name       string
+		change     func(msg *BusMsg)
+		second     bool // send the cancellation twice, under another case
+		wantCode   int
+		wantIssue  ValidationError // Location and Code of the expected issue, none when empty
+		wantTxs    int
+		wantInText string
+	}{
+		{
+			name:     "accepted",
+			wantCode: http.StatusOK,
+			wantTxs:  1,
+		},
+		{
+			name: "unknown transaction",
+			change: func(msg *BusMsg) {
+				msg.Document.FIToFIPmtCxlReq.Undrlyg[0].TxInf[0].OrgnlEndToEndId = optionalText("UNKNOWN")
+			},
+			wantCode:  http.StatusBadRequest,
+			wantIssue: ValidationError{Location: "/Document/FIToFIPmtCxlReq/Undrlyg[0]/TxInf[0]", Code: reasonUnknownOriginal},
+		},
+		{
+			name: "transaction listed twice",
+			change: func(msg *BusMsg) {
+				underlying := msg.Document.FIToFIPmtCxlReq.Undrlyg[0]
+				underlying.TxInf = append(underlying.TxInf, underlying.TxInf[0])
+			},
+			wantCode:   http.StatusBadRequest,
+			wantIssue:  ValidationError{Location: "/Document/FIToFIPmtCxlReq/Undrlyg[0]/TxInf[1]", Code: reasonDuplication},
+			wantInText: "already requested at /Document/FIToFIPmtCxlReq/Undrlyg[0]/TxInf[0]",
+		},
+		{
+			name:       "transaction already pending",
+			second:     true,
+			wantCode:   http.StatusBadRequest,
+			wantIssue:  ValidationError{Location: "/Document/FIToFIPmtCxlReq/Undrlyg[0]/TxInf[0]", Code: reasonDuplication},
+			wantInText: "already pending in case 20210302INDOIDJA01012345678",
+		},
+		{
+			name: "no underlying transactions",
+			change: func(msg *BusMsg) {
+				msg.Document.FIToFIPmtCxlReq.Undrlyg = nil
+			},
+			wantCode:  http.StatusBadRequest,
+			wantIssue: ValidationError{Location: "/Document/FIToFIPmtCxlReq/Undrlyg", Code: reasonMissingElement},
+		},
+		{
+			name: "underlying without transactions",
+			change: func(msg *BusMsg) {
+				msg.Document.FIToFIPmtCxlReq.Undrlyg[0].TxInf = nil
+			},
+			wantCode:  http.StatusBadRequest,
+			wantIssue: ValidationError{Location: "/Document/FIToFIPmtCxlReq/Undrlyg", Code: reasonMissingElement},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestServer(t)
+			if code, response := postIso(t, handler, "/iso20022", samplePayload(t, func(*BusMsg) {}), nil); code != http.StatusOK {
+				t.Fatalf("pacs.008 gives %d %+v", code, response)
+			}
+			if tt.second {
+				first := messagePayload(t, "camt056.json", func(msg *BusMsg) {
+					msg.AppHdr.BizMsgIdr = "20210302INDOIDJA010CXL00000001"
+				})
+				if code, response := postIso(t, handler, "/iso20022/cancellation", first, nil); code != http.StatusOK {
+					t.Fatalf("first camt.056 gives %d %+v", code, response)
+				}
+			}
+
+			change := tt.change
+			if tt.second {
+				change = func(msg *BusMsg) {
+					msg.Document.FIToFIPmtCxlReq.Assgnmt.Id = optionalText("20210302INDOIDJA01087654321")
+				}
+			}
+			code, response := postIso(t, handler, "/iso20022/cancellation", messagePayload(t, "camt056.json", change), nil)
+			if code != tt.wantCode {
+				t.Fatalf("got %d %+v, want %d", code, response, tt.wantCode)
+			}
+			if tt.wantIssue.Code != "" {
+				found := false
+				for _, issue := range response.Issues {
+					if issue.Location == tt.wantIssue.Location && issue.Code == tt.wantIssue.Code && strings.Contains(issue.Message, tt.wantInText) {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("got issues %+v, want %s at %s", response.Issues, tt.wantIssue.Code, tt.wantIssue.Location)
+				}
+				return
+			}
+
+			var c InvestigationCase
+			if code := getJSON(t, handler, "/cases/20210302INDOIDJA01012345678", &c); code != http.StatusOK {
+				t.Fatalf("got case %d %+v", code, c)
+			}
+			if c.Status != caseStatusPending || len(c.Transactions) != tt.wantTxs {
+				t.Errorf("got case %s with %d transactions, want %s with %d", c.Status, len(c.Transactions), caseStatusPending, tt.wantTxs)
+			}
+		})
+	}
+}
+
+// Resolving a case as CNCL cancels its transactions, RJCR leaves them as they are, a case is resolved once
+func TestResolveCase(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantCode  int
+		wantCase  string
+		wantState string
+	}{
+		{"cancelled", "status=CNCL", http.StatusOK, caseStatusCancelled, stateCancelled},
+		{"rejected", "status=RJCR&reason=LEGL", http.StatusOK, caseStatusRejected, stateAccepted},
+		{"rejected without reason", "status=RJCR", http.StatusBadRequest, caseStatusPending, stateAccepted},
+		{"invalid status", "status=ACCP", http.StatusBadRequest, caseStatusPending, stateAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestServer(t)
+			_, transfer := postIso(t, handler, "/iso20022", samplePayload(t, func(*BusMsg) {}), nil)
+			if code, response := postIso(t, handler, "/iso20022/cancellation", messagePayload(t, "camt056.json", nil), nil); code != http.StatusOK {
+				t.Fatalf("camt.056 gives %d %+v", code, response)
+			}
+
+			const path = "/cases/20210302INDOIDJA01012345678"
+			resolve := func() int {
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path+"/resolution?"+tt.query, nil))
+				return w.Code
+			}
+			if code := resolve(); code != tt.wantCode {
+				t.Fatalf("got %d, want %d", code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusOK {
+				if code := resolve(); code != http.StatusConflict {
+					t.Errorf("resolving again gives %d, want %d", code, http.StatusConflict)
+				}
+			}
+
+			var c InvestigationCase
+			getJSON(t, handler, path, &c)
+			if c.Status != tt.wantCase {
+				t.Errorf("got case %s, want %s", c.Status, tt.wantCase)
+			}
+			var lifecycle MessageLifecycle
+			getJSON(t, handler, "/iso20022/"+transfer.MessageId+"/lifecycle", &lifecycle)
+			if len(lifecycle.Transactions) != 1 || lifecycle.Transactions[0].State != tt.wantState {
+				t.Errorf("got lifecycle %+v, want the transaction %s", lifecycle.Transactions, tt.wantState)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Convert a BusMsg payload from one format to the other
+// JSON payloads are wrapped in {"BusMsg": ...}, XML payloads have BusMsg as root element
+func ConvertIso(body []byte, from string, to string) ([]byte, error) {
+	out, _, err := ConvertIsoVersion(body, from, to, "")
+	return out, err
+}
+
+// Convert a BusMsg payload from one format to the other and to msgDefIdr, another version of the same message
+// Empty msgDefIdr keeps the version of the payload
+// Elements dropped or defaulted by the version conversion are reported as warnings
+func ConvertIsoVersion(body []byte, from string, to string, msgDefIdr string) ([]byte, ValidationErrors, error) {
+	request, source, report, err := decodeMessage(body, from)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error unmarshal %s: %w", strings.ToUpper(from), err)
+	}
+
+	target := source
+	if msgDefIdr != "" {
+		target = lookupMessageType(msgDefIdr)
+		if target == nil || target.Root != source.Root {
+			return nil, nil, fmt.Errorf("Error converting %s: no version %s of the message", source.MsgDefIdr, msgDefIdr)
+		}
+	}
+
+	msg := request.BusMsg
+	setHeadNamespace(&msg)
+	encoded, more, err := target.Encode(msg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error converting %s to %s: %w", source.MsgDefIdr, target.MsgDefIdr, err)
+	}
+	report = append(report, more...)
+
+	var out []byte
+	if to == formatXML {
+		out, err = marshalIndent(encoded, formatXML)
+	} else {
+		out, err = marshalIndent(struct {
+			BusMsg interface{} `json:"BusMsg"`
+		}{encoded}, formatJSON)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error MarshalIndent %s: %w", strings.ToUpper(to), err)
+	}
+	return out, report, nil
+}
+
+// Convert a JSON BusMsg payload into canonical ISO 20022 XML
+func ConvertJSONToXML(body []byte) ([]byte, error) {
+	return ConvertIso(body, formatJSON, formatXML)
+}
+
+// Convert an ISO 20022 XML BusMsg payload into JSON
+func ConvertXMLToJSON(body []byte) ([]byte, error) {
+	return ConvertIso(body, formatXML, formatJSON)
+}
+
+// Marshal BusMsg as canonical ISO 20022 XML
+// AppHdr and Document each declare their namespace once, child elements inherit it
+// Document namespace follows its message root, see Document.MarshalXML
+func MarshalBusMsgXML(msg BusMsg) ([]byte, error) {
+	setHeadNamespace(&msg)
+
+	out, err := marshalIndent(msg, formatXML)
+	if err != nil {
+		return nil, fmt.Errorf("Error MarshalIndent %s: %w", strings.ToUpper(formatXML), err)
+	}
+	return out, nil
+}
+
+// Set AppHdr namespace when missing, as in messages decoded from JSON
+func setHeadNamespace(msg *BusMsg) {
+	if msg.AppHdr.XMLName.Space == "" {
+		msg.AppHdr.XMLName = xml.Name{Space: headNamespace, Local: "AppHdr"}
+	}
+}
+
+// Return the opposite payload format
+func otherFormat(format string) string {
+	if format == formatXML {
+		return formatJSON
+	}
+	return formatXML
+}
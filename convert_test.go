@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// Read sample payload from testdata
+func readSample(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.TrimSpace(body)
+}
+
+// Converting each sample gives the sample of the other format, and converting that back gives the sample again
+func TestConvertIsoRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		in, out  string
+		contains []string
+	}{
+		{
+			name: "pacs.008 JSON to XML to JSON",
+			from: formatJSON, to: formatXML,
+			in: "pacs008.json", out: "pacs008.xml",
+			contains: []string{
+				`<AppHdr xmlns="urn:iso:std:iso:20022:tech:xsd:head.001.001.02">`,
+				`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09">`,
+				`<IntrBkSttlmAmt Ccy="IDR">1234.56</IntrBkSttlmAmt>`,
+				`<IntrBkSttlmDt>2021-03-01</IntrBkSttlmDt>`,
+				`<CreDtTm>2021-03-01T19:00:00.123+07:00</CreDtTm>`,
+			},
+		},
+		{
+			name: "pacs.008 XML to JSON to XML",
+			from: formatXML, to: formatJSON,
+			in: "pacs008.xml", out: "pacs008.json",
+			contains: []string{
+				`"Value": "1234.56",`,
+				`"Ccy": "IDR"`,
+				`"IntrBkSttlmDt": "2021-03-01"`,
+				`"CreDtTm": "2021-03-01T19:00:00.123+07:00"`,
+			},
+		},
+		{
+			name: "pacs.002 JSON to XML to JSON",
+			from: formatJSON, to: formatXML,
+			in: "pacs002.json", out: "pacs002.xml",
+			contains: []string{
+				`<AppHdr xmlns="urn:iso:std:iso:20022:tech:xsd:head.001.001.02">`,
+				`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pacs.002.001.10">`,
+				`<CreDtTm>2021-03-02T10:00:00</CreDtTm>`,
+				`<AccptncDtTm>2021-03-02T09:59:59Z</AccptncDtTm>`,
+			},
+		},
+		{
+			name: "pacs.002 XML to JSON to XML",
+			from: formatXML, to: formatJSON,
+			in: "pacs002.xml", out: "pacs002.json",
+			contains: []string{
+				`"CreDtTm": "2021-03-02T10:00:00"`,
+				`"OrgnlCreDtTm": "2021-03-01T19:00:00.123+07:00"`,
+				`"AccptncDtTm": "2021-03-02T09:59:59Z"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in, want := readSample(t, tt.in), readSample(t, tt.out)
+
+			out, err := ConvertIso(in, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("converting %s: %v", tt.in, err)
+			}
+			if !bytes.Equal(out, want) {
+				t.Errorf("converting %s gives\n%s\nwant %s", tt.in, out, tt.out)
+			}
+			for _, s := range tt.contains {
+				if !bytes.Contains(out, []byte(s)) {
+					t.Errorf("converting %s gives no %s", tt.in, s)
+				}
+			}
+
+			back, err := ConvertIso(out, tt.to, tt.from)
+			if err != nil {
+				t.Fatalf("converting back: %v", err)
+			}
+			if !bytes.Equal(back, in) {
+				t.Errorf("converting back gives\n%s\nwant %s", back, tt.in)
+			}
+		})
+	}
+}
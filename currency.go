@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Currency is an ISO 4217 currency code entry
+type Currency struct {
+	Code       string
+	MinorUnits int // -1 when the currency has no fraction rule
+	Active     bool
+}
+
+// iso4217.csv is embedded as default table, ISO 4217 amendments are picked up by editing it and rebuilding,
+// or without a rebuild by starting with -currency-table naming an updated copy
+//
+//go:embed iso4217.csv
+var iso4217Table []byte
+
+var (
+	currenciesMu sync.RWMutex
+	currencies   map[string]Currency
+)
+
+func init() {
+	if err := LoadCurrencyTable(bytes.NewReader(iso4217Table)); err != nil {
+		panic(fmt.Sprintf("embedded iso4217.csv: %s", err.Error()))
+	}
+}
+
+// Replace currency table with the one read from r
+// Lines are "code,minor units,status" where status is A (active) or H (historic), # starts a comment
+func LoadCurrencyTable(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 3
+
+	table := make(map[string]Currency)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		currency := Currency{Code: strings.TrimSpace(record[0]), MinorUnits: -1}
+		if units := strings.TrimSpace(record[1]); units != "" {
+			if currency.MinorUnits, err = strconv.Atoi(units); err != nil {
+				return fmt.Errorf("currency %s: invalid minor units %q", currency.Code, units)
+			}
+		}
+		switch strings.TrimSpace(record[2]) {
+		case "A":
+			currency.Active = true
+		case "H":
+		default:
+			return fmt.Errorf("currency %s: invalid status %q", currency.Code, record[2])
+		}
+		table[currency.Code] = currency
+	}
+
+	currenciesMu.Lock()
+	currencies = table
+	currenciesMu.Unlock()
+	return nil
+}
+
+// Replace currency table with the one in file path
+func LoadCurrencyFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := LoadCurrencyTable(file); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Return ISO 4217 entry of currency code
+func LookupCurrency(code string) (Currency, bool) {
+	currenciesMu.RLock()
+	defer currenciesMu.RUnlock()
+	currency, ok := currencies[code]
+	return currency, ok
+}
+
+// Check code is a known ISO 4217 currency, historic codes are only allowed when allowHistoric is set
+func checkCurrency(code string, allowHistoric bool) error {
+	currency, ok := LookupCurrency(code)
+	if !ok {
+		return reasonError{reasonInvalidCurrency, fmt.Sprintf("%q is not an ISO 4217 currency code", code)}
+	}
+	if !currency.Active && !allowHistoric {
+		return reasonError{reasonInvalidCurrency, fmt.Sprintf("%q is a historic ISO 4217 currency code", code)}
+	}
+	return nil
+}
+
+// Check amount has no more fraction digits than its currency allows, e.g. 0 for JPY and 3 for KWD
+func checkMinorUnits(amount Decimal, code string) error {
+	currency, ok := LookupCurrency(code)
+	if !ok || currency.MinorUnits < 0 {
+		return nil
+	}
+
+	if _, fraction := amount.Digits(); fraction > currency.MinorUnits {
+		return reasonError{reasonInvalidAmount, fmt.Sprintf("%s %s must have no more than %d fraction digits", code, amount, currency.MinorUnits)}
+	}
+	return nil
+}
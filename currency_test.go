@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckCurrency(t *testing.T) {
+	tests := []struct {
+		code          string
+		allowHistoric bool
+		want          string // reason code of the error, empty when valid
+	}{
+		{"IDR", false, ""},
+		{"XAU", false, ""},
+		{"XXQ", false, reasonInvalidCurrency},
+		{"idr", false, reasonInvalidCurrency},
+		{"ATS", false, reasonInvalidCurrency},
+		{"ATS", true, ""},
+	}
+	for _, tt := range tests {
+		if got := reasonCode(checkCurrency(tt.code, tt.allowHistoric)); got != tt.want {
+			t.Errorf("checkCurrency(%q, %v) gives reason %q, want %q", tt.code, tt.allowHistoric, got, tt.want)
+		}
+	}
+}
+
+func TestCheckMinorUnits(t *testing.T) {
+	tests := []struct {
+		amount string
+		code   string
+		want   string
+	}{
+		{"1234.56", "IDR", ""},
+		{"1234.567", "IDR", reasonInvalidAmount},
+		{"1234.560", "IDR", ""}, // trailing zeros are not significant
+		{"1000", "JPY", ""},
+		{"1000.5", "JPY", reasonInvalidAmount},
+		{"1.234", "KWD", ""},
+		{"1.2345", "KWD", reasonInvalidAmount},
+		{"1.2345", "CLF", ""},
+		{"1.23456789", "XAU", ""}, // no fraction rule
+		{"1.23456789", "XXQ", ""}, // unknown currencies are reported by checkCurrency
+	}
+	for _, tt := range tests {
+		amount, err := ParseDecimal(tt.amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := reasonCode(checkMinorUnits(amount, tt.code)); got != tt.want {
+			t.Errorf("checkMinorUnits(%s, %s) gives reason %q, want %q", tt.amount, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestLoadCurrencyTable(t *testing.T) {
+	t.Cleanup(func() {
+		if err := LoadCurrencyTable(bytes.NewReader(iso4217Table)); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		table   string
+		wantErr string
+	}{
+		{"invalid minor units", "EUR,two,A\n", `invalid minor units "two"`},
+		{"invalid status", "EUR,2,X\n", `invalid status "X"`},
+		{"missing field", "EUR,2\n", "wrong number of fields"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := LoadCurrencyTable(strings.NewReader(tt.table))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// a table replaces the previous one as a whole
+	if err := LoadCurrencyTable(strings.NewReader("# code,minor units,status\nEUR,2,A\nDEM,2,H\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := LookupCurrency("IDR"); ok {
+		t.Error("IDR is still known after loading a table without it")
+	}
+	if currency, ok := LookupCurrency("DEM"); !ok || currency.Active || currency.MinorUnits != 2 {
+		t.Errorf("got DEM %+v, want historic with 2 minor units", currency)
+	}
+}
+
+// An updated table file replaces the embedded one, a broken file leaves the current table in place
+func TestLoadCurrencyFile(t *testing.T) {
+	t.Cleanup(func() {
+		if err := LoadCurrencyTable(bytes.NewReader(iso4217Table)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	dir := t.TempDir()
+
+	broken := filepath.Join(dir, "broken.csv")
+	if err := ioutil.WriteFile(broken, []byte("EUR,2,X\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadCurrencyFile(broken); err == nil || !strings.Contains(err.Error(), broken) {
+		t.Errorf("got error %v, want one naming %s", err, broken)
+	}
+	if err := LoadCurrencyFile(filepath.Join(dir, "missing.csv")); err == nil {
+		t.Error("got no error for a missing file")
+	}
+	if _, ok := LookupCurrency("IDR"); !ok {
+		t.Error("IDR is unknown after failed loads")
+	}
+
+	updated := filepath.Join(dir, "iso4217.csv")
+	if err := ioutil.WriteFile(updated, append(append([]byte{}, iso4217Table...), "XQQ,2,A\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadCurrencyFile(updated); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkCurrency("XQQ", false); err != nil {
+		t.Errorf("XQQ of the updated table is rejected: %v", err)
+	}
+}
+
+// Return reason code of err, empty when err is nil
+func reasonCode(err error) string {
+	var reason reasonError
+	if errors.As(err, &reason) {
+		return reason.Code
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Decimal is an exact xs:decimal value
+// It keeps the text it was decoded from, so "1234.50" is written back as "1234.50"
+type Decimal struct {
+	text string
+}
+
+var decimalPattern = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+// Parse text as Decimal
+func ParseDecimal(text string) (Decimal, error) {
+	text = strings.TrimSpace(text)
+	if !decimalPattern.MatchString(text) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", text)
+	}
+	return Decimal{text: text}, nil
+}
+
+// Return Decimal as written in the message, "0" when empty
+func (d Decimal) String() string {
+	if d.text == "" {
+		return "0"
+	}
+	return d.text
+}
+
+func (d *Decimal) UnmarshalText(text []byte) (err error) {
+	*d, err = ParseDecimal(string(text))
+	return err
+}
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts both "1234.56" and 1234.56
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		var number json.Number
+		if err := json.Unmarshal(data, &number); err != nil {
+			return fmt.Errorf("invalid decimal %s", data)
+		}
+		text = number.String()
+	}
+	return d.UnmarshalText([]byte(text))
+}
+
+// MarshalJSON writes Decimal as JSON string so no precision is lost by clients
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Return unscaled integer and scale, so that d = unscaled / 10^scale
+func (d Decimal) unscaled() (*big.Int, int) {
+	text := strings.TrimPrefix(d.String(), "+")
+	scale := 0
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		scale = len(text) - i - 1
+		text = text[:i] + text[i+1:]
+	}
+	if text == "" || text == "-" {
+		text += "0"
+	}
+
+	n, _ := new(big.Int).SetString(text, 10)
+	return n, scale
+}
+
+// Build Decimal from unscaled integer and scale
+func newDecimal(n *big.Int, scale int) Decimal {
+	digits := new(big.Int).Abs(n).String()
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	text := digits
+	if scale > 0 {
+		text = digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	}
+	if n.Sign() < 0 {
+		text = "-" + text
+	}
+	return Decimal{text: text}
+}
+
+// Return both values scaled to the same number of fraction digits
+func alignDecimals(a Decimal, b Decimal) (*big.Int, *big.Int, int) {
+	x, xScale := a.unscaled()
+	y, yScale := b.unscaled()
+	for ; xScale < yScale; xScale++ {
+		x.Mul(x, big.NewInt(10))
+	}
+	for ; yScale < xScale; yScale++ {
+		y.Mul(y, big.NewInt(10))
+	}
+	return x, y, xScale
+}
+
+// Return d + other, keeping the larger number of fraction digits
+func (d Decimal) Add(other Decimal) Decimal {
+	x, y, scale := alignDecimals(d, other)
+	return newDecimal(x.Add(x, y), scale)
+}
+
+// Return d - other, keeping the larger number of fraction digits
+func (d Decimal) Sub(other Decimal) Decimal {
+	x, y, scale := alignDecimals(d, other)
+	return newDecimal(x.Sub(x, y), scale)
+}
+
+// Return absolute value of d, keeping its fraction digits
+func (d Decimal) Abs() Decimal {
+	n, scale := d.unscaled()
+	return newDecimal(n.Abs(n), scale)
+}
+
+// Compare values, returning -1, 0 or +1 (1.50 and 1.5 are equal)
+func (d Decimal) Cmp(other Decimal) int {
+	x, y, _ := alignDecimals(d, other)
+	return x.Cmp(y)
+}
+
+// Return -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	n, _ := d.unscaled()
+	return n.Sign()
+}
+
+// Return number of significant integer and fraction digits,
+// ignoring leading zeros and trailing fraction zeros as XSD totalDigits/fractionDigits do
+func (d Decimal) Digits() (total int, fraction int) {
+	text := strings.TrimLeft(d.String(), "+-")
+	integer, frac := text, ""
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		integer, frac = text[:i], text[i+1:]
+	}
+	integer = strings.TrimLeft(integer, "0")
+	frac = strings.TrimRight(frac, "0")
+	return len(integer) + len(frac), len(frac)
+}
+
+// Check XSD totalDigits and fractionDigits facets
+func checkDigits(d Decimal, totalDigits int, fractionDigits int) error {
+	total, fraction := d.Digits()
+	if total > totalDigits {
+		return fmt.Errorf("%s must have no more than %d digits", d, totalDigits)
+	}
+	if fraction > fractionDigits {
+		return fmt.Errorf("%s must have no more than %d fraction digits", d, fractionDigits)
+	}
+	return nil
+}
+
+// Check amount facets: non-negative, 18 total and 5 fraction digits
+func checkAmount(d Decimal) error {
+	if d.Sign() < 0 {
+		return fmt.Errorf("%s must not be negative", d)
+	}
+	return checkDigits(d, 18, 5)
+}
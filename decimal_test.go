@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{text: "1234.50", want: "1234.50"},
+		{text: " 1 ", want: "1"},
+		{text: "-0.5", want: "-0.5"},
+		{text: "+.5", want: "+.5"},
+		{text: "5.", want: "5."},
+		{text: "", wantErr: true},
+		{text: "1e3", wantErr: true},
+		{text: "1,5", wantErr: true},
+		{text: ".", wantErr: true},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.text)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDecimal(%q) gives error %v, want error %v", tt.text, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && d.String() != tt.want {
+			t.Errorf("ParseDecimal(%q) = %s, want %s", tt.text, d, tt.want)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		sum, sub string
+		cmp      int
+	}{
+		{"1234.56", "0.44", "1235.00", "1234.12", 1},
+		{"1.5", "1.50", "3.00", "0.00", 0},
+		{"0.1", "0.2", "0.3", "-0.1", -1},
+		{"-5", "2.5", "-2.5", "-7.5", -1},
+		{"99999999999999999.99", "0.01", "100000000000000000.00", "99999999999999999.98", 1},
+	}
+	for _, tt := range tests {
+		a, b := mustDecimal(t, tt.a), mustDecimal(t, tt.b)
+		if got := a.Add(b).String(); got != tt.sum {
+			t.Errorf("%s + %s = %s, want %s", tt.a, tt.b, got, tt.sum)
+		}
+		if got := a.Sub(b).String(); got != tt.sub {
+			t.Errorf("%s - %s = %s, want %s", tt.a, tt.b, got, tt.sub)
+		}
+		if got := a.Cmp(b); got != tt.cmp {
+			t.Errorf("%s cmp %s = %d, want %d", tt.a, tt.b, got, tt.cmp)
+		}
+	}
+}
+
+func TestDecimalDigits(t *testing.T) {
+	tests := []struct {
+		text            string
+		total, fraction int
+	}{
+		{"1234.56", 6, 2},
+		{"001234.5600", 6, 2},
+		{"-0.05", 2, 2},
+		{"100", 3, 0},
+		{"0", 0, 0},
+	}
+	for _, tt := range tests {
+		total, fraction := mustDecimal(t, tt.text).Digits()
+		if total != tt.total || fraction != tt.fraction {
+			t.Errorf("%s has %d digits, %d fraction digits, want %d and %d", tt.text, total, fraction, tt.total, tt.fraction)
+		}
+	}
+}
+
+// Amounts keep their text through JSON, numbers are accepted and written back as strings
+func TestDecimalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`"1234.50"`, `"1234.50"`},
+		{`1234.50`, `"1234.50"`},
+		{`0.1`, `"0.1"`},
+	}
+	for _, tt := range tests {
+		var d Decimal
+		if err := json.Unmarshal([]byte(tt.in), &d); err != nil {
+			t.Errorf("unmarshal %s: %v", tt.in, err)
+			continue
+		}
+		out, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != tt.want {
+			t.Errorf("%s is written back as %s, want %s", tt.in, out, tt.want)
+		}
+	}
+
+	var d Decimal
+	if err := json.Unmarshal([]byte(`true`), &d); err == nil {
+		t.Error("unmarshal true gives no error")
+	}
+}
+
+// Parse text as Decimal, failing the test when it is invalid
+func mustDecimal(t *testing.T, text string) Decimal {
+	t.Helper()
+	d, err := ParseDecimal(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
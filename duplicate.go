@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+)
+
+// How long identifiers of accepted messages are remembered for duplicate detection
+// A replay without PssblDplct is rejected by default: ISO 20022 asks senders to flag every retransmission,
+// so an unflagged copy more likely is a second instruction sent by mistake than a retry, and acknowledging it
+// as the original would hide that from the sender. acceptUndeclaredReplays answers it like a declared one
+//
+// Rejections tell the cases apart by reason code: AM05 at /AppHdr/PssblDplct for a replay not flagged,
+// DU01 (message identifier) or DU03 (transaction identifier) at the reused element for different content
+var (
+	duplicateWindow         = 24 * time.Hour
+	acceptUndeclaredReplays = false
+)
+
+// DuplicateId is an identifier of a new message already used by a stored one
+type DuplicateId struct {
+	Location string
+	Value    string
+	Original *StoredMessage
+}
+
+// Return digest of Document content
+// Document is digested as compact JSON so the same message sent as JSON or XML gets the same digest
+func documentDigest(doc Document) (string, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Return digest of a stored message, computing it from the payload for messages saved before digests were kept
+func storedDigest(msg *StoredMessage) string {
+	if msg.Digest != "" {
+		return msg.Digest
+	}
+	request, _, _, err := decodeMessage(msg.Payload, msg.Format)
+	if err != nil {
+		return ""
+	}
+	msg.Digest, _ = documentDigest(request.BusMsg.Document)
+	return msg.Digest
+}
+
+// Compare reused identifiers of msg against the messages first using them
+// A replay (same content) returns the original message, a reuse with different content returns conflict issues
+func checkDuplicates(msg *StoredMessage, duplicates []DuplicateId) (*StoredMessage, ValidationErrors) {
+	var original *StoredMessage
+	var conflicts ValidationErrors
+	for _, duplicate := range duplicates {
+		if storedDigest(duplicate.Original) == msg.Digest {
+			if original == nil || duplicate.Original.ReceivedAt.Before(original.ReceivedAt) {
+				original = duplicate.Original
+			}
+			continue
+		}
+		code := reasonDuplicateTransaction
+		if base := path.Base(duplicate.Location); base == "MsgId" || base == "Id" {
+			code = reasonDuplicateMessageId
+		}
+		conflicts = append(conflicts, ValidationError{
+			Location: duplicate.Location,
+			Code:     code,
+			Severity: severityError,
+			Message: fmt.Sprintf("%s is already used by message %s received at %s with different content",
+				duplicate.Value, duplicate.Original.ID, duplicate.Original.ReceivedAt.UTC().Format(time.RFC3339)),
+		})
+	}
+
+	if len(conflicts) > 0 {
+		return nil, conflicts
+	}
+	return original, nil
+}
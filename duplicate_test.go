@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckDuplicates(t *testing.T) {
+	received := time.Date(2021, 3, 1, 19, 0, 0, 0, time.UTC)
+	first := &StoredMessage{ID: "first", Digest: "same", ReceivedAt: received}
+	later := &StoredMessage{ID: "later", Digest: "same", ReceivedAt: received.Add(time.Hour)}
+	other := &StoredMessage{ID: "other", Digest: "other", ReceivedAt: received}
+
+	tests := []struct {
+		name         string
+		duplicates   []DuplicateId
+		wantOriginal string
+		wantIssues   ValidationErrors
+	}{
+		{name: "new message"},
+		{
+			name:         "replay",
+			duplicates:   []DuplicateId{{Location: "/Document/FIToFICstmrCdtTrf/GrpHdr/MsgId", Value: "M1", Original: later}},
+			wantOriginal: "later",
+		},
+		{
+			name: "replay of the earliest message",
+			duplicates: []DuplicateId{
+				{Location: "/Document/FIToFICstmrCdtTrf/GrpHdr/MsgId", Value: "M1", Original: later},
+				{Location: "/Document/FIToFICstmrCdtTrf/CdtTrfTxInf[0]/PmtId/EndToEndId", Value: "E1", Original: first},
+			},
+			wantOriginal: "first",
+		},
+		{
+			name: "identifier reused with different content",
+			duplicates: []DuplicateId{
+				{Location: "/Document/FIToFICstmrCdtTrf/GrpHdr/MsgId", Value: "M1", Original: first},
+				{Location: "/Document/FIToFICstmrCdtTrf/CdtTrfTxInf[0]/PmtId/TxId", Value: "T1", Original: other},
+			},
+			wantIssues: ValidationErrors{{Location: "/Document/FIToFICstmrCdtTrf/CdtTrfTxInf[0]/PmtId/TxId", Code: reasonDuplicateTransaction}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original, issues := checkDuplicates(&StoredMessage{Digest: "same"}, tt.duplicates)
+			checkIssues(t, issues, tt.wantIssues)
+
+			id := ""
+			if original != nil {
+				id = original.ID
+			}
+			if id != tt.wantOriginal {
+				t.Errorf("got original %q, want %q", id, tt.wantOriginal)
+			}
+		})
+	}
+}
+
+// JSON and XML payloads of the same message have the same digest, other content does not
+func TestDocumentDigest(t *testing.T) {
+	fromJSON := decodeSample(t, "pacs008.json", formatJSON)
+	fromXML := decodeSample(t, "pacs008.xml", formatXML)
+	jsonDigest, err := documentDigest(fromJSON.BusMsg.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xmlDigest, err := documentDigest(fromXML.BusMsg.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jsonDigest != xmlDigest {
+		t.Errorf("JSON digest %s differs from XML digest %s", jsonDigest, xmlDigest)
+	}
+
+	fromJSON.BusMsg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0].IntrBkSttlmAmt.Value = mustDecimal(t, "1234.57")
+	if changed, _ := documentDigest(fromJSON.BusMsg.Document); changed == jsonDigest {
+		t.Error("digest does not change with the amount")
+	}
+}
+
+// Replays are answered with the acknowledgement of the original, and rejected with AM05 unless flagged PssblDplct or
+// accepted explicitly, reusing an identifier for other content is rejected with DU01 or DU03
+func TestIngestDuplicates(t *testing.T) {
+	tests := []struct {
+		name             string
+		acceptUndeclared bool
+		replay           func(msg *BusMsg)
+		wantCode         int
+		wantStatus       string
+		wantOriginal     bool // MessageId is the one of the original message
+		wantIssues       ValidationErrors
+	}{
+		{
+			name:         "replay flagged PssblDplct",
+			replay:       func(msg *BusMsg) { msg.AppHdr.PssblDplct = true },
+			wantCode:     http.StatusOK,
+			wantStatus:   statusAccepted,
+			wantOriginal: true,
+		},
+		{
+			name:       "replay not flagged",
+			replay:     func(msg *BusMsg) {},
+			wantCode:   http.StatusConflict,
+			wantStatus: statusRejected,
+			wantIssues: ValidationErrors{{Location: "/AppHdr/PssblDplct", Code: reasonDuplication}},
+		},
+		{
+			name:             "replay not flagged, accepted",
+			acceptUndeclared: true,
+			replay:           func(msg *BusMsg) {},
+			wantCode:         http.StatusOK,
+			wantStatus:       statusAccepted,
+			wantOriginal:     true,
+			wantIssues:       ValidationErrors{{Location: "/AppHdr/PssblDplct", Code: reasonDuplication}},
+		},
+		{
+			name: "MsgId reused",
+			replay: func(msg *BusMsg) {
+				tx := msg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0]
+				tx.PmtId.EndToEndId, tx.PmtId.TxId = optionalText("E2"), optionalText("T2")
+			},
+			wantCode:   http.StatusConflict,
+			wantStatus: statusRejected,
+			wantIssues: ValidationErrors{{Location: "/Document/FIToFICstmrCdtTrf/GrpHdr/MsgId", Code: reasonDuplicateMessageId}},
+		},
+		{
+			name: "TxId reused",
+			replay: func(msg *BusMsg) {
+				msg.Document.FIToFICstmrCdtTrf.GrpHdr.MsgId = optionalText("M2")
+				msg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0].PmtId.EndToEndId = optionalText("E2")
+			},
+			wantCode:   http.StatusConflict,
+			wantStatus: statusRejected,
+			wantIssues: ValidationErrors{{Location: "/Document/FIToFICstmrCdtTrf/CdtTrfTxInf[0]/PmtId/TxId", Code: reasonDuplicateTransaction}},
+		},
+		{
+			name: "new message",
+			replay: func(msg *BusMsg) {
+				msg.Document.FIToFICstmrCdtTrf.GrpHdr.MsgId = optionalText("M2")
+				tx := msg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0]
+				tx.PmtId.EndToEndId, tx.PmtId.TxId = optionalText("E2"), optionalText("T2")
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: statusAccepted,
+		},
+		{
+			name: "EndToEndId reused",
+			replay: func(msg *BusMsg) {
+				msg.Document.FIToFICstmrCdtTrf.GrpHdr.MsgId = optionalText("M2")
+				msg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0].PmtId.TxId = optionalText("T2")
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: statusAccepted,
+		},
+		{
+			name: "MsgId reused by another sender",
+			replay: func(msg *BusMsg) {
+				bic := BICFIDec2014Identifier("DEUTDEFF")
+				msg.AppHdr.Fr.FIId.FinInstnId.BICFI = &bic
+				msg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0].PmtId.TxId = optionalText("T2")
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: statusAccepted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestServer(t)
+			defer func(accept bool) { acceptUndeclaredReplays = accept }(acceptUndeclaredReplays)
+			acceptUndeclaredReplays = tt.acceptUndeclared
+
+			code, first := postIso(t, handler, "/iso20022", samplePayload(t, func(msg *BusMsg) {}), nil)
+			if code != http.StatusOK || first.Status != statusAccepted {
+				t.Fatalf("got %d %+v for the original message", code, first)
+			}
+
+			code, response := postIso(t, handler, "/iso20022", samplePayload(t, tt.replay), nil)
+			if code != tt.wantCode || response.Status != tt.wantStatus {
+				t.Errorf("got %d %s, want %d %s", code, response.Status, tt.wantCode, tt.wantStatus)
+			}
+			if (response.MessageId == first.MessageId) != tt.wantOriginal {
+				t.Errorf("got MessageId %q, original is %q", response.MessageId, first.MessageId)
+			}
+			checkIssues(t, response.Issues, tt.wantIssues)
+		})
+	}
+}
+
+// Unrelated messages may both carry the NOTPROVIDED placeholder as EndToEndId
+func TestIngestEndToEndIdNotProvided(t *testing.T) {
+	handler := newTestServer(t)
+	for i, ids := range [][2]string{{"M1", "T1"}, {"M2", "T2"}} {
+		body := samplePayload(t, func(msg *BusMsg) {
+			msg.Document.FIToFICstmrCdtTrf.GrpHdr.MsgId = optionalText(ids[0])
+			tx := msg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0]
+			tx.PmtId.EndToEndId, tx.PmtId.TxId = optionalText("NOTPROVIDED"), optionalText(ids[1])
+			tx.IntrBkSttlmAmt.Value = mustDecimal(t, fmt.Sprintf("%d.00", 100*(i+1)))
+		})
+		if code, response := postIso(t, handler, "/iso20022", body, nil); code != http.StatusOK || response.Status != statusAccepted {
+			t.Errorf("message %d: got %d %+v, want it accepted", i+1, code, response)
+		}
+	}
+}
+
+// Start service on empty stores in a temporary directory, logging nowhere
+func newTestServer(t *testing.T) http.Handler {
+	t.Helper()
+	dir := t.TempDir()
+
+	fileStore, err := NewFileStore(filepath.Join(dir, "parsed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlStore, err := NewSQLStore(filepath.Join(dir, "messages.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	savedStore, savedFinder, savedIdempotency, savedDeliveries := store, finder, idempotency, deliveries
+	store, finder, idempotency, deliveries = MultiStore{fileStore, sqlStore}, sqlStore, sqlStore, sqlStore
+	log.SetOutput(ioutil.Discard)
+	t.Cleanup(func() {
+		store, finder, idempotency, deliveries = savedStore, savedFinder, savedIdempotency, savedDeliveries
+		log.SetOutput(os.Stderr)
+		sqlStore.Close()
+	})
+	return pathHandler()
+}
+
+// Return JSON payload of the pacs.008 sample, changed by change
+func samplePayload(t *testing.T, change func(msg *BusMsg)) []byte {
+	t.Helper()
+	return messagePayload(t, "pacs008.json", change)
+}
+
+// Return JSON payload of the sample name, changed by change when not nil
+func messagePayload(t *testing.T, name string, change func(msg *BusMsg)) []byte {
+	t.Helper()
+	request := decodeSample(t, name, formatJSON)
+	if change != nil {
+		change(&request.BusMsg)
+	}
+	body, err := marshalBusMsg(request.BusMsg, formatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+// POST JSON body to path, returning the status code and Response
+func postIso(t *testing.T, handler http.Handler, path string, body []byte, header http.Header) (int, Response) {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	for name, values := range header {
+		r.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	var response Response
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("%s gives %d %s: %v", path, w.Code, w.Body.String(), err)
+	}
+	return w.Code, response
+}
+
+// POST JSON body to path, returning the status code and the message answering it
+func postMessage(t *testing.T, handler http.Handler, path string, body []byte) (int, BusMsg) {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	answer, _, _, err := decodeMessage(w.Body.Bytes(), formatJSON)
+	if err != nil {
+		t.Fatalf("%s gives %d %s: %v", path, w.Code, w.Body.String(), err)
+	}
+	return w.Code, answer.BusMsg
+}
+
+// GET path, decoding the JSON response into v, returning the status code
+func getJSON(t *testing.T, handler http.Handler, path string, v interface{}) int {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("%s gives %d %s: %v", path, w.Code, w.Body.String(), err)
+	}
+	return w.Code
+}
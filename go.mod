@@ -3,7 +3,8 @@ module github.com/j03hanafi/jsonParser
 go 1.16
 
 require (
-	github.com/ChimeraCoder/gojson v1.1.0 // indirect
-	github.com/gorilla/mux v1.8.0 // indirect
+	github.com/ChimeraCoder/gojson v1.1.0
+	github.com/gorilla/mux v1.8.0
 	gopkg.in/yaml.v2 v2.4.0 // indirect
+	modernc.org/sqlite v1.17.3
 )
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// How long responses are kept for Idempotency-Key retries
+var idempotencyTTL = 24 * time.Hour
+
+// CachedResponse is the response of the first request sent with an Idempotency-Key
+// Keys are scoped to the request method, path and query, the same key sent to another endpoint is another request
+type CachedResponse struct {
+	Scope       string // e.g. POST /iso20022?reply=pacs.002
+	Key         string
+	BodyDigest  string // sha256 of request body
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+// IdempotencyCache keeps responses by scope and Idempotency-Key
+// GetResponse returns nil when key is unknown in scope or was stored before since
+type IdempotencyCache interface {
+	GetResponse(scope string, key string, since time.Time) (*CachedResponse, error)
+	PutResponse(response *CachedResponse) error
+}
+
+var (
+	idempotency     IdempotencyCache
+	idempotencyKeys = keyLocks{locks: make(map[string]*keyLock)}
+)
+
+// keyLocks serializes requests sharing a scoped Idempotency-Key, so a retry waits for the first attempt
+// Requests with other keys are not held up
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	waiting int // requests holding or waiting for the lock, it is dropped when none are left
+}
+
+// Lock key, returning the function unlocking it
+func (l *keyLocks) lock(key string) func() {
+	l.mu.Lock()
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		l.locks[key] = lock
+	}
+	lock.waiting++
+	l.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		l.mu.Lock()
+		lock.waiting--
+		if lock.waiting == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// Return the scope of an Idempotency-Key sent with r: method, path and query, its parameters sorted
+func requestScope(r *http.Request) string {
+	scope := r.Method + " " + r.URL.Path
+	if query := r.URL.Query().Encode(); query != "" {
+		scope += "?" + query
+	}
+	return scope
+}
+
+// responseRecorder keeps a copy of what a handler writes
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(statusCode int) {
+	rec.statusCode = statusCode
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rec *responseRecorder) Write(data []byte) (int, error) {
+	if rec.statusCode == 0 {
+		rec.statusCode = http.StatusOK
+	}
+	rec.body.Write(data)
+	return rec.ResponseWriter.Write(data)
+}
+
+// Wrap handler so a retried request with the same Idempotency-Key, method, path, query and body gets the first response again
+// The same key with a different body is answered with 422, requests without the header are passed through
+// Server errors are not cached, so they can be retried
+func idempotent(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			handler(w, r)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+		digest := hex.EncodeToString(sum[:])
+
+		var response Response
+		format := requestFormat(r)
+
+		scope := requestScope(r)
+		unlock := idempotencyKeys.lock(scope + " " + key)
+		defer unlock()
+
+		cached, err := idempotency.GetResponse(scope, key, time.Now().Add(-idempotencyTTL))
+		if err != nil {
+			response.Status = statusRejected
+			response.Message = "Error reading Idempotency-Key"
+			log.Printf("%s: %s", response.Message, err.Error())
+			responseFormatter(w, format, response, http.StatusInternalServerError)
+			return
+		}
+		if cached != nil {
+			if cached.BodyDigest != digest {
+				response.Status = statusRejected
+				response.Message = fmt.Sprintf("Idempotency-Key %s was already used with a different request body", key)
+				log.Print(response.Message)
+				responseFormatter(w, format, response, http.StatusUnprocessableEntity)
+				return
+			}
+			log.Printf("Idempotency-Key %s retried, returning cached response", key)
+			w.Header().Set("Content-Type", cached.ContentType)
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(cached.StatusCode)
+			w.Write(cached.Body)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w}
+		handler(rec, r)
+		if rec.statusCode >= 500 {
+			return
+		}
+
+		err = idempotency.PutResponse(&CachedResponse{
+			Scope:       scope,
+			Key:         key,
+			BodyDigest:  digest,
+			StatusCode:  rec.statusCode,
+			ContentType: rec.Header().Get("Content-Type"),
+			Body:        rec.body.Bytes(),
+			CreatedAt:   time.Now(),
+		})
+		if err != nil {
+			log.Printf("Error saving Idempotency-Key %s: %s", key, err.Error())
+		}
+	}
+}
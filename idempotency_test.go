@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryCache is an IdempotencyCache kept in memory
+type memoryCache struct {
+	mu        sync.Mutex
+	responses map[string]*CachedResponse
+}
+
+func (c *memoryCache) GetResponse(scope string, key string, since time.Time) (*CachedResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	response := c.responses[scope+" "+key]
+	if response == nil || response.CreatedAt.Before(since) {
+		return nil, nil
+	}
+	return response, nil
+}
+
+func (c *memoryCache) PutResponse(response *CachedResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responses[response.Scope+" "+response.Key] = response
+	return nil
+}
+
+// Use an empty memoryCache as idempotency cache for the test
+func useMemoryCache(t *testing.T) *memoryCache {
+	cache := &memoryCache{responses: make(map[string]*CachedResponse)}
+	saved := idempotency
+	idempotency = cache
+	t.Cleanup(func() { idempotency = saved })
+	return cache
+}
+
+// testRequest is a request sent to an idempotent handler
+type testRequest struct {
+	target string
+	key    string
+	body   string
+}
+
+func TestIdempotent(t *testing.T) {
+	first := testRequest{target: "/iso20022?reply=pacs.002", key: "k1", body: "A"}
+
+	tests := []struct {
+		name         string
+		retry        testRequest
+		statusCode   int // answered by the handler, to the first request and when the retry is handled
+		wantCode     int
+		wantHandled  bool // the retry reaches the handler
+		wantReplayed bool
+	}{
+		{name: "retry", retry: first, statusCode: http.StatusOK, wantCode: http.StatusOK, wantReplayed: true},
+		{name: "retry of a rejection", retry: first, statusCode: http.StatusBadRequest, wantCode: http.StatusBadRequest, wantReplayed: true},
+		{name: "retry after a server error", retry: first, statusCode: http.StatusInternalServerError, wantCode: http.StatusInternalServerError, wantHandled: true},
+		{name: "other body", retry: testRequest{target: first.target, key: "k1", body: "B"}, statusCode: http.StatusOK, wantCode: http.StatusUnprocessableEntity},
+		{name: "other key", retry: testRequest{target: first.target, key: "k2", body: "A"}, statusCode: http.StatusOK, wantCode: http.StatusOK, wantHandled: true},
+		{name: "no key", retry: testRequest{target: first.target, body: "A"}, statusCode: http.StatusOK, wantCode: http.StatusOK, wantHandled: true},
+		{name: "other path", retry: testRequest{target: "/iso20022/return?reply=pacs.002", key: "k1", body: "A"}, statusCode: http.StatusOK, wantCode: http.StatusOK, wantHandled: true},
+		{name: "other query", retry: testRequest{target: "/iso20022", key: "k1", body: "B"}, statusCode: http.StatusOK, wantCode: http.StatusOK, wantHandled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useMemoryCache(t)
+			handled := 0
+			handler := idempotent(func(w http.ResponseWriter, r *http.Request) {
+				handled++
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.statusCode)
+				fmt.Fprintf(w, `{"handled":%d}`, handled)
+			})
+
+			firstResponse := sendRequest(handler, first)
+			retried := sendRequest(handler, tt.retry)
+
+			if retried.Code != tt.wantCode {
+				t.Errorf("got %d, want %d", retried.Code, tt.wantCode)
+			}
+			if got := handled == 2; got != tt.wantHandled {
+				t.Errorf("retry handled %v, want %v", got, tt.wantHandled)
+			}
+			if got := retried.Header().Get("Idempotent-Replayed") == "true"; got != tt.wantReplayed {
+				t.Errorf("retry replayed %v, want %v", got, tt.wantReplayed)
+			}
+			if tt.wantReplayed {
+				if retried.Body.String() != firstResponse.Body.String() {
+					t.Errorf("got body %s, want the first one %s", retried.Body, firstResponse.Body)
+				}
+				if retried.Header().Get("Content-Type") != "application/json" {
+					t.Errorf("got Content-Type %q, want the first one", retried.Header().Get("Content-Type"))
+				}
+			}
+		})
+	}
+}
+
+// Responses older than idempotencyTTL are not replayed
+func TestIdempotentExpired(t *testing.T) {
+	cache := useMemoryCache(t)
+	handled := 0
+	handler := idempotent(func(w http.ResponseWriter, r *http.Request) { handled++ })
+
+	request := testRequest{target: "/iso20022", key: "k1", body: "A"}
+	sendRequest(handler, request)
+	for _, response := range cache.responses {
+		response.CreatedAt = response.CreatedAt.Add(-idempotencyTTL - time.Minute)
+	}
+	sendRequest(handler, request)
+	if handled != 2 {
+		t.Errorf("handled %d requests, want 2", handled)
+	}
+}
+
+// Concurrent requests with the same key are handled once, the others wait and get its response
+func TestIdempotentConcurrent(t *testing.T) {
+	useMemoryCache(t)
+	var mu sync.Mutex
+	handled := 0
+	handler := idempotent(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		handled++
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if w := sendRequest(handler, testRequest{target: "/iso20022", key: "k1", body: "A"}); w.Code != http.StatusOK {
+				t.Errorf("got %d, want %d", w.Code, http.StatusOK)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if handled != 1 {
+		t.Errorf("handled %d requests, want 1", handled)
+	}
+	idempotencyKeys.mu.Lock()
+	defer idempotencyKeys.mu.Unlock()
+	if len(idempotencyKeys.locks) != 0 {
+		t.Errorf("%d key locks left, want none", len(idempotencyKeys.locks))
+	}
+}
+
+func TestRequestScope(t *testing.T) {
+	tests := []struct {
+		method, target string
+		want           string
+	}{
+		{http.MethodPost, "/iso20022", "POST /iso20022"},
+		{http.MethodPost, "/iso20022?reply=pacs.002", "POST /iso20022?reply=pacs.002"},
+		{http.MethodPost, "/iso20022?b=2&a=1", "POST /iso20022?a=1&b=2"},
+		{http.MethodPost, "/iso20022/return", "POST /iso20022/return"},
+	}
+	for _, tt := range tests {
+		if got := requestScope(httptest.NewRequest(tt.method, tt.target, nil)); got != tt.want {
+			t.Errorf("requestScope(%s %s) = %q, want %q", tt.method, tt.target, got, tt.want)
+		}
+	}
+}
+
+// Send request to handler, returning what it wrote
+func sendRequest(handler http.HandlerFunc, request testRequest) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, request.target, ioutil.NopCloser(strings.NewReader(request.body)))
+	r.Header.Set("Content-Type", "application/json")
+	if request.key != "" {
+		r.Header.Set("Idempotency-Key", request.key)
+	}
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ISO 3166-1 alpha-2 country codes, XK (Kosovo) is added as it is used by BIC and IBAN registries
+var countryCodes = make(map[string]bool)
+
+func init() {
+	for _, code := range strings.Fields(`
+		AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS
+		BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE
+		EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM
+		HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC
+		LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA
+		NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW
+		SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO
+		TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW XK`) {
+		countryCodes[code] = true
+	}
+}
+
+// IBAN length per country as published in the SWIFT IBAN registry
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22, "BH": 22, "BI": 27,
+	"BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28,
+	"EE": 20, "EG": 29, "ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23,
+	"GL": 18, "GR": 27, "GT": 28, "HN": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26,
+	"IT": 27, "JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21,
+	"LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27, "MT": 31, "MU": 30, "NI": 28,
+	"NL": 18, "NO": 15, "OM": 23, "PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22,
+	"RU": 33, "SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "SO": 23, "ST": 25,
+	"SV": 28, "TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// Check code is an ISO 3166 country
+func checkCountry(code string) error {
+	if !countryCodes[code] {
+		return reasonError{reasonInvalidCountry, fmt.Sprintf("%q is not an ISO 3166 country code", code)}
+	}
+	return nil
+}
+
+// Check BIC structure: institution code, ISO 3166 country, location and optional branch
+func checkBIC(bic string) error {
+	if len(bic) != 8 && len(bic) != 11 {
+		return reasonError{reasonInvalidBankId, fmt.Sprintf("BIC %q must be 8 or 11 characters long", bic)}
+	}
+	if !countryCodes[bic[4:6]] {
+		return reasonError{reasonInvalidBankId, fmt.Sprintf("BIC %q has unknown country code %s", bic, bic[4:6])}
+	}
+	return nil
+}
+
+// Check IBAN country, length and mod-97 check digits (ISO 13616)
+func checkIBAN(iban string) error {
+	country := iban[:2]
+	length, ok := ibanLengths[country]
+	if !ok {
+		return reasonError{reasonInvalidAccountNumber, fmt.Sprintf("IBAN %q has unknown country code %s", iban, country)}
+	}
+	if len(iban) != length {
+		return reasonError{reasonInvalidAccountNumber, fmt.Sprintf("IBAN %q must be %d characters long for %s", iban, length, country)}
+	}
+	if mod97(iban[4:]+iban[:4]) != 1 {
+		return reasonError{reasonInvalidAccountNumber, fmt.Sprintf("IBAN %q has invalid check digits", iban)}
+	}
+	return nil
+}
+
+// Check LEI mod-97 check digits (ISO 17442)
+func checkLEI(lei string) error {
+	if mod97(lei) != 1 {
+		return reasonError{reasonInvalidBankId, fmt.Sprintf("LEI %q has invalid check digits", lei)}
+	}
+	return nil
+}
+
+// Return ISO 7064 MOD 97-10 remainder, letters count as 10 (A) to 35 (Z)
+func mod97(value string) int {
+	remainder := 0
+	for _, c := range strings.ToUpper(value) {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		default:
+			return -1
+		}
+	}
+	return remainder
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIdentifierValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value validator
+		code  string // reason code of the error, empty when valid
+	}{
+		{"BIC", BICFIDec2014Identifier("DEUTDEFF"), ""},
+		{"BIC with branch", BICFIDec2014Identifier("DEUTDEFF500"), ""},
+		{"BIC of Kosovo", AnyBICDec2014Identifier("RBKOXKPR"), ""},
+		{"BIC with unknown country", BICFIDec2014Identifier("DEUTQQFF"), reasonInvalidBankId},
+		{"any BIC with unknown country", AnyBICDec2014Identifier("DEUTQQFF500"), reasonInvalidBankId},
+		{"IBAN", IBAN2007Identifier("GB82WEST12345698765432"), ""},
+		{"IBAN of Germany", IBAN2007Identifier("DE89370400440532013000"), ""},
+		{"IBAN with invalid check digits", IBAN2007Identifier("GB83WEST12345698765432"), reasonInvalidAccountNumber},
+		{"IBAN too short for its country", IBAN2007Identifier("GB82WEST1234569876543"), reasonInvalidAccountNumber},
+		{"IBAN with unknown country", IBAN2007Identifier("QQ82WEST12345698765432"), reasonInvalidAccountNumber},
+		{"LEI", LEIIdentifier("5493001KJTIIGC8Y1R12"), ""},
+		{"LEI with invalid check digits", LEIIdentifier("5493001KJTIIGC8Y1R13"), reasonInvalidBankId},
+		{"country", CountryCode("ID"), ""},
+		{"unknown country", CountryCode("QQ"), reasonInvalidCountry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.value.Validate()
+			if tt.code == "" {
+				if err != nil {
+					t.Fatalf("got error %v, want none", err)
+				}
+				return
+			}
+			var reason reasonError
+			if !errors.As(err, &reason) {
+				t.Fatalf("got error %v, want reason %s", err, tt.code)
+			}
+			if reason.Code != tt.code {
+				t.Errorf("got reason %s, want %s", reason.Code, tt.code)
+			}
+		})
+	}
+}
+
+// Values not matching the XSD pattern are rejected before their check digits are computed
+func TestIdentifierPattern(t *testing.T) {
+	tests := []struct {
+		name  string
+		value validator
+	}{
+		{"BIC too short", BICFIDec2014Identifier("DEUTDE")},
+		{"BIC in lower case", BICFIDec2014Identifier("deutdeff")},
+		{"IBAN without check digits", IBAN2007Identifier("GB")},
+		{"LEI with letters as check digits", LEIIdentifier("5493001KJTIIGC8Y1RAB")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.value.Validate()
+			if err == nil {
+				t.Fatal("got no error")
+			}
+			var reason reasonError
+			if errors.As(err, &reason) {
+				t.Errorf("got reason %s, want a pattern error", reason.Code)
+			}
+		})
+	}
+}
+
+func TestMod97(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"5493001KJTIIGC8Y1R12", 1},
+		{"WEST12345698765432GB82", 1},
+		{"97", 0},
+		{"a1", 101 % 97}, // letters count case-insensitively, a as 10
+		{"12-34", -1},
+	}
+	for _, tt := range tests {
+		if got := mod97(tt.value); got != tt.want {
+			t.Errorf("mod97(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
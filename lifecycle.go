@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Lifecycle states of a pacs.008 transaction
+// A transaction is Received when its message arrives, then Validated and Accepted, or Rejected, as the message is checked
+// Later messages settle, reject, return or cancel it
+const (
+	stateReceived  = "Received"
+	stateValidated = "Validated"
+	stateAccepted  = "Accepted"
+	stateRejected  = "Rejected"
+	stateSettled   = "Settled"
+	stateReturned  = "Returned"
+	stateCancelled = "Cancelled"
+)
+
+// States each state may move to, Rejected, Returned and Cancelled are final
+// An accepted transaction may be returned without a settlement confirmation, as settlement is not always reported
+var lifecycleTransitions = map[string][]string{
+	"":             {stateReceived},
+	stateReceived:  {stateValidated, stateRejected},
+	stateValidated: {stateAccepted, stateRejected},
+	stateAccepted:  {stateSettled, stateRejected, stateReturned, stateCancelled},
+	stateSettled:   {stateReturned, stateCancelled},
+}
+
+// Return whether a transaction in state from may move to state to
+func canTransition(from string, to string) bool {
+	for _, state := range lifecycleTransitions[from] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition is a change of state of a transaction, caused by the message saved with it
+type Transition struct {
+	Transaction TransactionRef `xml:"-" json:"-"` // empty MessageID means a transaction of the message saved with it
+	From        string         `xml:"From,omitempty" json:"From,omitempty"`
+	To          string         `xml:"To" json:"To"`
+	Event       string         `xml:"Event" json:"Event"`         // MsgDefIdr of the message causing it
+	MessageID   string         `xml:"MessageId" json:"MessageId"` // message causing it
+	At          time.Time      `xml:"At" json:"At"`
+}
+
+// Issue reporting that the transaction found for location can not move to state to
+func transitionError(location string, original *PaymentRecord, to string) ValidationError {
+	return ruleError(location, reasonIncorrectContent, "transaction %s in message %s is %s, it can not become %s",
+		original.EndToEndId, original.MsgId, original.State, to)
+}
+
+// Move every transaction of msg from state from to state to at the given time
+func moveTransactions(msg *StoredMessage, from string, to string, at time.Time) {
+	_, txs := summarize(msg.Message.Document)
+	for _, tx := range txs {
+		msg.Transitions = append(msg.Transitions, Transition{
+			Transaction: TransactionRef{Seq: tx.Seq},
+			From:        from,
+			To:          to,
+			Event:       msg.MsgDefIdr,
+			At:          at,
+		})
+	}
+}
+
+// Accept every transaction of msg, a validated pacs.008, and notify the accounts it credits
+func handleCreditTransfer(finder MessageFinder, msg *StoredMessage) (ValidationErrors, error) {
+	moveTransactions(msg, stateValidated, stateAccepted, time.Now())
+
+	var err error
+	msg.Notifications, err = transferNotifications(msg)
+	return nil, err
+}
+
+// Settlement and rejection statuses of pacs.002 reports and the state they move a transaction to
+// Other statuses leave the transaction as it is
+var reportedStates = map[string]string{
+	"ACSC": stateSettled,
+	"ACCC": stateSettled,
+	"RJCT": stateRejected,
+}
+
+// Apply transaction statuses of msg, a pacs.002 received from a counterparty, to the pacs.008 transactions they report on
+// A group status applies to every transaction of the original message that no transaction status reports on
+// Reports on transactions never received are accepted with a warning, reports moving a transaction to a state
+// it can not reach are rejected
+// Statuses are applied in order, a transaction reported on twice is checked against the state the first report moved it to
+func handleStatusReport(finder MessageFinder, msg *StoredMessage) (ValidationErrors, error) {
+	var issues ValidationErrors
+	applied := make(map[TransactionRef]string)
+	reported := make(map[TransactionRef]bool)
+	report := msg.Message.Document.FIToFIPmtStsRpt
+	if report == nil {
+		return issues, nil
+	}
+
+	const root = "/Document/FIToFIPmtStsRpt"
+	var groupMsgId *Max35Text
+	if len(report.OrgnlGrpInfAndSts) == 1 && report.OrgnlGrpInfAndSts[0] != nil {
+		groupMsgId = report.OrgnlGrpInfAndSts[0].OrgnlMsgId
+	}
+
+	// apply moves original to state to, or reports why it can not
+	apply := func(location string, original *PaymentRecord, to string) {
+		ref := TransactionRef{MessageID: original.MessageID, Seq: original.Seq}
+		if state, ok := applied[ref]; ok {
+			original.State = state
+		}
+		if !canTransition(original.State, to) {
+			issues = append(issues, transitionError(location, original, to))
+			return
+		}
+		msg.Transitions = append(msg.Transitions, Transition{
+			Transaction: ref,
+			From:        original.State,
+			To:          to,
+			Event:       msg.MsgDefIdr,
+			At:          msg.ReceivedAt,
+		})
+		applied[ref] = to
+	}
+
+	for i, tx := range report.TxInfAndSts {
+		if tx == nil || tx.TxSts == nil {
+			continue
+		}
+		location := fmt.Sprintf("%s/TxInfAndSts[%d]", root, i)
+
+		msgId := groupMsgId
+		if tx.OrgnlGrpInf != nil {
+			msgId = tx.OrgnlGrpInf.OrgnlMsgId
+		}
+		uetr := ""
+		if tx.OrgnlUETR != nil {
+			uetr = string(*tx.OrgnlUETR)
+		}
+		original, err := finder.FindTransaction(textValue(msgId), textValue(tx.OrgnlEndToEndId), uetr)
+		if err != nil {
+			return nil, err
+		}
+		if original == nil {
+			issues = append(issues, ValidationError{Location: location, Code: reasonUnknownOriginal, Severity: severityWarning,
+				Message: fmt.Sprintf("no pacs.008 transaction %s was received, status %s is not applied",
+					transactionReference(textValue(msgId), textValue(tx.OrgnlEndToEndId), uetr), *tx.TxSts)})
+			continue
+		}
+		reported[TransactionRef{MessageID: original.MessageID, Seq: original.Seq}] = true
+		if to, ok := reportedStates[string(*tx.TxSts)]; ok {
+			apply(location+"/TxSts", original, to)
+		}
+	}
+
+	for i, group := range report.OrgnlGrpInfAndSts {
+		if group == nil || group.GrpSts == nil {
+			continue
+		}
+		to, ok := reportedStates[string(*group.GrpSts)]
+		if !ok {
+			continue
+		}
+		location := fmt.Sprintf("%s/OrgnlGrpInfAndSts[%d]", root, i)
+
+		original, err := finder.FindMessage(textValue(group.OrgnlMsgId), "pacs.008")
+		if errors.Is(err, ErrMessageNotFound) {
+			issues = append(issues, ValidationError{Location: location + "/OrgnlMsgId", Code: reasonUnknownOriginal, Severity: severityWarning,
+				Message: fmt.Sprintf("no pacs.008 message %s was received, status %s is not applied", textValue(group.OrgnlMsgId), *group.GrpSts)})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		txs, err := finder.FindLifecycle(original.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range txs {
+			ref := TransactionRef{MessageID: original.ID, Seq: tx.Seq}
+			if reported[ref] {
+				continue
+			}
+			payment, err := finder.FindPayment(ref)
+			if err != nil {
+				return nil, err
+			}
+			if payment != nil {
+				apply(location+"/GrpSts", payment, to)
+			}
+		}
+	}
+	return issues, nil
+}
+
+// TransactionLifecycle is the current state and state history of a stored transaction
+type TransactionLifecycle struct {
+	Seq        int          `xml:"Seq" json:"Seq"`
+	EndToEndId string       `xml:"EndToEndId,omitempty" json:"EndToEndId,omitempty"`
+	UETR       string       `xml:"UETR,omitempty" json:"UETR,omitempty"`
+	State      string       `xml:"State" json:"State"`
+	History    []Transition `xml:"Transition" json:"History"`
+}
+
+// MessageLifecycle lists the lifecycle of every transaction of a stored message
+type MessageLifecycle struct {
+	XMLName      xml.Name               `xml:"Lifecycle" json:"-"`
+	MessageID    string                 `xml:"MessageId" json:"MessageId"`
+	Transactions []TransactionLifecycle `xml:"Transaction" json:"Transactions"`
+}
+
+// Return current state and state history of the transactions of a stored message,
+// found by ID, BizMsgIdr, MsgId, EndToEndId or UETR like getIso
+func getLifecycle(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ipReq := getIP(r)
+	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new Lifecycle Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
+
+	var response Response
+
+	format := acceptFormat(r)
+	id := mux.Vars(r)["id"]
+
+	msg, err := finder.Find(id)
+	if errors.Is(err, ErrMessageNotFound) {
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Message %s not found", id)
+		log.Print(response.Message)
+		responseFormatter(w, format, response, http.StatusNotFound)
+		return
+	}
+
+	var lifecycle []TransactionLifecycle
+	if err == nil {
+		lifecycle, err = finder.FindLifecycle(msg.ID)
+	}
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = "Error reading message"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
+	}
+	responseFormatter(w, format, MessageLifecycle{MessageID: msg.ID, Transactions: lifecycle}, http.StatusOK)
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	states := []string{"", stateReceived, stateValidated, stateAccepted, stateRejected, stateSettled, stateReturned, stateCancelled}
+	allowed := map[[2]string]bool{
+		{"", stateReceived}:             true,
+		{stateReceived, stateValidated}: true,
+		{stateReceived, stateRejected}:  true,
+		{stateValidated, stateAccepted}: true,
+		{stateValidated, stateRejected}: true,
+		{stateAccepted, stateSettled}:   true,
+		{stateAccepted, stateRejected}:  true,
+		{stateAccepted, stateReturned}:  true,
+		{stateAccepted, stateCancelled}: true,
+		{stateSettled, stateReturned}:   true,
+		{stateSettled, stateCancelled}:  true,
+	}
+	for _, from := range states {
+		for _, to := range states {
+			if got, want := canTransition(from, to), allowed[[2]string{from, to}]; got != want {
+				t.Errorf("canTransition(%q, %q) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+// transactionFinder finds the transactions of records and their messages, like a store holding them
+// Other MessageFinder methods are not implemented
+type transactionFinder struct {
+	MessageFinder
+	records []PaymentRecord
+}
+
+func (f transactionFinder) FindTransaction(msgId string, endToEndId string, uetr string) (*PaymentRecord, error) {
+	for _, record := range f.records {
+		if record.MsgId == msgId && record.EndToEndId == endToEndId {
+			return &record, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f transactionFinder) FindMessage(msgId string, name string) (*StoredMessage, error) {
+	for _, record := range f.records {
+		if record.MsgId == msgId {
+			return &StoredMessage{ID: record.MessageID, MsgId: msgId, MsgDefIdr: canonicalPacs008}, nil
+		}
+	}
+	return nil, ErrMessageNotFound
+}
+
+func (f transactionFinder) FindLifecycle(messageID string) ([]TransactionLifecycle, error) {
+	var lifecycle []TransactionLifecycle
+	for _, record := range f.records {
+		if record.MessageID == messageID {
+			lifecycle = append(lifecycle, TransactionLifecycle{Seq: record.Seq, EndToEndId: record.EndToEndId, State: record.State})
+		}
+	}
+	return lifecycle, nil
+}
+
+func (f transactionFinder) FindPayment(ref TransactionRef) (*PaymentRecord, error) {
+	for _, record := range f.records {
+		if record.MessageID == ref.MessageID && record.Seq == ref.Seq {
+			return &record, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestHandleStatusReport(t *testing.T) {
+	finder := transactionFinder{records: []PaymentRecord{
+		{MessageID: "m1", Seq: 0, MsgId: "M1", EndToEndId: "E1", State: stateAccepted},
+		{MessageID: "m1", Seq: 1, MsgId: "M1", EndToEndId: "E2", State: stateSettled},
+		{MessageID: "m1", Seq: 2, MsgId: "M1", EndToEndId: "E3", State: stateRejected},
+		{MessageID: "m2", Seq: 0, MsgId: "M2", EndToEndId: "F1", State: stateAccepted},
+		{MessageID: "m2", Seq: 1, MsgId: "M2", EndToEndId: "F2", State: stateAccepted},
+	}}
+	const tx = "/Document/FIToFIPmtStsRpt/TxInfAndSts"
+	const group = "/Document/FIToFIPmtStsRpt/OrgnlGrpInfAndSts[0]"
+
+	tests := []struct {
+		name            string
+		msgId           string // OrgnlMsgId of the report, M1 when empty
+		grpSts          string
+		statuses        [][2]string // EndToEndId and TxSts of each TxInfAndSts
+		wantTransitions []Transition
+		wantIssues      ValidationErrors
+		wantRejected    bool // issues hold errors, not only warnings
+	}{
+		{
+			name:            "settlement",
+			statuses:        [][2]string{{"E1", "ACSC"}},
+			wantTransitions: []Transition{{Transaction: TransactionRef{"m1", 0}, From: stateAccepted, To: stateSettled}},
+		},
+		{
+			name:            "rejection of an accepted transaction",
+			statuses:        [][2]string{{"E1", "RJCT"}},
+			wantTransitions: []Transition{{Transaction: TransactionRef{"m1", 0}, From: stateAccepted, To: stateRejected}},
+		},
+		{
+			name:         "rejection of a settled transaction",
+			statuses:     [][2]string{{"E2", "RJCT"}},
+			wantIssues:   ValidationErrors{{Location: tx + "[0]/TxSts", Code: reasonIncorrectContent}},
+			wantRejected: true,
+		},
+		{
+			name:         "settlement of a rejected transaction",
+			statuses:     [][2]string{{"E3", "ACCC"}},
+			wantIssues:   ValidationErrors{{Location: tx + "[0]/TxSts", Code: reasonIncorrectContent}},
+			wantRejected: true,
+		},
+		{
+			name:            "settlement and rejection in one report",
+			statuses:        [][2]string{{"E1", "ACSC"}, {"E1", "RJCT"}},
+			wantTransitions: []Transition{{Transaction: TransactionRef{"m1", 0}, From: stateAccepted, To: stateSettled}},
+			wantIssues:      ValidationErrors{{Location: tx + "[1]/TxSts", Code: reasonIncorrectContent}},
+			wantRejected:    true,
+		},
+		{
+			name:            "rejection and settlement in one report",
+			statuses:        [][2]string{{"E1", "RJCT"}, {"E1", "ACSC"}},
+			wantTransitions: []Transition{{Transaction: TransactionRef{"m1", 0}, From: stateAccepted, To: stateRejected}},
+			wantIssues:      ValidationErrors{{Location: tx + "[1]/TxSts", Code: reasonIncorrectContent}},
+			wantRejected:    true,
+		},
+		{
+			name:     "status leaving the transaction as it is",
+			statuses: [][2]string{{"E1", "ACTC"}, {"E1", "PDNG"}},
+		},
+		{
+			name:       "unknown transaction",
+			statuses:   [][2]string{{"E9", "ACSC"}},
+			wantIssues: ValidationErrors{{Location: tx + "[0]", Code: reasonUnknownOriginal}},
+		},
+		{
+			name:   "group settlement",
+			msgId:  "M2",
+			grpSts: "ACSC",
+			wantTransitions: []Transition{
+				{Transaction: TransactionRef{"m2", 0}, From: stateAccepted, To: stateSettled},
+				{Transaction: TransactionRef{"m2", 1}, From: stateAccepted, To: stateSettled},
+			},
+		},
+		{
+			name:     "group rejection overridden by a transaction status",
+			msgId:    "M2",
+			grpSts:   "RJCT",
+			statuses: [][2]string{{"F1", "ACCC"}},
+			wantTransitions: []Transition{
+				{Transaction: TransactionRef{"m2", 0}, From: stateAccepted, To: stateSettled},
+				{Transaction: TransactionRef{"m2", 1}, From: stateAccepted, To: stateRejected},
+			},
+		},
+		{
+			name:            "group rejection of a transaction reported pending",
+			msgId:           "M2",
+			grpSts:          "RJCT",
+			statuses:        [][2]string{{"F1", "PDNG"}},
+			wantTransitions: []Transition{{Transaction: TransactionRef{"m2", 1}, From: stateAccepted, To: stateRejected}},
+		},
+		{
+			name:            "group rejection of settled and rejected transactions",
+			grpSts:          "RJCT",
+			wantTransitions: []Transition{{Transaction: TransactionRef{"m1", 0}, From: stateAccepted, To: stateRejected}},
+			wantIssues: ValidationErrors{
+				{Location: group + "/GrpSts", Code: reasonIncorrectContent},
+				{Location: group + "/GrpSts", Code: reasonIncorrectContent},
+			},
+			wantRejected: true,
+		},
+		{
+			name:   "group status leaving transactions as they are",
+			msgId:  "M2",
+			grpSts: "ACTC",
+		},
+		{
+			name:       "group status of an unknown message",
+			msgId:      "M9",
+			grpSts:     "ACSC",
+			wantIssues: ValidationErrors{{Location: group + "/OrgnlMsgId", Code: reasonUnknownOriginal}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgId := tt.msgId
+			if msgId == "" {
+				msgId = "M1"
+			}
+			msg := statusReport(msgId, tt.grpSts, tt.statuses)
+			issues, err := handleStatusReport(finder, msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkIssues(t, issues, tt.wantIssues)
+			if issues.HasErrors() != tt.wantRejected {
+				t.Errorf("report rejected %v, want %v: %v", issues.HasErrors(), tt.wantRejected, issues)
+			}
+
+			if len(msg.Transitions) != len(tt.wantTransitions) {
+				t.Fatalf("got transitions %+v, want %+v", msg.Transitions, tt.wantTransitions)
+			}
+			for i, got := range msg.Transitions {
+				want := tt.wantTransitions[i]
+				want.Event, want.At = msg.MsgDefIdr, msg.ReceivedAt
+				if got != want {
+					t.Errorf("got transition %+v, want %+v", got, want)
+				}
+			}
+		})
+	}
+}
+
+// Build stored pacs.002 reporting status grpSts, when set, of message msgId and statuses of its transactions
+func statusReport(msgId string, grpSts string, statuses [][2]string) *StoredMessage {
+	report := &FIToFIPaymentStatusReportV10{
+		OrgnlGrpInfAndSts: []*OriginalGroupHeader17{{OrgnlMsgId: optionalText(msgId)}},
+	}
+	if grpSts != "" {
+		status := ExternalPaymentGroupStatus1Code(grpSts)
+		report.OrgnlGrpInfAndSts[0].GrpSts = &status
+	}
+	for _, status := range statuses {
+		txSts := ExternalPaymentTransactionStatus1Code(status[1])
+		report.TxInfAndSts = append(report.TxInfAndSts, &PaymentTransaction110{OrgnlEndToEndId: optionalText(status[0]), TxSts: &txSts})
+	}
+	msg := &BusMsg{}
+	msg.Document.FIToFIPmtStsRpt = report
+	return &StoredMessage{
+		MsgDefIdr:  "pacs.002.001.10",
+		ReceivedAt: time.Date(2021, 3, 2, 10, 0, 0, 0, time.UTC),
+		Message:    msg,
+	}
+}
+
+// Transactions go through Received, Validated and Accepted on arrival, or are saved as Rejected,
+// and pacs.002 reports move them further
+func TestIngestLifecycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		change    func(msg *BusMsg)
+		wantCode  int
+		report    bool // the pacs.002 sample settling the transaction is sent next
+		groupOnly bool // the pacs.002 sample is sent with its group status only
+		wantSteps []string
+	}{
+		{
+			name:      "accepted",
+			change:    func(msg *BusMsg) {},
+			wantCode:  http.StatusOK,
+			wantSteps: []string{stateReceived, stateValidated, stateAccepted},
+		},
+		{
+			name:      "accepted and settled",
+			change:    func(msg *BusMsg) {},
+			wantCode:  http.StatusOK,
+			report:    true,
+			wantSteps: []string{stateReceived, stateValidated, stateAccepted, stateSettled},
+		},
+		{
+			name:      "accepted and settled by group status",
+			change:    func(msg *BusMsg) {},
+			wantCode:  http.StatusOK,
+			report:    true,
+			groupOnly: true,
+			wantSteps: []string{stateReceived, stateValidated, stateAccepted, stateSettled},
+		},
+		{
+			name: "invalid",
+			change: func(msg *BusMsg) {
+				ccy := ActiveCurrencyCode("XXQ")
+				msg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0].IntrBkSttlmAmt.Ccy = &ccy
+			},
+			wantCode:  http.StatusBadRequest,
+			wantSteps: []string{stateReceived, stateRejected},
+		},
+		{
+			name: "invalid, then reported settled",
+			change: func(msg *BusMsg) {
+				nbOfTxs := Max15NumericText("2")
+				msg.Document.FIToFICstmrCdtTrf.GrpHdr.NbOfTxs = &nbOfTxs
+			},
+			wantCode:  http.StatusBadRequest,
+			report:    true,
+			wantSteps: []string{stateReceived, stateRejected},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestServer(t)
+
+			code, response := postIso(t, handler, "/iso20022", samplePayload(t, tt.change), nil)
+			if code != tt.wantCode || response.MessageId == "" {
+				t.Fatalf("got %d %+v, want %d and the saved message", code, response, tt.wantCode)
+			}
+			if tt.report {
+				report := decodeSample(t, "pacs002.json", formatJSON)
+				if tt.groupOnly {
+					report.BusMsg.Document.FIToFIPmtStsRpt.TxInfAndSts = nil
+				}
+				body, err := marshalBusMsg(report.BusMsg, formatJSON)
+				if err != nil {
+					t.Fatal(err)
+				}
+				postIso(t, handler, "/iso20022", body, nil)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/iso20022/"+response.MessageId+"/lifecycle", nil))
+			var lifecycle MessageLifecycle
+			if err := json.Unmarshal(w.Body.Bytes(), &lifecycle); err != nil || len(lifecycle.Transactions) != 1 {
+				t.Fatalf("got lifecycle %d %s", w.Code, w.Body)
+			}
+
+			tx := lifecycle.Transactions[0]
+			var steps []string
+			from := ""
+			for _, step := range tx.History {
+				if step.From != from {
+					t.Errorf("step to %s is from %q, want %q", step.To, step.From, from)
+				}
+				steps, from = append(steps, step.To), step.To
+			}
+			if len(steps) != len(tt.wantSteps) {
+				t.Fatalf("got steps %v, want %v", steps, tt.wantSteps)
+			}
+			for i := range steps {
+				if steps[i] != tt.wantSteps[i] {
+					t.Errorf("got steps %v, want %v", steps, tt.wantSteps)
+					break
+				}
+			}
+			if want := tt.wantSteps[len(tt.wantSteps)-1]; tx.State != want {
+				t.Errorf("got state %s, want %s", tx.State, want)
+			}
+		})
+	}
+}
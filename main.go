@@ -2,17 +2,44 @@ package main
 
 import (
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/gorilla/mux"
 	"io/ioutil"
 	"log"
+	"mime"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
+// store persists every accepted message, finder reads them back
+// ingestMu serializes duplicate checks with saving
+var (
+	store    MessageStore
+	finder   MessageFinder
+	ingestMu sync.Mutex
+)
+
+// Payload formats accepted and produced by ISO20022 endpoints
+const (
+	formatJSON = "json"
+	formatXML  = "xml"
+)
+
 func main() {
+	flag.DurationVar(&duplicateWindow, "duplicate-window", duplicateWindow, "how long message identifiers are checked for duplicates")
+	flag.BoolVar(&acceptUndeclaredReplays, "accept-undeclared-replays", acceptUndeclaredReplays, "answer replays without PssblDplct with the original acknowledgement and a warning instead of rejecting them with AM05")
+	flag.DurationVar(&idempotencyTTL, "idempotency-ttl", idempotencyTTL, "how long responses are kept for Idempotency-Key retries")
+	flag.BoolVar(&notifyDebtor, "notify-debtor", notifyDebtor, "also send camt.054 notifications to debited accounts")
+	sinkURL := flag.String("notification-sink", "", "URL camt.054 notifications are posted to, none when empty")
+	currencyTable := flag.String("currency-table", "", "ISO 4217 table file replacing the embedded one, in the format of iso4217.csv")
+	flag.Parse()
+
 	// Setting up log file
 	// set permission to read/write log file
 	// read/write to existing log file, if there is none it will create new log file
@@ -22,6 +49,31 @@ func main() {
 	}
 	log.SetOutput(file)
 
+	if *currencyTable != "" {
+		if err := LoadCurrencyFile(*currencyTable); err != nil {
+			log.Fatal("Found error in currency table ", err)
+		}
+		log.Println("Currency table loaded from", *currencyTable)
+	}
+
+	// Setting up message store, accepted messages are saved in parsed/ and in messages.db
+	fileStore, err := NewFileStore("parsed")
+	if err != nil {
+		log.Fatal("Found error in message store ", err)
+	}
+	sqlStore, err := NewSQLStore("messages.db")
+	if err != nil {
+		log.Fatal("Found error in message database ", err)
+	}
+	defer sqlStore.Close()
+	store = MultiStore{fileStore, sqlStore}
+	finder = sqlStore
+	idempotency = sqlStore
+	deliveries = sqlStore
+	if *sinkURL != "" {
+		notificationSink = &HTTPSink{URL: *sinkURL, Client: &http.Client{Timeout: 10 * time.Second}}
+	}
+
 	// Setting up HTTP Listener and Handler
 	// router will handle any request at any endpoint available in server()
 	router := pathHandler()
@@ -41,7 +93,19 @@ func pathHandler() *mux.Router {
 	router := mux.NewRouter()
 
 	// Endpoints, Handler function, and HTTP request Method
-	router.HandleFunc("/iso20022", parseIso).Methods("POST")
+	router.HandleFunc("/iso20022", idempotent(parseIso)).Methods("POST")
+	router.HandleFunc("/iso20022/return", idempotent(parseReturn)).Methods("POST")
+	router.HandleFunc("/iso20022/cancellation", idempotent(parseCancellation)).Methods("POST")
+	router.HandleFunc("/iso20022/status", parseStatusRequest).Methods("POST")
+	router.HandleFunc("/iso20022", listIso).Methods("GET")
+	router.HandleFunc("/iso20022/{id}", getIso).Methods("GET")
+	router.HandleFunc("/iso20022/{id}/lifecycle", getLifecycle).Methods("GET")
+	router.HandleFunc("/convert", convertIso).Methods("POST")
+	router.HandleFunc("/cases/{id}", getCase).Methods("GET")
+	router.HandleFunc("/cases/{id}/resolution", getResolution).Methods("GET")
+	router.HandleFunc("/cases/{id}/resolution", resolveCase).Methods("POST")
+	router.HandleFunc("/statements/{account}", getStatement).Methods("GET")
+	router.HandleFunc("/notifications", listNotifications).Methods("GET")
 
 	return router
 }
@@ -51,78 +115,506 @@ func parseIso(w http.ResponseWriter, r *http.Request) {
 	ipReq := getIP(r)
 	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new ISO20022 Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
 
-	// Get request body JSON
+	ingestIso(w, r, start, "")
+}
+
+func parseReturn(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ipReq := getIP(r)
+	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new Payment Return Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
+
+	ingestIso(w, r, start, "PmtRtr")
+}
+
+func parseCancellation(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ipReq := getIP(r)
+	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new Payment Cancellation Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
+
+	ingestIso(w, r, start, "FIToFIPmtCxlReq")
+}
+
+// Answer a pacs.028 status request with a pacs.002 holding the current status of the messages and transactions it asks for
+// The request itself is not saved, it can be repeated as often as needed
+func parseStatusRequest(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ipReq := getIP(r)
+	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new Payment Status Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
+
+	var response Response
+
+	format := requestFormat(r)
 	body, _ := ioutil.ReadAll(r.Body)
-	//fmt.Printf("%+v", string(body))
-	var request Iso20022
+
+	request, _, _, ok := decodeRequest(w, r, format, body, "FIToFIPmtStsReq")
+	if !ok {
+		return
+	}
+
+	answer, err := AnswerStatusRequest(finder, request.BusMsg)
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = "Error checking stored messages"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Status request answered with %s", answer.AppHdr.BizMsgIdr)
+	writeBusMsg(w, format, answer, http.StatusOK)
+}
+
+// Decode, validate, check and save a message received at start, as the message type its AppHdr.MsgDefIdr names
+// When root is set only message types held in that Document element are accepted
+func ingestIso(w http.ResponseWriter, r *http.Request, start time.Time, root string) {
+	ipReq := getIP(r)
+
 	var response Response
 
-	err := json.Unmarshal(body, &request)
+	// Negotiate request format, the response is written in the same format
+	format := requestFormat(r)
+
+	// Get request body JSON/XML
+	body, _ := ioutil.ReadAll(r.Body)
+
+	request, msgType, issues, ok := decodeRequest(w, r, format, body, root)
+	if !ok {
+		return
+	}
+	checkedAt := time.Now()
+	response.Issues = issues
+
+	doc, err := marshalIndent(request.BusMsg.Document, format)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error unmarshal JSON: %s", err.Error())
-		log.Printf(response.Message)
-		responseFormatter(w, response, http.StatusInternalServerError)
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Error MarshalIndent %s: %s", strings.ToUpper(format), err.Error())
+		log.Print(response.Message)
+		responseFormatter(w, format, response, http.StatusInternalServerError)
 		return
 	}
-	fmt.Printf("%+v\n", request)
 
-	doc, err := json.MarshalIndent(request.BusMsg.Document, "", "  ")
+	// save to message store
+	msg := &StoredMessage{
+		BizMsgIdr:  string(request.BusMsg.AppHdr.BizMsgIdr),
+		MsgDefIdr:  string(request.BusMsg.AppHdr.MsgDefIdr),
+		Sender:     partyId(request.BusMsg.AppHdr.Fr),
+		ClientIP:   ipReq,
+		Format:     format,
+		Status:     statusAccepted,
+		ReceivedAt: start,
+		Payload:    body,
+		Document:   doc,
+		Message:    &request.BusMsg,
+	}
+	if group, _ := summarize(request.BusMsg.Document); group != nil {
+		msg.MsgId = textValue(group.MsgId)
+	}
+	msg.Digest, err = documentDigest(request.BusMsg.Document)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error MarshalIndent JSON: %s", err.Error())
-		log.Printf(response.Message)
-		responseFormatter(w, response, http.StatusInternalServerError)
+		response.Status = statusRejected
+		response.Message = "Error digesting message"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
 		return
 	}
-	//log.Printf("\n\nDocument: %s\n\n", string(doc))
 
-	// save as file
-	filename := fmt.Sprintf("parsed/%v@%v", ipReq, time.Now().Format("15:04:05"))
-	CreateFile(filename, string(doc))
+	// Transactions keeping a lifecycle are Received on arrival, then Validated or Rejected by the checks decodeRequest made
+	if msgType.Lifecycle {
+		moveTransactions(msg, "", stateReceived, start)
+		if issues.HasErrors() {
+			response.Message = "Validation failed"
+			log.Printf("%s: %s", response.Message, issues.Error())
+			saveRejected(w, r, format, msg, stateReceived, response)
+			return
+		}
+		moveTransactions(msg, stateReceived, stateValidated, checkedAt)
+	}
+
+	// Check against stored messages and save as one step, so concurrent replays or returns are not both accepted
+	ingestMu.Lock()
+	defer ingestMu.Unlock()
+
+	duplicates, err := finder.FindDuplicates(msg, start.Add(-duplicateWindow))
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = "Error checking duplicates"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
+	}
+	original, conflicts := checkDuplicates(msg, duplicates)
+	if len(conflicts) > 0 {
+		response.Status = statusRejected
+		response.Message = "Duplicate identifier with different content"
+		response.Issues = append(response.Issues, conflicts...)
+		log.Printf("%s: %s", response.Message, conflicts.Error())
+		replyIso(w, r, format, request.BusMsg, response, http.StatusConflict)
+		return
+	}
+	if original != nil {
+		// Replay of an accepted message, answer with its acknowledgement and save nothing
+		if !request.BusMsg.AppHdr.PssblDplct {
+			undeclared := ValidationError{
+				Location: "/AppHdr/PssblDplct",
+				Code:     reasonDuplication,
+				Severity: severityError,
+				Message:  fmt.Sprintf("message is a replay of %s but PssblDplct is not set", original.ID),
+			}
+			if !acceptUndeclaredReplays {
+				response.Status = statusRejected
+				response.Message = "Duplicate message not flagged PssblDplct"
+				response.Issues = append(response.Issues, undeclared)
+				log.Printf("%s: %s", response.Message, undeclared.Message)
+				replyIso(w, r, format, request.BusMsg, response, http.StatusConflict)
+				return
+			}
+			undeclared.Severity = severityWarning
+			response.Issues = append(response.Issues, undeclared)
+		}
+		response.Status = original.Status
+		response.Message = "Parsing Success"
+		response.MessageId = original.ID
+		log.Printf("Message is a replay of %s", original.ID)
+		replyIso(w, r, format, request.BusMsg, response, http.StatusOK)
+		return
+	}
+	if request.BusMsg.AppHdr.PssblDplct {
+		log.Printf("Message flagged PssblDplct has no original within %s, processed as new", duplicateWindow)
+	}
+
+	// message type checks against stored messages, e.g. a return must refer to the transaction it returns
+	if msgType.Handle != nil {
+		transitions := msg.Transitions
+		issues, err := msgType.Handle(finder, msg)
+		if err != nil {
+			response.Status = statusRejected
+			response.Message = "Error checking stored messages"
+			log.Printf("%s: %s", response.Message, err.Error())
+			responseFormatter(w, format, response, http.StatusInternalServerError)
+			return
+		}
+		if issues.HasErrors() {
+			response.Status = statusRejected
+			response.Message = "Validation failed"
+			response.Issues = append(response.Issues, issues...)
+			log.Printf("%s: %s", response.Message, issues.Error())
+			if msgType.Lifecycle {
+				msg.Transitions, msg.Notifications = transitions, nil
+				saveRejected(w, r, format, msg, stateValidated, response)
+				return
+			}
+			replyIso(w, r, format, request.BusMsg, response, http.StatusBadRequest)
+			return
+		}
+	}
 
+	if err := store.Save(msg); err != nil {
+		response.Status = statusRejected
+		response.Message = "Error saving message"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Message saved as %s", msg.ID)
+	if len(msg.Notifications) > 0 {
+		go deliverNotifications(notificationSink, msg.Notifications)
+	}
+
+	response.Status = statusAccepted
 	response.Message = "Parsing Success"
-	responseFormatter(w, response, http.StatusOK)
+	response.MessageId = msg.ID
+	replyIso(w, r, format, request.BusMsg, response, http.StatusOK)
 
 }
 
-// Return ip address for client request
-func getIP(r *http.Request) string {
-	forwarded := r.Header.Get("X-FORWARDED-FOR")
-	if forwarded != "" {
-		return forwarded
+// Decode body as the message type its AppHdr.MsgDefIdr names and validate it, writing the rejection when that fails
+// When root is set only message types held in that Document element are accepted
+// Warnings found on the way are returned with the message, as are validation errors of message types keeping a lifecycle,
+// for the rejection to be saved with the message
+func decodeRequest(w http.ResponseWriter, r *http.Request, format string, body []byte, root string) (Iso20022, *MessageType, ValidationErrors, bool) {
+	var response Response
+
+	// Find message type from the header, then decode the whole message as that type
+	header, namespace, err := decodeHeader(body, format)
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Error unmarshal %s", strings.ToUpper(format))
+		response.Issues = decodeIssues(err)
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusBadRequest)
+		return Iso20022{}, nil, nil, false
 	}
-	return r.RemoteAddr
+	msgType := detectMessageType(header, namespace)
+	if msgType == nil || msgType.DecodeOnly || (root != "" && msgType.Root != root) || (root == "" && msgType.Endpoint != "") {
+		response.Status = statusRejected
+		response.Message = "Unsupported message"
+		response.Issues = ValidationErrors{{
+			Location: "/AppHdr/MsgDefIdr",
+			Code:     reasonIncorrectContent,
+			Severity: severityError,
+			Message:  fmt.Sprintf("MsgDefIdr %q is not one of %s", header.MsgDefIdr, strings.Join(messageDefinitions(), ", ")),
+		}}
+		if msgType != nil && msgType.DecodeOnly {
+			response.Issues[0].Message = fmt.Sprintf("MsgDefIdr %q is only sent by this service, not accepted", header.MsgDefIdr)
+		} else if msgType != nil && root == "" {
+			response.Issues[0].Message = fmt.Sprintf("MsgDefIdr %q is only accepted at %s", header.MsgDefIdr, msgType.Endpoint)
+		} else if msgType != nil {
+			response.Issues[0].Message = fmt.Sprintf("MsgDefIdr %q is not accepted here, %s expected", header.MsgDefIdr, root)
+		}
+		log.Printf("%s: %s", response.Message, response.Issues.Error())
+		replyIso(w, r, format, BusMsg{AppHdr: header}, response, http.StatusBadRequest)
+		return Iso20022{}, nil, nil, false
+	}
+
+	request, report, err := msgType.Decode(body, format)
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Error unmarshal %s", strings.ToUpper(format))
+		response.Issues = decodeIssues(err)
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusBadRequest)
+		return Iso20022{}, nil, nil, false
+	}
+
+	// Check XSD facets and business rules of the message type, reject message listing every violation
+	// other versions are converted to the canonical model, reporting what was dropped or defaulted
+	response.Issues = append(report, ValidateIso(request)...)
+
+	if response.Issues.HasErrors() && !msgType.Lifecycle {
+		response.Status = statusRejected
+		response.Message = "Validation failed"
+		log.Printf("%s: %s", response.Message, response.Issues.Error())
+		replyIso(w, r, format, request.BusMsg, response, http.StatusBadRequest)
+		return Iso20022{}, nil, nil, false
+	}
+	return request, msgType, response.Issues, true
 }
 
-// Response formatter
-func responseFormatter(w http.ResponseWriter, data interface{}, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(data)
+// Save msg, rejected in state from, then write the rejection response lists
+// Its transactions move to Rejected, the rejected message can be retrieved like accepted ones
+func saveRejected(w http.ResponseWriter, r *http.Request, format string, msg *StoredMessage, from string, response Response) {
+	msg.Status = statusRejected
+	moveTransactions(msg, from, stateRejected, time.Now())
+
+	response.Status = statusRejected
+	if err := store.Save(msg); err != nil {
+		response.Message = "Error saving message"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Rejected message saved as %s", msg.ID)
+
+	response.MessageId = msg.ID
+	replyIso(w, r, format, *msg.Message, response, http.StatusBadRequest)
 }
 
-// Create file for request/response
-func CreateFile(fileName string, content string) string {
+// Write reply to a parsed message: Response, or a pacs.002 status report when asked for with ?reply=pacs.002
+func replyIso(w http.ResponseWriter, r *http.Request, format string, msg BusMsg, response Response, statusCode int) {
+	if r.URL.Query().Get("reply") != "pacs.002" {
+		responseFormatter(w, format, response, statusCode)
+		return
+	}
 
-	log.Println("Creating new file")
+	writeBusMsg(w, format, NewStatusReport(msg, response.Status, response.Issues), statusCode)
+}
+
+// List stored payments matching query parameters, see parsePaymentFilter
+// Next page is fetched by passing NextCursor as cursor parameter
+func listIso(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ipReq := getIP(r)
+	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new Search Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
+
+	var response Response
+
+	format := acceptFormat(r)
+
+	filter, err := parsePaymentFilter(r.URL.Query())
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Invalid search parameter: %s", err.Error())
+		log.Print(response.Message)
+		responseFormatter(w, format, response, http.StatusBadRequest)
+		return
+	}
 
-	if !strings.Contains(fileName, ".json") {
-		fileName += ".json"
+	page, err := finder.Search(filter)
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = "Error searching messages"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
 	}
+	responseFormatter(w, format, page, http.StatusOK)
+}
 
-	file, err := os.Create(fileName)
+// Return stored message by BizMsgIdr, MsgId, EndToEndId or UETR
+// Response holds the original payload and its parsed form, in the format asked for by Accept header
+func getIso(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ipReq := getIP(r)
+	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new Retrieve Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
 
+	var response Response
+
+	format := acceptFormat(r)
+	id := mux.Vars(r)["id"]
+
+	msg, err := finder.Find(id)
+	if errors.Is(err, ErrMessageNotFound) {
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Message %s not found", id)
+		log.Print(response.Message)
+		responseFormatter(w, format, response, http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		log.Fatalf("Failed creating file: %s", err)
+		response.Status = statusRejected
+		response.Message = "Error reading message"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
 	}
 
-	defer file.Close()
+	request, _, _, err := decodeMessage(msg.Payload, msg.Format)
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Error unmarshal stored %s", strings.ToUpper(msg.Format))
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
+	}
+	setHeadNamespace(&request.BusMsg)
+
+	record := MessageRecord{
+		ID:         msg.ID,
+		BizMsgIdr:  msg.BizMsgIdr,
+		MsgDefIdr:  msg.MsgDefIdr,
+		MsgId:      msg.MsgId,
+		ClientIP:   msg.ClientIP,
+		Format:     msg.Format,
+		Status:     msg.Status,
+		ReceivedAt: msg.ReceivedAt,
+		Payload:    string(msg.Payload),
+		BusMsg:     &request.BusMsg,
+	}
+	responseFormatter(w, format, record, http.StatusOK)
+}
 
-	_, err = file.WriteString(content)
+// Convert BusMsg from JSON into ISO 20022 XML and vice versa
+func convertIso(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ipReq := getIP(r)
+	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new Convert Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
+
+	var response Response
 
+	// Convert JSON to XML and back, or with msgDefIdr to another version of the message in the same format
+	msgDefIdr := r.URL.Query().Get("msgDefIdr")
+	from := requestFormat(r)
+	to := otherFormat(from)
+	if msgDefIdr != "" {
+		to = from
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+
+	out, report, err := ConvertIsoVersion(body, from, to, msgDefIdr)
 	if err != nil {
-		log.Fatalf("Failed writing to file: %s", err)
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Error converting %s to %s", strings.ToUpper(from), strings.ToUpper(to))
+		response.Issues = decodeIssues(err)
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, from, response, http.StatusBadRequest)
+		return
 	}
 
-	log.Println("File created!")
-	return fileName
+	contentType := "application/json"
+	if to == formatXML {
+		contentType = "application/xml"
+	}
+	w.Header().Set("Content-Type", contentType)
+	for _, issue := range report {
+		w.Header().Add("Warning", fmt.Sprintf("199 - %q", issue.Location+": "+issue.Message))
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(out)
+}
+
+// Return payload format of client request based on its Content-Type header
+// Anything other than an XML media type is treated as JSON
+func requestFormat(r *http.Request) string {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return formatJSON
+	}
+
+	if mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml") {
+		return formatXML
+	}
+	return formatJSON
+}
+
+// Return response format asked for by Accept header, the first JSON or XML media type wins
+// JSON is returned when Accept has neither
+func acceptFormat(r *http.Request) string {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		if mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml") {
+			return formatXML
+		}
+		if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			return formatJSON
+		}
+	}
+	return formatJSON
+}
+
+// Decode JSON or XML request body into Iso20022
+// XML payload is a BusMsg envelope holding AppHdr and Document
+func decodeIso(body []byte, format string) (Iso20022, error) {
+	var request Iso20022
+	err := unmarshalPayload(body, format, &request.BusMsg)
+	return request, err
+}
+
+// Marshal data as indented JSON or XML
+func marshalIndent(data interface{}, format string) ([]byte, error) {
+	if format == formatXML {
+		out, err := xml.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(xml.Header), out...), nil
+	}
+
+	return json.MarshalIndent(data, "", "  ")
+}
+
+// Return ip address for client request
+func getIP(r *http.Request) string {
+	forwarded := r.Header.Get("X-FORWARDED-FOR")
+	if forwarded != "" {
+		return forwarded
+	}
+	return r.RemoteAddr
+}
+
+// Response formatter
+func responseFormatter(w http.ResponseWriter, format string, data interface{}, statusCode int) {
+	if format == formatXML {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(statusCode)
+		w.Write([]byte(xml.Header))
+		xml.NewEncoder(w).Encode(data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
 }
@@ -4,11 +4,45 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/xml"
+	"fmt"
+	"strings"
 	"time"
 )
 
+// XML namespace written on AppHdr when converting from JSON
+const headNamespace = "urn:iso:std:iso:20022:tech:xsd:head.001.001.02"
+
+// XML namespace of FIToFICstmrCdtTrf Document
+const pacs008Namespace = "urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09"
+
+// Overall status of a processed message
+const (
+	statusAccepted = "ACTC"
+	statusRejected = "RJCT"
+)
+
+// Response is the machine-readable report returned for every request
+// MessageId is the stored message the request was accepted as
 type Response struct {
-	Message string `json:"Message"`
+	Status    string           `xml:"Status" json:"Status"`
+	Message   string           `xml:"Message" json:"Message"`
+	MessageId string           `xml:"MessageId,omitempty" json:"MessageId,omitempty"`
+	Issues    ValidationErrors `xml:"Issue,omitempty" json:"Issues,omitempty"`
+}
+
+// MessageRecord is a stored message as returned by GET /iso20022/{id}
+type MessageRecord struct {
+	XMLName    xml.Name  `xml:"Message" json:"-"`
+	ID         string    `xml:"Id" json:"Id"`
+	BizMsgIdr  string    `xml:"BizMsgIdr" json:"BizMsgIdr"`
+	MsgDefIdr  string    `xml:"MsgDefIdr" json:"MsgDefIdr"`
+	MsgId      string    `xml:"MsgId,omitempty" json:"MsgId,omitempty"`
+	ClientIP   string    `xml:"ClientIP" json:"ClientIP"`
+	Format     string    `xml:"Format" json:"Format"`
+	Status     string    `xml:"Status" json:"Status"`
+	ReceivedAt time.Time `xml:"ReceivedAt" json:"ReceivedAt"`
+	Payload    string    `xml:"Payload" json:"Payload"` // request body as received
+	BusMsg     *BusMsg   `xml:"BusMsg" json:"BusMsg"`   // parsed form of Payload
 }
 
 type Iso20022 struct {
@@ -16,438 +50,756 @@ type Iso20022 struct {
 }
 
 type BusMsg struct {
-	AppHdr   AppHdr   `json:"AppHdr"`
-	Document Document `json:"Document"`
+	XMLName  xml.Name `xml:"BusMsg" json:"-"`
+	AppHdr   AppHdr   `xml:"AppHdr" json:"AppHdr"`
+	Document Document `xml:"Document" json:"Document"`
 }
 
+// AppHdr is the business application header (head.001.001.02)
+// It keeps the namespace it was decoded with, headNamespace is used when it has none
 type AppHdr struct {
-	BizMsgIdr string `json:"BizMsgIdr"`
-	MsgDefIdr string `json:"MsgDefIdr"`
-	CreDt     string `json:"CreDt"`
+	XMLName    xml.Name                      `json:"-"`
+	CharSet    *UnicodeChartsCode            `xml:"CharSet,omitempty" json:"CharSet,omitempty"`
+	Fr         *Party44Choice                `xml:"Fr" json:"Fr"`
+	To         *Party44Choice                `xml:"To" json:"To"`
+	BizMsgIdr  Max35Text                     `xml:"BizMsgIdr" json:"BizMsgIdr"`
+	MsgDefIdr  Max35Text                     `xml:"MsgDefIdr" json:"MsgDefIdr"`
+	BizSvc     *Max35Text                    `xml:"BizSvc,omitempty" json:"BizSvc,omitempty"`
+	MktPrctc   *ImplementationSpecification1 `xml:"MktPrctc,omitempty" json:"MktPrctc,omitempty"`
+	CreDt      ISODateTime                   `xml:"CreDt" json:"CreDt"`
+	BizPrcgDt  *ISODateTime                  `xml:"BizPrcgDt,omitempty" json:"BizPrcgDt,omitempty"`
+	CpyDplct   *CopyDuplicate1Code           `xml:"CpyDplct,omitempty" json:"CpyDplct,omitempty"`
+	PssblDplct bool                          `xml:"PssblDplct,omitempty" json:"PssblDplct,omitempty"`
+	Prty       *BusinessMessagePriorityCode  `xml:"Prty,omitempty" json:"Prty,omitempty"`
+	Sgntr      *SignatureEnvelope            `xml:"Sgntr,omitempty" json:"Sgntr,omitempty"`
+	Rltd       []*BusinessApplicationHeader5 `xml:"Rltd,omitempty" json:"Rltd,omitempty"`
 }
 
 type AccountIdentification4Choice struct {
-	IBAN *IBAN2007Identifier            `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 IBAN,omitempty" json:"IBAN,omitempty"`
-	Othr *GenericAccountIdentification1 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Othr,omitempty" json:"Othr,omitempty"`
+	IBAN *IBAN2007Identifier            `xml:"IBAN,omitempty" json:"IBAN,omitempty"`
+	Othr *GenericAccountIdentification1 `xml:"Othr,omitempty" json:"Othr,omitempty"`
 }
 
 type AccountSchemeName1Choice struct {
-	Cd    *ExternalAccountIdentification1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalAccountIdentification1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                          `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type ActiveCurrencyAndAmount struct {
-	Value float64             `xml:",chardata" json:"Value,string"`
+	Value Decimal             `xml:",chardata" json:"Value"`
 	Ccy   *ActiveCurrencyCode `xml:"Ccy,attr" json:"Ccy"`
 }
 
+func (a ActiveCurrencyAndAmount) Validate() error {
+	if err := checkAmount(a.Value); err != nil {
+		return err
+	}
+	if a.Ccy == nil {
+		return nil
+	}
+	return checkMinorUnits(a.Value, string(*a.Ccy))
+}
+
 // ActiveCurrencyCode Must match the pattern [A-Z]{3,3}
 type ActiveCurrencyCode string
 
+func (v ActiveCurrencyCode) Validate() error {
+	if err := checkPattern(string(v), `[A-Z]{3,3}`); err != nil {
+		return err
+	}
+	return checkCurrency(string(v), false)
+}
+
 type ActiveOrHistoricCurrencyAndAmount struct {
-	Value float64                       `xml:",chardata" json:"Value,string"`
+	Value Decimal                       `xml:",chardata" json:"Value"`
 	Ccy   *ActiveOrHistoricCurrencyCode `xml:"Ccy,attr" json:"Ccy"`
 }
 
+func (a ActiveOrHistoricCurrencyAndAmount) Validate() error {
+	if err := checkAmount(a.Value); err != nil {
+		return err
+	}
+	if a.Ccy == nil {
+		return nil
+	}
+	return checkMinorUnits(a.Value, string(*a.Ccy))
+}
+
 // ActiveOrHistoricCurrencyCode Must match the pattern [A-Z]{3,3}
 type ActiveOrHistoricCurrencyCode string
 
+func (v ActiveOrHistoricCurrencyCode) Validate() error {
+	if err := checkPattern(string(v), `[A-Z]{3,3}`); err != nil {
+		return err
+	}
+	return checkCurrency(string(v), true)
+}
+
 // AddressType2Code May be one of ADDR, PBOX, HOME, BIZZ, MLTO, DLVY
 type AddressType2Code string
 
+func (v AddressType2Code) Validate() error {
+	return checkEnumeration(string(v), "ADDR", "PBOX", "HOME", "BIZZ", "MLTO", "DLVY")
+}
+
 type AddressType3Choice struct {
-	Cd    *AddressType2Code        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *GenericIdentification30 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *AddressType2Code        `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *GenericIdentification30 `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 // AnyBICDec2014Identifier Must match the pattern [A-Z0-9]{4,4}[A-Z]{2,2}[A-Z0-9]{2,2}([A-Z0-9]{3,3}){0,1}
 type AnyBICDec2014Identifier string
 
+func (v AnyBICDec2014Identifier) Validate() error {
+	if err := checkPattern(string(v), `[A-Z0-9]{4,4}[A-Z]{2,2}[A-Z0-9]{2,2}([A-Z0-9]{3,3}){0,1}`); err != nil {
+		return err
+	}
+	return checkBIC(string(v))
+}
+
+// BaseOneRate May be no more than 11 digits long, with no more than 10 fraction digits
+type BaseOneRate Decimal
+
+func (d *BaseOneRate) UnmarshalText(text []byte) error {
+	return (*Decimal)(d).UnmarshalText(text)
+}
+func (d BaseOneRate) MarshalText() ([]byte, error) {
+	return Decimal(d).MarshalText()
+}
+func (d *BaseOneRate) UnmarshalJSON(data []byte) error {
+	return (*Decimal)(d).UnmarshalJSON(data)
+}
+func (d BaseOneRate) MarshalJSON() ([]byte, error) {
+	return Decimal(d).MarshalJSON()
+}
+func (d BaseOneRate) Validate() error {
+	return checkDigits(Decimal(d), 11, 10)
+}
+
 // BICFIDec2014Identifier Must match the pattern [A-Z0-9]{4,4}[A-Z]{2,2}[A-Z0-9]{2,2}([A-Z0-9]{3,3}){0,1}
 type BICFIDec2014Identifier string
 
+func (v BICFIDec2014Identifier) Validate() error {
+	if err := checkPattern(string(v), `[A-Z0-9]{4,4}[A-Z]{2,2}[A-Z0-9]{2,2}([A-Z0-9]{3,3}){0,1}`); err != nil {
+		return err
+	}
+	return checkBIC(string(v))
+}
+
 type BranchAndFinancialInstitutionIdentification6 struct {
-	FinInstnId *FinancialInstitutionIdentification18 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 FinInstnId" json:"FinInstnId"`
-	BrnchId    *BranchData3                          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 BrnchId,omitempty" json:"BrnchId,omitempty"`
+	FinInstnId *FinancialInstitutionIdentification18 `xml:"FinInstnId" json:"FinInstnId"`
+	BrnchId    *BranchData3                          `xml:"BrnchId,omitempty" json:"BrnchId,omitempty"`
 }
 
 type BranchData3 struct {
-	Id      *Max35Text       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Id,omitempty" json:"Id,omitempty"`
-	LEI     *LEIIdentifier   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 LEI,omitempty" json:"LEI,omitempty"`
-	Nm      *Max140Text      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Nm,omitempty" json:"Nm,omitempty"`
-	PstlAdr *PostalAddress24 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PstlAdr,omitempty" json:"PstlAdr,omitempty"`
+	Id      *Max35Text       `xml:"Id,omitempty" json:"Id,omitempty"`
+	LEI     *LEIIdentifier   `xml:"LEI,omitempty" json:"LEI,omitempty"`
+	Nm      *Max140Text      `xml:"Nm,omitempty" json:"Nm,omitempty"`
+	PstlAdr *PostalAddress24 `xml:"PstlAdr,omitempty" json:"PstlAdr,omitempty"`
+}
+
+type BusinessApplicationHeader5 struct {
+	CharSet    *UnicodeChartsCode           `xml:"CharSet,omitempty" json:"CharSet,omitempty"`
+	Fr         *Party44Choice               `xml:"Fr" json:"Fr"`
+	To         *Party44Choice               `xml:"To" json:"To"`
+	BizMsgIdr  *Max35Text                   `xml:"BizMsgIdr" json:"BizMsgIdr"`
+	MsgDefIdr  *Max35Text                   `xml:"MsgDefIdr" json:"MsgDefIdr"`
+	BizSvc     *Max35Text                   `xml:"BizSvc,omitempty" json:"BizSvc,omitempty"`
+	CreDt      *ISODateTime                 `xml:"CreDt" json:"CreDt"`
+	CpyDplct   *CopyDuplicate1Code          `xml:"CpyDplct,omitempty" json:"CpyDplct,omitempty"`
+	PssblDplct bool                         `xml:"PssblDplct,omitempty" json:"PssblDplct,omitempty"`
+	Prty       *BusinessMessagePriorityCode `xml:"Prty,omitempty" json:"Prty,omitempty"`
+	Sgntr      *SignatureEnvelope           `xml:"Sgntr,omitempty" json:"Sgntr,omitempty"`
+}
+
+// BusinessMessagePriorityCode May be no more than 4 items long
+type BusinessMessagePriorityCode string
+
+func (v BusinessMessagePriorityCode) Validate() error {
+	return checkLength(string(v), 1, 4)
 }
 
 type CashAccount38 struct {
-	Id   *AccountIdentification4Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Id" json:"Id"`
-	Tp   *CashAccountType2Choice       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp,omitempty" json:"Tp,omitempty"`
-	Ccy  *ActiveOrHistoricCurrencyCode `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Ccy,omitempty" json:"Ccy,omitempty"`
-	Nm   *Max70Text                    `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Nm,omitempty" json:"Nm,omitempty"`
-	Prxy *ProxyAccountIdentification1  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prxy,omitempty" json:"Prxy,omitempty"`
+	Id   *AccountIdentification4Choice `xml:"Id" json:"Id"`
+	Tp   *CashAccountType2Choice       `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	Ccy  *ActiveOrHistoricCurrencyCode `xml:"Ccy,omitempty" json:"Ccy,omitempty"`
+	Nm   *Max70Text                    `xml:"Nm,omitempty" json:"Nm,omitempty"`
+	Prxy *ProxyAccountIdentification1  `xml:"Prxy,omitempty" json:"Prxy,omitempty"`
 }
 
 type CashAccountType2Choice struct {
-	Cd    *ExternalCashAccountType1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                    `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalCashAccountType1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                    `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type CategoryPurpose1Choice struct {
-	Cd    *ExternalCategoryPurpose1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                    `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalCategoryPurpose1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                    `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 // ChargeBearerType1Code May be one of DEBT, CRED, SHAR, SLEV
 type ChargeBearerType1Code string
 
+func (v ChargeBearerType1Code) Validate() error {
+	return checkEnumeration(string(v), "DEBT", "CRED", "SHAR", "SLEV")
+}
+
 type Charges7 struct {
-	Amt *ActiveOrHistoricCurrencyAndAmount            `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Amt" json:"Amt"`
-	Agt *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Agt" json:"Agt"`
+	Amt *ActiveOrHistoricCurrencyAndAmount            `xml:"Amt" json:"Amt"`
+	Agt *BranchAndFinancialInstitutionIdentification6 `xml:"Agt" json:"Agt"`
 }
 
 // ClearingChannel2Code May be one of RTGS, RTNS, MPNS, BOOK
 type ClearingChannel2Code string
 
+func (v ClearingChannel2Code) Validate() error {
+	return checkEnumeration(string(v), "RTGS", "RTNS", "MPNS", "BOOK")
+}
+
 type ClearingSystemIdentification2Choice struct {
-	Cd    *ExternalClearingSystemIdentification1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd"`
-	Prtry *Max35Text                                 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry"`
+	Cd    *ExternalClearingSystemIdentification1Code `xml:"Cd,omitempty" json:"Cd"`
+	Prtry *Max35Text                                 `xml:"Prtry,omitempty" json:"Prtry"`
 }
 
 type ClearingSystemIdentification3Choice struct {
-	Cd    *ExternalCashClearingSystem1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalCashClearingSystem1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                       `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type ClearingSystemMemberIdentification2 struct {
-	ClrSysId *ClearingSystemIdentification2Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ClrSysId,omitempty" json:"ClrSysId,omitempty"`
-	MmbId    *Max35Text                           `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 MmbId" json:"MmbId"`
+	ClrSysId *ClearingSystemIdentification2Choice `xml:"ClrSysId,omitempty" json:"ClrSysId,omitempty"`
+	MmbId    *Max35Text                           `xml:"MmbId" json:"MmbId"`
 }
 
 type Contact4 struct {
-	NmPrfx    *NamePrefix2Code             `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 NmPrfx,omitempty" json:"NmPrfx,omitempty"`
-	Nm        *Max140Text                  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Nm,omitempty" json:"Nm,omitempty"`
-	PhneNb    *PhoneNumber                 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PhneNb,omitempty" json:"PhneNb,omitempty"`
-	MobNb     *PhoneNumber                 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 MobNb,omitempty" json:"MobNb,omitempty"`
-	FaxNb     *PhoneNumber                 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 FaxNb,omitempty" json:"FaxNb,omitempty"`
-	EmailAdr  *Max2048Text                 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 EmailAdr,omitempty" json:"EmailAdr,omitempty"`
-	EmailPurp *Max35Text                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 EmailPurp,omitempty" json:"EmailPurp,omitempty"`
-	JobTitl   *Max35Text                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 JobTitl,omitempty" json:"JobTitl,omitempty"`
-	Rspnsblty *Max35Text                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Rspnsblty,omitempty" json:"Rspnsblty,omitempty"`
-	Dept      *Max70Text                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Dept,omitempty" json:"Dept,omitempty"`
-	Othr      []*OtherContact1             `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Othr,omitempty" json:"Othr,omitempty"`
-	PrefrdMtd *PreferredContactMethod1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PrefrdMtd,omitempty" json:"PrefrdMtd,omitempty"`
+	NmPrfx    *NamePrefix2Code             `xml:"NmPrfx,omitempty" json:"NmPrfx,omitempty"`
+	Nm        *Max140Text                  `xml:"Nm,omitempty" json:"Nm,omitempty"`
+	PhneNb    *PhoneNumber                 `xml:"PhneNb,omitempty" json:"PhneNb,omitempty"`
+	MobNb     *PhoneNumber                 `xml:"MobNb,omitempty" json:"MobNb,omitempty"`
+	FaxNb     *PhoneNumber                 `xml:"FaxNb,omitempty" json:"FaxNb,omitempty"`
+	EmailAdr  *Max2048Text                 `xml:"EmailAdr,omitempty" json:"EmailAdr,omitempty"`
+	EmailPurp *Max35Text                   `xml:"EmailPurp,omitempty" json:"EmailPurp,omitempty"`
+	JobTitl   *Max35Text                   `xml:"JobTitl,omitempty" json:"JobTitl,omitempty"`
+	Rspnsblty *Max35Text                   `xml:"Rspnsblty,omitempty" json:"Rspnsblty,omitempty"`
+	Dept      *Max70Text                   `xml:"Dept,omitempty" json:"Dept,omitempty"`
+	Othr      []*OtherContact1             `xml:"Othr,omitempty" json:"Othr,omitempty"`
+	PrefrdMtd *PreferredContactMethod1Code `xml:"PrefrdMtd,omitempty" json:"PrefrdMtd,omitempty"`
+}
+
+// CopyDuplicate1Code May be one of CODU, COPY, DUPL
+type CopyDuplicate1Code string
+
+func (v CopyDuplicate1Code) Validate() error {
+	return checkEnumeration(string(v), "CODU", "COPY", "DUPL")
 }
 
 // CountryCode Must match the pattern [A-Z]{2,2}
 type CountryCode string
 
+func (v CountryCode) Validate() error {
+	if err := checkPattern(string(v), `[A-Z]{2,2}`); err != nil {
+		return err
+	}
+	return checkCountry(string(v))
+}
+
 // CreditDebitCode May be one of CRDT, DBIT
 type CreditDebitCode string
 
+func (v CreditDebitCode) Validate() error {
+	return checkEnumeration(string(v), "CRDT", "DBIT")
+}
+
 type CreditTransferMandateData1 struct {
-	MndtId       *Max35Text                 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 MndtId,omitempty" json:"MndtId,omitempty"`
-	Tp           *MandateTypeInformation2   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp,omitempty" json:"Tp,omitempty"`
-	DtOfSgntr    *ISODate                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DtOfSgntr,omitempty" json:"DtOfSgntr,omitempty"`
-	DtOfVrfctn   *ISODateTime               `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DtOfVrfctn,omitempty" json:"DtOfVrfctn,omitempty"`
-	ElctrncSgntr *Max10KBinary              `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ElctrncSgntr,omitempty" json:"ElctrncSgntr,omitempty"`
-	FrstPmtDt    *ISODate                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 FrstPmtDt,omitempty" json:"FrstPmtDt,omitempty"`
-	FnlPmtDt     *ISODate                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 FnlPmtDt,omitempty" json:"FnlPmtDt,omitempty"`
-	Frqcy        *Frequency36Choice         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Frqcy,omitempty" json:"Frqcy,omitempty"`
-	Rsn          *MandateSetupReason1Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Rsn,omitempty" json:"Rsn,omitempty"`
+	MndtId       *Max35Text                 `xml:"MndtId,omitempty" json:"MndtId,omitempty"`
+	Tp           *MandateTypeInformation2   `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	DtOfSgntr    *ISODate                   `xml:"DtOfSgntr,omitempty" json:"DtOfSgntr,omitempty"`
+	DtOfVrfctn   *ISODateTime               `xml:"DtOfVrfctn,omitempty" json:"DtOfVrfctn,omitempty"`
+	ElctrncSgntr *Max10KBinary              `xml:"ElctrncSgntr,omitempty" json:"ElctrncSgntr,omitempty"`
+	FrstPmtDt    *ISODate                   `xml:"FrstPmtDt,omitempty" json:"FrstPmtDt,omitempty"`
+	FnlPmtDt     *ISODate                   `xml:"FnlPmtDt,omitempty" json:"FnlPmtDt,omitempty"`
+	Frqcy        *Frequency36Choice         `xml:"Frqcy,omitempty" json:"Frqcy,omitempty"`
+	Rsn          *MandateSetupReason1Choice `xml:"Rsn,omitempty" json:"Rsn,omitempty"`
 }
 
 type CreditTransferTransaction43 struct {
-	PmtId             *PaymentIdentification13                      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PmtId" json:"PmtId"`
-	PmtTpInf          *PaymentTypeInformation28                     `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PmtTpInf,omitempty" json:"PmtTpInf,omitempty"`
-	IntrBkSttlmAmt    *ActiveCurrencyAndAmount                      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 IntrBkSttlmAmt" json:"IntrBkSttlmAmt"`
-	IntrBkSttlmDt     *ISODate                                      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 IntrBkSttlmDt,omitempty" json:"IntrBkSttlmDt,omitempty"`
-	SttlmPrty         *Priority3Code                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SttlmPrty,omitempty" json:"SttlmPrty,omitempty"`
-	SttlmTmIndctn     *SettlementDateTimeIndication1                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SttlmTmIndctn,omitempty" json:"SttlmTmIndctn,omitempty"`
-	SttlmTmReq        *SettlementTimeRequest2                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SttlmTmReq,omitempty" json:"SttlmTmReq,omitempty"`
-	AccptncDtTm       *ISODateTime                                  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 AccptncDtTm,omitempty" json:"AccptncDtTm,omitempty"`
-	PoolgAdjstmntDt   *ISODate                                      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PoolgAdjstmntDt,omitempty" json:"PoolgAdjstmntDt,omitempty"`
-	InstdAmt          *ActiveOrHistoricCurrencyAndAmount            `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstdAmt,omitempty" json:"InstdAmt,omitempty"`
-	XchgRate          float64                                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 XchgRate,omitempty" json:"XchgRate,omitempty"`
-	ChrgBr            *ChargeBearerType1Code                        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ChrgBr" json:"ChrgBr"`
-	ChrgsInf          []*Charges7                                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ChrgsInf,omitempty" json:"ChrgsInf,omitempty"`
-	MndtRltdInf       *CreditTransferMandateData1                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 MndtRltdInf,omitempty" json:"MndtRltdInf,omitempty"`
-	PrvsInstgAgt1     *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PrvsInstgAgt1,omitempty" json:"PrvsInstgAgt1,omitempty"`
-	PrvsInstgAgt1Acct *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PrvsInstgAgt1Acct,omitempty" json:"PrvsInstgAgt1Acct,omitempty"`
-	PrvsInstgAgt2     *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PrvsInstgAgt2,omitempty" json:"PrvsInstgAgt2,omitempty"`
-	PrvsInstgAgt2Acct *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PrvsInstgAgt2Acct,omitempty" json:"PrvsInstgAgt2Acct,omitempty"`
-	PrvsInstgAgt3     *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PrvsInstgAgt3,omitempty" json:"PrvsInstgAgt3,omitempty"`
-	PrvsInstgAgt3Acct *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PrvsInstgAgt3Acct,omitempty" json:"PrvsInstgAgt3Acct,omitempty"`
-	InstgAgt          *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstgAgt,omitempty" json:"InstgAgt,omitempty"`
-	InstdAgt          *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstdAgt,omitempty" json:"InstdAgt,omitempty"`
-	IntrmyAgt1        *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 IntrmyAgt1,omitempty" json:"IntrmyAgt1,omitempty"`
-	IntrmyAgt1Acct    *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 IntrmyAgt1Acct,omitempty" json:"IntrmyAgt1Acct,omitempty"`
-	IntrmyAgt2        *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 IntrmyAgt2,omitempty" json:"IntrmyAgt2,omitempty"`
-	IntrmyAgt2Acct    *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 IntrmyAgt2Acct,omitempty" json:"IntrmyAgt2Acct,omitempty"`
-	IntrmyAgt3        *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 IntrmyAgt3,omitempty" json:"IntrmyAgt3,omitempty"`
-	IntrmyAgt3Acct    *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 IntrmyAgt3Acct,omitempty" json:"IntrmyAgt3Acct,omitempty"`
-	UltmtDbtr         *PartyIdentification135                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 UltmtDbtr,omitempty" json:"UltmtDbtr,omitempty"`
-	InitgPty          *PartyIdentification135                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InitgPty,omitempty" json:"InitgPty,omitempty"`
-	Dbtr              *PartyIdentification135                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Dbtr" json:"Dbtr"`
-	DbtrAcct          *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DbtrAcct,omitempty" json:"DbtrAcct,omitempty"`
-	DbtrAgt           *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DbtrAgt" json:"DbtrAgt"`
-	DbtrAgtAcct       *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DbtrAgtAcct,omitempty" json:"DbtrAgtAcct,omitempty"`
-	CdtrAgt           *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdtrAgt" json:"CdtrAgt"`
-	CdtrAgtAcct       *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdtrAgtAcct,omitempty" json:"CdtrAgtAcct,omitempty"`
-	Cdtr              *PartyIdentification135                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cdtr" json:"Cdtr"`
-	CdtrAcct          *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdtrAcct,omitempty" json:"CdtrAcct,omitempty"`
-	UltmtCdtr         *PartyIdentification135                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 UltmtCdtr,omitempty" json:"UltmtCdtr,omitempty"`
-	InstrForCdtrAgt   []*InstructionForCreditorAgent3               `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstrForCdtrAgt,omitempty" json:"InstrForCdtrAgt,omitempty"`
-	InstrForNxtAgt    []*InstructionForNextAgent1                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstrForNxtAgt,omitempty" json:"InstrForNxtAgt,omitempty"`
-	Purp              *Purpose2Choice                               `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Purp,omitempty" json:"Purp,omitempty"`
-	RgltryRptg        []*RegulatoryReporting3                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RgltryRptg,omitempty" json:"RgltryRptg,omitempty"`
-	Tax               *TaxInformation8                              `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tax,omitempty" json:"Tax,omitempty"`
-	RltdRmtInf        []*RemittanceLocation7                        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RltdRmtInf,omitempty" json:"RltdRmtInf,omitempty"`
-	RmtInf            *RemittanceInformation16                      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RmtInf,omitempty" json:"RmtInf,omitempty"`
-	SplmtryData       []*SupplementaryData1                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SplmtryData,omitempty" json:"SplmtryData,omitempty"`
+	PmtId             *PaymentIdentification13                      `xml:"PmtId" json:"PmtId"`
+	PmtTpInf          *PaymentTypeInformation28                     `xml:"PmtTpInf,omitempty" json:"PmtTpInf,omitempty"`
+	IntrBkSttlmAmt    *ActiveCurrencyAndAmount                      `xml:"IntrBkSttlmAmt" json:"IntrBkSttlmAmt"`
+	IntrBkSttlmDt     *ISODate                                      `xml:"IntrBkSttlmDt,omitempty" json:"IntrBkSttlmDt,omitempty"`
+	SttlmPrty         *Priority3Code                                `xml:"SttlmPrty,omitempty" json:"SttlmPrty,omitempty"`
+	SttlmTmIndctn     *SettlementDateTimeIndication1                `xml:"SttlmTmIndctn,omitempty" json:"SttlmTmIndctn,omitempty"`
+	SttlmTmReq        *SettlementTimeRequest2                       `xml:"SttlmTmReq,omitempty" json:"SttlmTmReq,omitempty"`
+	AccptncDtTm       *ISODateTime                                  `xml:"AccptncDtTm,omitempty" json:"AccptncDtTm,omitempty"`
+	PoolgAdjstmntDt   *ISODate                                      `xml:"PoolgAdjstmntDt,omitempty" json:"PoolgAdjstmntDt,omitempty"`
+	InstdAmt          *ActiveOrHistoricCurrencyAndAmount            `xml:"InstdAmt,omitempty" json:"InstdAmt,omitempty"`
+	XchgRate          *BaseOneRate                                  `xml:"XchgRate,omitempty" json:"XchgRate,omitempty"`
+	ChrgBr            *ChargeBearerType1Code                        `xml:"ChrgBr" json:"ChrgBr"`
+	ChrgsInf          []*Charges7                                   `xml:"ChrgsInf,omitempty" json:"ChrgsInf,omitempty"`
+	MndtRltdInf       *CreditTransferMandateData1                   `xml:"MndtRltdInf,omitempty" json:"MndtRltdInf,omitempty"`
+	PrvsInstgAgt1     *BranchAndFinancialInstitutionIdentification6 `xml:"PrvsInstgAgt1,omitempty" json:"PrvsInstgAgt1,omitempty"`
+	PrvsInstgAgt1Acct *CashAccount38                                `xml:"PrvsInstgAgt1Acct,omitempty" json:"PrvsInstgAgt1Acct,omitempty"`
+	PrvsInstgAgt2     *BranchAndFinancialInstitutionIdentification6 `xml:"PrvsInstgAgt2,omitempty" json:"PrvsInstgAgt2,omitempty"`
+	PrvsInstgAgt2Acct *CashAccount38                                `xml:"PrvsInstgAgt2Acct,omitempty" json:"PrvsInstgAgt2Acct,omitempty"`
+	PrvsInstgAgt3     *BranchAndFinancialInstitutionIdentification6 `xml:"PrvsInstgAgt3,omitempty" json:"PrvsInstgAgt3,omitempty"`
+	PrvsInstgAgt3Acct *CashAccount38                                `xml:"PrvsInstgAgt3Acct,omitempty" json:"PrvsInstgAgt3Acct,omitempty"`
+	InstgAgt          *BranchAndFinancialInstitutionIdentification6 `xml:"InstgAgt,omitempty" json:"InstgAgt,omitempty"`
+	InstdAgt          *BranchAndFinancialInstitutionIdentification6 `xml:"InstdAgt,omitempty" json:"InstdAgt,omitempty"`
+	IntrmyAgt1        *BranchAndFinancialInstitutionIdentification6 `xml:"IntrmyAgt1,omitempty" json:"IntrmyAgt1,omitempty"`
+	IntrmyAgt1Acct    *CashAccount38                                `xml:"IntrmyAgt1Acct,omitempty" json:"IntrmyAgt1Acct,omitempty"`
+	IntrmyAgt2        *BranchAndFinancialInstitutionIdentification6 `xml:"IntrmyAgt2,omitempty" json:"IntrmyAgt2,omitempty"`
+	IntrmyAgt2Acct    *CashAccount38                                `xml:"IntrmyAgt2Acct,omitempty" json:"IntrmyAgt2Acct,omitempty"`
+	IntrmyAgt3        *BranchAndFinancialInstitutionIdentification6 `xml:"IntrmyAgt3,omitempty" json:"IntrmyAgt3,omitempty"`
+	IntrmyAgt3Acct    *CashAccount38                                `xml:"IntrmyAgt3Acct,omitempty" json:"IntrmyAgt3Acct,omitempty"`
+	UltmtDbtr         *PartyIdentification135                       `xml:"UltmtDbtr,omitempty" json:"UltmtDbtr,omitempty"`
+	InitgPty          *PartyIdentification135                       `xml:"InitgPty,omitempty" json:"InitgPty,omitempty"`
+	Dbtr              *PartyIdentification135                       `xml:"Dbtr" json:"Dbtr"`
+	DbtrAcct          *CashAccount38                                `xml:"DbtrAcct,omitempty" json:"DbtrAcct,omitempty"`
+	DbtrAgt           *BranchAndFinancialInstitutionIdentification6 `xml:"DbtrAgt" json:"DbtrAgt"`
+	DbtrAgtAcct       *CashAccount38                                `xml:"DbtrAgtAcct,omitempty" json:"DbtrAgtAcct,omitempty"`
+	CdtrAgt           *BranchAndFinancialInstitutionIdentification6 `xml:"CdtrAgt" json:"CdtrAgt"`
+	CdtrAgtAcct       *CashAccount38                                `xml:"CdtrAgtAcct,omitempty" json:"CdtrAgtAcct,omitempty"`
+	Cdtr              *PartyIdentification135                       `xml:"Cdtr" json:"Cdtr"`
+	CdtrAcct          *CashAccount38                                `xml:"CdtrAcct,omitempty" json:"CdtrAcct,omitempty"`
+	UltmtCdtr         *PartyIdentification135                       `xml:"UltmtCdtr,omitempty" json:"UltmtCdtr,omitempty"`
+	InstrForCdtrAgt   []*InstructionForCreditorAgent3               `xml:"InstrForCdtrAgt,omitempty" json:"InstrForCdtrAgt,omitempty"`
+	InstrForNxtAgt    []*InstructionForNextAgent1                   `xml:"InstrForNxtAgt,omitempty" json:"InstrForNxtAgt,omitempty"`
+	Purp              *Purpose2Choice                               `xml:"Purp,omitempty" json:"Purp,omitempty"`
+	RgltryRptg        []*RegulatoryReporting3                       `xml:"RgltryRptg,omitempty" json:"RgltryRptg,omitempty"`
+	Tax               *TaxInformation8                              `xml:"Tax,omitempty" json:"Tax,omitempty"`
+	RltdRmtInf        []*RemittanceLocation7                        `xml:"RltdRmtInf,omitempty" json:"RltdRmtInf,omitempty"`
+	RmtInf            *RemittanceInformation16                      `xml:"RmtInf,omitempty" json:"RmtInf,omitempty"`
+	SplmtryData       []*SupplementaryData1                         `xml:"SplmtryData,omitempty" json:"SplmtryData,omitempty"`
 }
 
 type CreditorReferenceInformation2 struct {
-	Tp  *CreditorReferenceType2 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp,omitempty" json:"Tp,omitempty"`
-	Ref *Max35Text              `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Ref,omitempty" json:"Ref,omitempty"`
+	Tp  *CreditorReferenceType2 `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	Ref *Max35Text              `xml:"Ref,omitempty" json:"Ref,omitempty"`
 }
 
 type CreditorReferenceType1Choice struct {
-	Cd    *DocumentType3Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *DocumentType3Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text         `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type CreditorReferenceType2 struct {
-	CdOrPrtry *CreditorReferenceType1Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdOrPrtry" json:"CdOrPrtry,omitempty"`
-	Issr      *Max35Text                    `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Issr,omitempty" json:"Issr"`
+	CdOrPrtry *CreditorReferenceType1Choice `xml:"CdOrPrtry" json:"CdOrPrtry,omitempty"`
+	Issr      *Max35Text                    `xml:"Issr,omitempty" json:"Issr"`
 }
 
 type DateAndPlaceOfBirth1 struct {
-	BirthDt     *ISODate     `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 BirthDt" json:"BirthDt"`
-	PrvcOfBirth *Max35Text   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PrvcOfBirth,omitempty" json:"PrvcOfBirth,omitempty"`
-	CityOfBirth *Max35Text   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CityOfBirth" json:"CityOfBirth"`
-	CtryOfBirth *CountryCode `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CtryOfBirth" json:"CtryOfBirth"`
+	BirthDt     *ISODate     `xml:"BirthDt" json:"BirthDt"`
+	PrvcOfBirth *Max35Text   `xml:"PrvcOfBirth,omitempty" json:"PrvcOfBirth,omitempty"`
+	CityOfBirth *Max35Text   `xml:"CityOfBirth" json:"CityOfBirth"`
+	CtryOfBirth *CountryCode `xml:"CtryOfBirth" json:"CtryOfBirth"`
 }
 
 type DatePeriod2 struct {
-	FrDt *ISODate `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 FrDt" json:"FrDt"`
-	ToDt *ISODate `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ToDt" json:"ToDt"`
+	FrDt *ISODate `xml:"FrDt" json:"FrDt"`
+	ToDt *ISODate `xml:"ToDt" json:"ToDt"`
+}
+
+// DecimalNumber May be no more than 18 digits long, with no more than 17 fraction digits
+type DecimalNumber Decimal
+
+func (d *DecimalNumber) UnmarshalText(text []byte) error {
+	return (*Decimal)(d).UnmarshalText(text)
+}
+func (d DecimalNumber) MarshalText() ([]byte, error) {
+	return Decimal(d).MarshalText()
+}
+func (d *DecimalNumber) UnmarshalJSON(data []byte) error {
+	return (*Decimal)(d).UnmarshalJSON(data)
+}
+func (d DecimalNumber) MarshalJSON() ([]byte, error) {
+	return Decimal(d).MarshalJSON()
+}
+func (d DecimalNumber) Validate() error {
+	return checkDigits(Decimal(d), 18, 17)
 }
 
 type DiscountAmountAndType1 struct {
-	Tp  *DiscountAmountType1Choice         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp,omitempty" json:"Tp,omitempty"`
-	Amt *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Amt" json:"Amt"`
+	Tp  *DiscountAmountType1Choice         `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	Amt *ActiveOrHistoricCurrencyAndAmount `xml:"Amt" json:"Amt"`
 }
 
 type DiscountAmountType1Choice struct {
-	Cd    *ExternalDiscountAmountType1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalDiscountAmountType1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                       `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
+// Document holds one message root, its namespace follows the root
+// It keeps the namespace it was decoded with, so validation can check it against the root
 type Document struct {
-	FIToFICstmrCdtTrf *FIToFICustomerCreditTransferV09 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 FIToFICstmrCdtTrf" json:"FIToFICstmrCdtTrf"`
+	XMLName               xml.Name                                  `xml:"Document" json:"-"`
+	FIToFICstmrCdtTrf     *FIToFICustomerCreditTransferV09          `xml:"FIToFICstmrCdtTrf,omitempty" json:"FIToFICstmrCdtTrf,omitempty"`
+	FIToFIPmtStsRpt       *FIToFIPaymentStatusReportV10             `xml:"FIToFIPmtStsRpt,omitempty" json:"FIToFIPmtStsRpt,omitempty"`
+	PmtRtr                *PaymentReturnV09                         `xml:"PmtRtr,omitempty" json:"PmtRtr,omitempty"`
+	FICdtTrf              *FinancialInstitutionCreditTransferV09    `xml:"FICdtTrf,omitempty" json:"FICdtTrf,omitempty"`
+	FIToFIPmtCxlReq       *FIToFIPaymentCancellationRequestV08      `xml:"FIToFIPmtCxlReq,omitempty" json:"FIToFIPmtCxlReq,omitempty"`
+	RsltnOfInvstgtn       *ResolutionOfInvestigationV09             `xml:"RsltnOfInvstgtn,omitempty" json:"RsltnOfInvstgtn,omitempty"`
+	FIToFIPmtStsReq       *FIToFIPaymentStatusRequestV03            `xml:"FIToFIPmtStsReq,omitempty" json:"FIToFIPmtStsReq,omitempty"`
+	BkToCstmrStmt         *BankToCustomerStatementV08               `xml:"BkToCstmrStmt,omitempty" json:"BkToCstmrStmt,omitempty"`
+	BkToCstmrDbtCdtNtfctn *BankToCustomerDebitCreditNotificationV08 `xml:"BkToCstmrDbtCdtNtfctn,omitempty" json:"BkToCstmrDbtCdtNtfctn,omitempty"`
+}
+
+// Return element name and namespace of the message root Document holds, empty when it has none
+func (d Document) root() (string, string) {
+	switch {
+	case d.FIToFICstmrCdtTrf != nil:
+		return "FIToFICstmrCdtTrf", pacs008Namespace
+	case d.FIToFIPmtStsRpt != nil:
+		return "FIToFIPmtStsRpt", pacs002Namespace
+	case d.PmtRtr != nil:
+		return "PmtRtr", pacs004Namespace
+	case d.FICdtTrf != nil:
+		return "FICdtTrf", pacs009Namespace
+	case d.FIToFIPmtCxlReq != nil:
+		return "FIToFIPmtCxlReq", camt056Namespace
+	case d.RsltnOfInvstgtn != nil:
+		return "RsltnOfInvstgtn", camt029Namespace
+	case d.FIToFIPmtStsReq != nil:
+		return "FIToFIPmtStsReq", pacs028Namespace
+	case d.BkToCstmrStmt != nil:
+		return "BkToCstmrStmt", camt053Namespace
+	case d.BkToCstmrDbtCdtNtfctn != nil:
+		return "BkToCstmrDbtCdtNtfctn", camt054Namespace
+	}
+	return "", ""
+}
+
+// Return namespace of the message root Document holds
+func (d Document) rootNamespace() string {
+	_, namespace := d.root()
+	return namespace
+}
+
+// Return message definition identifier of namespace, e.g. pacs.008.001.09
+func namespaceMessage(namespace string) string {
+	return strings.TrimPrefix(namespace, "urn:iso:std:iso:20022:tech:xsd:")
+}
+
+// Return message name of message definition identifier, e.g. pacs.008 of pacs.008.001.09
+func messageName(msgDefIdr string) string {
+	if len(msgDefIdr) < 8 {
+		return msgDefIdr
+	}
+	return msgDefIdr[:8]
+}
+
+// Check message definition identifier is a version of message, e.g. pacs.008.001.09 of pacs.008
+func isMessage(msgDefIdr string, message string) bool {
+	return strings.HasPrefix(msgDefIdr, message+".")
+}
+
+// MarshalXML writes Document in the namespace of its message root
+func (d Document) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Space: d.rootNamespace(), Local: "Document"}
+	type document Document // without MarshalXML
+	return e.EncodeElement(document(d), start)
 }
 
 type DocumentAdjustment1 struct {
-	Amt       *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Amt" json:"Amt"`
-	CdtDbtInd *CreditDebitCode                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdtDbtInd,omitempty" json:"CdtDbtInd,omitempty"`
-	Rsn       *Max4Text                          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Rsn,omitempty" json:"Rsn,omitempty"`
-	AddtlInf  *Max140Text                        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 AddtlInf,omitempty" json:"AddtlInf,omitempty"`
+	Amt       *ActiveOrHistoricCurrencyAndAmount `xml:"Amt" json:"Amt"`
+	CdtDbtInd *CreditDebitCode                   `xml:"CdtDbtInd,omitempty" json:"CdtDbtInd,omitempty"`
+	Rsn       *Max4Text                          `xml:"Rsn,omitempty" json:"Rsn,omitempty"`
+	AddtlInf  *Max140Text                        `xml:"AddtlInf,omitempty" json:"AddtlInf,omitempty"`
 }
 
 type DocumentLineIdentification1 struct {
-	Tp     *DocumentLineType1 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp,omitempty" json:"Tp,omitempty"`
-	Nb     *Max35Text         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Nb,omitempty" json:"Nb,omitempty"`
-	RltdDt *ISODate           `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RltdDt,omitempty" json:"RltdDt,omitempty"`
+	Tp     *DocumentLineType1 `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	Nb     *Max35Text         `xml:"Nb,omitempty" json:"Nb,omitempty"`
+	RltdDt *ISODate           `xml:"RltdDt,omitempty" json:"RltdDt,omitempty"`
 }
 
 type DocumentLineInformation1 struct {
-	Id   []*DocumentLineIdentification1 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Id" json:"Id"`
-	Desc *Max2048Text                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Desc,omitempty" json:"Desc,omitempty"`
-	Amt  *RemittanceAmount3             `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Amt,omitempty" json:"Amt,omitempty"`
+	Id   []*DocumentLineIdentification1 `xml:"Id" json:"Id"`
+	Desc *Max2048Text                   `xml:"Desc,omitempty" json:"Desc,omitempty"`
+	Amt  *RemittanceAmount3             `xml:"Amt,omitempty" json:"Amt,omitempty"`
 }
 
 type DocumentLineType1 struct {
-	CdOrPrtry *DocumentLineType1Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdOrPrtry" json:"CdOrPrtry"`
-	Issr      *Max35Text               `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Issr,omitempty" json:"Issr,omitempty"`
+	CdOrPrtry *DocumentLineType1Choice `xml:"CdOrPrtry" json:"CdOrPrtry"`
+	Issr      *Max35Text               `xml:"Issr,omitempty" json:"Issr,omitempty"`
 }
 
 type DocumentLineType1Choice struct {
-	Cd    *ExternalDocumentLineType1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                     `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalDocumentLineType1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                     `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 // DocumentType3Code May be one of RADM, RPIN, FXDR, DISP, PUOR, SCOR
 type DocumentType3Code string
 
+func (v DocumentType3Code) Validate() error {
+	return checkEnumeration(string(v), "RADM", "RPIN", "FXDR", "DISP", "PUOR", "SCOR")
+}
+
 // DocumentType6Code May be one of MSIN, CNFA, DNFA, CINV, CREN, DEBN, HIRI, SBIN, CMCN, SOAC, DISP, BOLD, VCHR, AROI, TSUT, PUOR
 type DocumentType6Code string
 
+func (v DocumentType6Code) Validate() error {
+	return checkEnumeration(string(v), "MSIN", "CNFA", "DNFA", "CINV", "CREN", "DEBN", "HIRI", "SBIN", "CMCN", "SOAC", "DISP", "BOLD", "VCHR", "AROI", "TSUT", "PUOR")
+}
+
 // Exact2NumericText Must match the pattern [0-9]{2}
 type Exact2NumericText string
 
+func (v Exact2NumericText) Validate() error {
+	return checkPattern(string(v), `[0-9]{2}`)
+}
+
 // Exact4AlphaNumericText Must match the pattern [a-zA-Z0-9]{4}
 type Exact4AlphaNumericText string
 
+func (v Exact4AlphaNumericText) Validate() error {
+	return checkPattern(string(v), `[a-zA-Z0-9]{4}`)
+}
+
 // ExternalAccountIdentification1Code May be no more than 4 items long
 type ExternalAccountIdentification1Code string
 
+func (v ExternalAccountIdentification1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalCashAccountType1Code May be no more than 4 items long
 type ExternalCashAccountType1Code string
 
+func (v ExternalCashAccountType1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalCashClearingSystem1Code May be no more than 3 items long
 type ExternalCashClearingSystem1Code string
 
+func (v ExternalCashClearingSystem1Code) Validate() error {
+	return checkLength(string(v), 1, 3)
+}
+
 // ExternalCategoryPurpose1Code May be no more than 4 items long
 type ExternalCategoryPurpose1Code string
 
+func (v ExternalCategoryPurpose1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalClearingSystemIdentification1Code May be no more than 5 items long
 type ExternalClearingSystemIdentification1Code string
 
+func (v ExternalClearingSystemIdentification1Code) Validate() error {
+	return checkLength(string(v), 1, 5)
+}
+
 // ExternalCreditorAgentInstruction1Code May be no more than 4 items long
 type ExternalCreditorAgentInstruction1Code string
 
+func (v ExternalCreditorAgentInstruction1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalDiscountAmountType1Code May be no more than 4 items long
 type ExternalDiscountAmountType1Code string
 
+func (v ExternalDiscountAmountType1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalDocumentLineType1Code May be no more than 4 items long
 type ExternalDocumentLineType1Code string
 
+func (v ExternalDocumentLineType1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalFinancialInstitutionIdentification1Code May be no more than 4 items long
 type ExternalFinancialInstitutionIdentification1Code string
 
+func (v ExternalFinancialInstitutionIdentification1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalGarnishmentType1Code May be no more than 4 items long
 type ExternalGarnishmentType1Code string
 
+func (v ExternalGarnishmentType1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalLocalInstrument1Code May be no more than 35 items long
 type ExternalLocalInstrument1Code string
 
+func (v ExternalLocalInstrument1Code) Validate() error {
+	return checkLength(string(v), 1, 35)
+}
+
 // ExternalMandateSetupReason1Code May be no more than 4 items long
 type ExternalMandateSetupReason1Code string
 
+func (v ExternalMandateSetupReason1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalOrganisationIdentification1Code May be no more than 4 items long
 type ExternalOrganisationIdentification1Code string
 
+func (v ExternalOrganisationIdentification1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalPersonIdentification1Code May be no more than 4 items long
 type ExternalPersonIdentification1Code string
 
+func (v ExternalPersonIdentification1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalProxyAccountType1Code May be no more than 4 items long
 type ExternalProxyAccountType1Code string
 
+func (v ExternalProxyAccountType1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalPurpose1Code May be no more than 4 items long
 type ExternalPurpose1Code string
 
+func (v ExternalPurpose1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalServiceLevel1Code May be no more than 4 items long
 type ExternalServiceLevel1Code string
 
+func (v ExternalServiceLevel1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // ExternalTaxAmountType1Code May be no more than 4 items long
 type ExternalTaxAmountType1Code string
 
+func (v ExternalTaxAmountType1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 type FIToFICustomerCreditTransferV09 struct {
-	GrpHdr      *GroupHeader93                 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 GrpHdr" json:"GrpHdr"`
-	CdtTrfTxInf []*CreditTransferTransaction43 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdtTrfTxInf" json:"CdtTrfTxInf"`
-	SplmtryData []*SupplementaryData1          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SplmtryData,omitempty" json:"SplmtryData,omitempty"`
+	GrpHdr      *GroupHeader93                 `xml:"GrpHdr" json:"GrpHdr"`
+	CdtTrfTxInf []*CreditTransferTransaction43 `xml:"CdtTrfTxInf" json:"CdtTrfTxInf"`
+	SplmtryData []*SupplementaryData1          `xml:"SplmtryData,omitempty" json:"SplmtryData,omitempty"`
 }
 
 type FinancialIdentificationSchemeName1Choice struct {
-	Cd    *ExternalFinancialInstitutionIdentification1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalFinancialInstitutionIdentification1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                                       `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type FinancialInstitutionIdentification18 struct {
-	BICFI       *BICFIDec2014Identifier              `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 BICFI,omitempty" json:"BICFI,omitempty"`
-	ClrSysMmbId *ClearingSystemMemberIdentification2 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ClrSysMmbId,omitempty" json:"ClrSysMmbId,omitempty"`
-	LEI         *LEIIdentifier                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 LEI,omitempty" json:"LEI,omitempty"`
-	Nm          *Max140Text                          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Nm,omitempty" json:"Nm,omitempty"`
-	PstlAdr     *PostalAddress24                     `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PstlAdr,omitempty" json:"PstlAdr,omitempty"`
-	Othr        *GenericFinancialIdentification1     `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Othr,omitempty" json:"Othr,omitempty"`
+	BICFI       *BICFIDec2014Identifier              `xml:"BICFI,omitempty" json:"BICFI,omitempty"`
+	ClrSysMmbId *ClearingSystemMemberIdentification2 `xml:"ClrSysMmbId,omitempty" json:"ClrSysMmbId,omitempty"`
+	LEI         *LEIIdentifier                       `xml:"LEI,omitempty" json:"LEI,omitempty"`
+	Nm          *Max140Text                          `xml:"Nm,omitempty" json:"Nm,omitempty"`
+	PstlAdr     *PostalAddress24                     `xml:"PstlAdr,omitempty" json:"PstlAdr,omitempty"`
+	Othr        *GenericFinancialIdentification1     `xml:"Othr,omitempty" json:"Othr,omitempty"`
 }
 
 type Frequency36Choice struct {
-	Tp     *Frequency6Code      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp,omitempty" json:"Tp,omitempty"`
-	Prd    *FrequencyPeriod1    `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prd,omitempty" json:"Prd,omitempty"`
-	PtInTm *FrequencyAndMoment1 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PtInTm,omitempty" json:"PtInTm,omitempty"`
+	Tp     *Frequency6Code      `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	Prd    *FrequencyPeriod1    `xml:"Prd,omitempty" json:"Prd,omitempty"`
+	PtInTm *FrequencyAndMoment1 `xml:"PtInTm,omitempty" json:"PtInTm,omitempty"`
 }
 
 // Frequency6Code May be one of YEAR, MNTH, QURT, MIAN, WEEK, DAIL, ADHO, INDA, FRTN
 type Frequency6Code string
 
+func (v Frequency6Code) Validate() error {
+	return checkEnumeration(string(v), "YEAR", "MNTH", "QURT", "MIAN", "WEEK", "DAIL", "ADHO", "INDA", "FRTN")
+}
+
 type FrequencyAndMoment1 struct {
-	Tp     *Frequency6Code    `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp" json:"Tp"`
-	PtInTm *Exact2NumericText `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PtInTm" json:"PtInTm"`
+	Tp     *Frequency6Code    `xml:"Tp" json:"Tp"`
+	PtInTm *Exact2NumericText `xml:"PtInTm" json:"PtInTm"`
 }
 
 type FrequencyPeriod1 struct {
-	Tp        *Frequency6Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp" json:"Tp"`
-	CntPerPrd float64         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CntPerPrd" json:"CntPerPrd"`
+	Tp        *Frequency6Code `xml:"Tp" json:"Tp"`
+	CntPerPrd *DecimalNumber  `xml:"CntPerPrd" json:"CntPerPrd"`
 }
 
 type Garnishment3 struct {
-	Tp                *GarnishmentType1                  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp" json:"Tp"`
-	Grnshee           *PartyIdentification135            `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Grnshee,omitempty" json:"Grnshee,omitempty"`
-	GrnshmtAdmstr     *PartyIdentification135            `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 GrnshmtAdmstr,omitempty" json:"GrnshmtAdmstr,omitempty"`
-	RefNb             *Max140Text                        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RefNb,omitempty" json:"RefNb,omitempty"`
-	Dt                *ISODate                           `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Dt,omitempty" json:"Dt,omitempty"`
-	RmtdAmt           *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RmtdAmt,omitempty" json:"RmtdAmt,omitempty"`
-	FmlyMdclInsrncInd bool                               `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 FmlyMdclInsrncInd,omitempty" json:"FmlyMdclInsrncInd,omitempty"`
-	MplyeeTermntnInd  bool                               `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 MplyeeTermntnInd,omitempty" json:"MplyeeTermntnInd,omitempty"`
+	Tp                *GarnishmentType1                  `xml:"Tp" json:"Tp"`
+	Grnshee           *PartyIdentification135            `xml:"Grnshee,omitempty" json:"Grnshee,omitempty"`
+	GrnshmtAdmstr     *PartyIdentification135            `xml:"GrnshmtAdmstr,omitempty" json:"GrnshmtAdmstr,omitempty"`
+	RefNb             *Max140Text                        `xml:"RefNb,omitempty" json:"RefNb,omitempty"`
+	Dt                *ISODate                           `xml:"Dt,omitempty" json:"Dt,omitempty"`
+	RmtdAmt           *ActiveOrHistoricCurrencyAndAmount `xml:"RmtdAmt,omitempty" json:"RmtdAmt,omitempty"`
+	FmlyMdclInsrncInd bool                               `xml:"FmlyMdclInsrncInd,omitempty" json:"FmlyMdclInsrncInd,omitempty"`
+	MplyeeTermntnInd  bool                               `xml:"MplyeeTermntnInd,omitempty" json:"MplyeeTermntnInd,omitempty"`
 }
 
 type GarnishmentType1 struct {
-	CdOrPrtry *GarnishmentType1Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdOrPrtry" json:"CdOrPrtry"`
-	Issr      *Max35Text              `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Issr,omitempty" json:"Issr,omitempty"`
+	CdOrPrtry *GarnishmentType1Choice `xml:"CdOrPrtry" json:"CdOrPrtry"`
+	Issr      *Max35Text              `xml:"Issr,omitempty" json:"Issr,omitempty"`
 }
 
 type GarnishmentType1Choice struct {
-	Cd    *ExternalGarnishmentType1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                    `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalGarnishmentType1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                    `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type GenericAccountIdentification1 struct {
-	Id      *Max34Text                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Id" json:"Id"`
-	SchmeNm *AccountSchemeName1Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SchmeNm,omitempty" json:"SchmeNm,omitempty"`
-	Issr    *Max35Text                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Issr,omitempty" json:"Issr,omitempty"`
+	Id      *Max34Text                `xml:"Id" json:"Id"`
+	SchmeNm *AccountSchemeName1Choice `xml:"SchmeNm,omitempty" json:"SchmeNm,omitempty"`
+	Issr    *Max35Text                `xml:"Issr,omitempty" json:"Issr,omitempty"`
 }
 
 type GenericFinancialIdentification1 struct {
-	Id      *Max35Text                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Id" json:"Id"`
-	SchmeNm *FinancialIdentificationSchemeName1Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SchmeNm,omitempty" json:"SchmeNm,omitempty"`
-	Issr    *Max35Text                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Issr,omitempty" json:"Issr,omitempty"`
+	Id      *Max35Text                                `xml:"Id" json:"Id"`
+	SchmeNm *FinancialIdentificationSchemeName1Choice `xml:"SchmeNm,omitempty" json:"SchmeNm,omitempty"`
+	Issr    *Max35Text                                `xml:"Issr,omitempty" json:"Issr,omitempty"`
 }
 
 type GenericIdentification30 struct {
-	Id      *Exact4AlphaNumericText `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Id" json:"Id"`
-	Issr    *Max35Text              `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Issr" json:"Issr"`
-	SchmeNm *Max35Text              `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SchmeNm,omitempty" json:"SchmeNm,omitempty"`
+	Id      *Exact4AlphaNumericText `xml:"Id" json:"Id"`
+	Issr    *Max35Text              `xml:"Issr" json:"Issr"`
+	SchmeNm *Max35Text              `xml:"SchmeNm,omitempty" json:"SchmeNm,omitempty"`
 }
 
 type GenericOrganisationIdentification1 struct {
-	Id      *Max35Text                                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Id" json:"Id"`
-	SchmeNm *OrganisationIdentificationSchemeName1Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SchmeNm,omitempty" json:"SchmeNm,omitempty"`
-	Issr    *Max35Text                                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Issr,omitempty" json:"Issr,omitempty"`
+	Id      *Max35Text                                   `xml:"Id" json:"Id"`
+	SchmeNm *OrganisationIdentificationSchemeName1Choice `xml:"SchmeNm,omitempty" json:"SchmeNm,omitempty"`
+	Issr    *Max35Text                                   `xml:"Issr,omitempty" json:"Issr,omitempty"`
 }
 
 type GenericPersonIdentification1 struct {
-	Id      *Max35Text                             `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Id" json:"Id"`
-	SchmeNm *PersonIdentificationSchemeName1Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SchmeNm,omitempty" json:"SchmeNm,omitempty"`
-	Issr    *Max35Text                             `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Issr,omitempty" json:"Issr,omitempty"`
+	Id      *Max35Text                             `xml:"Id" json:"Id"`
+	SchmeNm *PersonIdentificationSchemeName1Choice `xml:"SchmeNm,omitempty" json:"SchmeNm,omitempty"`
+	Issr    *Max35Text                             `xml:"Issr,omitempty" json:"Issr,omitempty"`
 }
 
 type GroupHeader93 struct {
-	MsgId             *Max35Text                                    `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 MsgId" json:"MsgId"`
-	CreDtTm           *ISODateTime                                  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CreDtTm" json:"CreDtTm"`
-	BtchBookg         bool                                          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 BtchBookg,omitempty" json:"BtchBookg,omitempty"`
-	NbOfTxs           *Max15NumericText                             `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 NbOfTxs" json:"NbOfTxs"`
-	CtrlSum           float64                                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CtrlSum,omitempty" json:"CtrlSum,omitempty"`
-	TtlIntrBkSttlmAmt *ActiveCurrencyAndAmount                      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TtlIntrBkSttlmAmt,omitempty" json:"TtlIntrBkSttlmAmt,omitempty"`
-	IntrBkSttlmDt     *ISODate                                      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 IntrBkSttlmDt,omitempty" json:"IntrBkSttlmDt,omitempty"`
-	SttlmInf          *SettlementInstruction7                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SttlmInf" json:"SttlmInf"`
-	PmtTpInf          *PaymentTypeInformation28                     `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PmtTpInf,omitempty" json:"PmtTpInf,omitempty"`
-	InstgAgt          *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstgAgt,omitempty" json:"InstgAgt,omitempty"`
-	InstdAgt          *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstdAgt,omitempty" json:"InstdAgt,omitempty"`
+	MsgId             *Max35Text                                    `xml:"MsgId" json:"MsgId"`
+	CreDtTm           *ISODateTime                                  `xml:"CreDtTm" json:"CreDtTm"`
+	BtchBookg         bool                                          `xml:"BtchBookg,omitempty" json:"BtchBookg,omitempty"`
+	NbOfTxs           *Max15NumericText                             `xml:"NbOfTxs" json:"NbOfTxs"`
+	CtrlSum           *DecimalNumber                                `xml:"CtrlSum,omitempty" json:"CtrlSum,omitempty"`
+	TtlIntrBkSttlmAmt *ActiveCurrencyAndAmount                      `xml:"TtlIntrBkSttlmAmt,omitempty" json:"TtlIntrBkSttlmAmt,omitempty"`
+	IntrBkSttlmDt     *ISODate                                      `xml:"IntrBkSttlmDt,omitempty" json:"IntrBkSttlmDt,omitempty"`
+	SttlmInf          *SettlementInstruction7                       `xml:"SttlmInf" json:"SttlmInf"`
+	PmtTpInf          *PaymentTypeInformation28                     `xml:"PmtTpInf,omitempty" json:"PmtTpInf,omitempty"`
+	InstgAgt          *BranchAndFinancialInstitutionIdentification6 `xml:"InstgAgt,omitempty" json:"InstgAgt,omitempty"`
+	InstdAgt          *BranchAndFinancialInstitutionIdentification6 `xml:"InstdAgt,omitempty" json:"InstdAgt,omitempty"`
 }
 
 // IBAN2007Identifier Must match the pattern [A-Z]{2,2}[0-9]{2,2}[a-zA-Z0-9]{1,30}
 type IBAN2007Identifier string
 
+func (v IBAN2007Identifier) Validate() error {
+	if err := checkPattern(string(v), `[A-Z]{2,2}[0-9]{2,2}[a-zA-Z0-9]{1,30}`); err != nil {
+		return err
+	}
+	return checkIBAN(string(v))
+}
+
 type ISODate time.Time
 
 func (t *ISODate) UnmarshalText(text []byte) error {
@@ -475,45 +827,65 @@ func (t ISOTime) MarshalText() ([]byte, error) {
 	return xsdTime(t).MarshalText()
 }
 
+type ImplementationSpecification1 struct {
+	Regy *Max350Text  `xml:"Regy" json:"Regy"`
+	Id   *Max2048Text `xml:"Id" json:"Id"`
+}
+
 // Instruction4Code May be one of PHOA, TELA
 type Instruction4Code string
 
+func (v Instruction4Code) Validate() error {
+	return checkEnumeration(string(v), "PHOA", "TELA")
+}
+
 type InstructionForCreditorAgent3 struct {
-	Cd       *ExternalCreditorAgentInstruction1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	InstrInf *Max140Text                            `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstrInf,omitempty" json:"InstrInf,omitempty"`
+	Cd       *ExternalCreditorAgentInstruction1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	InstrInf *Max140Text                            `xml:"InstrInf,omitempty" json:"InstrInf,omitempty"`
 }
 
 type InstructionForNextAgent1 struct {
-	Cd       *Instruction4Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	InstrInf *Max140Text       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstrInf,omitempty" json:"InstrInf,omitempty"`
+	Cd       *Instruction4Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	InstrInf *Max140Text       `xml:"InstrInf,omitempty" json:"InstrInf,omitempty"`
 }
 
 // LEIIdentifier Must match the pattern [A-Z0-9]{18,18}[0-9]{2,2}
 type LEIIdentifier string
 
+func (v LEIIdentifier) Validate() error {
+	if err := checkPattern(string(v), `[A-Z0-9]{18,18}[0-9]{2,2}`); err != nil {
+		return err
+	}
+	return checkLEI(string(v))
+}
+
 type LocalInstrument2Choice struct {
-	Cd    *ExternalLocalInstrument1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                    `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalLocalInstrument1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                    `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type MandateClassification1Choice struct {
-	Cd    *MandateClassification1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *MandateClassification1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                  `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 // MandateClassification1Code May be one of FIXE, USGB, VARI
 type MandateClassification1Code string
 
+func (v MandateClassification1Code) Validate() error {
+	return checkEnumeration(string(v), "FIXE", "USGB", "VARI")
+}
+
 type MandateSetupReason1Choice struct {
-	Cd    *ExternalMandateSetupReason1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max70Text                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalMandateSetupReason1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max70Text                       `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type MandateTypeInformation2 struct {
-	SvcLvl    *ServiceLevel8Choice          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SvcLvl,omitempty" json:"SvcLvl,omitempty"`
-	LclInstrm *LocalInstrument2Choice       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 LclInstrm,omitempty" json:"LclInstrm,omitempty"`
-	CtgyPurp  *CategoryPurpose1Choice       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CtgyPurp,omitempty" json:"CtgyPurp,omitempty"`
-	Clssfctn  *MandateClassification1Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Clssfctn,omitempty" json:"Clssfctn,omitempty"`
+	SvcLvl    *ServiceLevel8Choice          `xml:"SvcLvl,omitempty" json:"SvcLvl,omitempty"`
+	LclInstrm *LocalInstrument2Choice       `xml:"LclInstrm,omitempty" json:"LclInstrm,omitempty"`
+	CtgyPurp  *CategoryPurpose1Choice       `xml:"CtgyPurp,omitempty" json:"CtgyPurp,omitempty"`
+	Clssfctn  *MandateClassification1Choice `xml:"Clssfctn,omitempty" json:"Clssfctn,omitempty"`
 }
 
 type Max10KBinary []byte
@@ -524,272 +896,383 @@ func (t *Max10KBinary) UnmarshalText(text []byte) error {
 func (t Max10KBinary) MarshalText() ([]byte, error) {
 	return xsdBase64Binary(t).MarshalText()
 }
+func (t Max10KBinary) Validate() error {
+	if len(t) < 1 || len(t) > 10240 {
+		return fmt.Errorf("must be 1 to 10240 bytes long, got %d", len(t))
+	}
+	return nil
+}
 
 // Max10Text May be no more than 10 items long
 type Max10Text string
 
+func (v Max10Text) Validate() error {
+	return checkLength(string(v), 1, 10)
+}
+
 // Max128Text May be no more than 128 items long
 type Max128Text string
 
+func (v Max128Text) Validate() error {
+	return checkLength(string(v), 1, 128)
+}
+
 // Max140Text May be no more than 140 items long
 type Max140Text string
 
+func (v Max140Text) Validate() error {
+	return checkLength(string(v), 1, 140)
+}
+
 // Max15NumericText Must match the pattern [0-9]{1,15}
 type Max15NumericText string
 
+func (v Max15NumericText) Validate() error {
+	return checkPattern(string(v), `[0-9]{1,15}`)
+}
+
 // Max16Text May be no more than 16 items long
 type Max16Text string
 
+func (v Max16Text) Validate() error {
+	return checkLength(string(v), 1, 16)
+}
+
 // Max2048Text May be no more than 2048 items long
 type Max2048Text string
 
+func (v Max2048Text) Validate() error {
+	return checkLength(string(v), 1, 2048)
+}
+
 // Max34Text May be no more than 34 items long
 type Max34Text string
 
+func (v Max34Text) Validate() error {
+	return checkLength(string(v), 1, 34)
+}
+
 // Max350Text May be no more than 350 items long
 type Max350Text string
 
+func (v Max350Text) Validate() error {
+	return checkLength(string(v), 1, 350)
+}
+
 // Max35Text May be no more than 35 items long
 type Max35Text string
 
+func (v Max35Text) Validate() error {
+	return checkLength(string(v), 1, 35)
+}
+
 // Max4Text May be no more than 4 items long
 type Max4Text string
 
+func (v Max4Text) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
 // Max70Text May be no more than 70 items long
 type Max70Text string
 
+func (v Max70Text) Validate() error {
+	return checkLength(string(v), 1, 70)
+}
+
 type NameAndAddress16 struct {
-	Nm  *Max140Text      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Nm" json:"Nm"`
-	Adr *PostalAddress24 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Adr" json:"Adr"`
+	Nm  *Max140Text      `xml:"Nm" json:"Nm"`
+	Adr *PostalAddress24 `xml:"Adr" json:"Adr"`
 }
 
 // NamePrefix2Code May be one of DOCT, MADM, MISS, MIST, MIKS
 type NamePrefix2Code string
 
+func (v NamePrefix2Code) Validate() error {
+	return checkEnumeration(string(v), "DOCT", "MADM", "MISS", "MIST", "MIKS")
+}
+
 type OrganisationIdentification29 struct {
-	AnyBIC *AnyBICDec2014Identifier              `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 AnyBIC,omitempty" json:"AnyBIC,omitempty"`
-	LEI    *LEIIdentifier                        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 LEI,omitempty" json:"LEI,omitempty"`
-	Othr   []*GenericOrganisationIdentification1 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Othr,omitempty" json:"Othr,omitempty"`
+	AnyBIC *AnyBICDec2014Identifier              `xml:"AnyBIC,omitempty" json:"AnyBIC,omitempty"`
+	LEI    *LEIIdentifier                        `xml:"LEI,omitempty" json:"LEI,omitempty"`
+	Othr   []*GenericOrganisationIdentification1 `xml:"Othr,omitempty" json:"Othr,omitempty"`
 }
 
 type OrganisationIdentificationSchemeName1Choice struct {
-	Cd    *ExternalOrganisationIdentification1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                               `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalOrganisationIdentification1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                               `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type OtherContact1 struct {
-	ChanlTp *Max4Text   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ChanlTp" json:"ChanlTp"`
-	Id      *Max128Text `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Id,omitempty" json:"Id,omitempty"`
+	ChanlTp *Max4Text   `xml:"ChanlTp" json:"ChanlTp"`
+	Id      *Max128Text `xml:"Id,omitempty" json:"Id,omitempty"`
 }
 
 type Party38Choice struct {
-	OrgId  *OrganisationIdentification29 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 OrgId,omitempty" json:"OrgId,omitempty"`
-	PrvtId *PersonIdentification13       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PrvtId,omitempty" json:"PrvtId,omitempty"`
+	OrgId  *OrganisationIdentification29 `xml:"OrgId,omitempty" json:"OrgId,omitempty"`
+	PrvtId *PersonIdentification13       `xml:"PrvtId,omitempty" json:"PrvtId,omitempty"`
+}
+
+type Party44Choice struct {
+	OrgId *PartyIdentification135                       `xml:"OrgId,omitempty" json:"OrgId,omitempty"`
+	FIId  *BranchAndFinancialInstitutionIdentification6 `xml:"FIId,omitempty" json:"FIId,omitempty"`
 }
 
 type PartyIdentification135 struct {
-	Nm        *Max140Text      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Nm,omitempty" json:"Nm,omitempty"`
-	PstlAdr   *PostalAddress24 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PstlAdr,omitempty" json:"PstlAdr,omitempty"`
-	Id        *Party38Choice   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Id,omitempty" json:"Id,omitempty"`
-	CtryOfRes *CountryCode     `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CtryOfRes,omitempty" json:"CtryOfRes,omitempty"`
-	CtctDtls  *Contact4        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CtctDtls,omitempty" json:"CtctDtls,omitempty"`
+	Nm        *Max140Text      `xml:"Nm,omitempty" json:"Nm,omitempty"`
+	PstlAdr   *PostalAddress24 `xml:"PstlAdr,omitempty" json:"PstlAdr,omitempty"`
+	Id        *Party38Choice   `xml:"Id,omitempty" json:"Id,omitempty"`
+	CtryOfRes *CountryCode     `xml:"CtryOfRes,omitempty" json:"CtryOfRes,omitempty"`
+	CtctDtls  *Contact4        `xml:"CtctDtls,omitempty" json:"CtctDtls,omitempty"`
 }
 
 type PaymentIdentification13 struct {
-	InstrId    *Max35Text        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstrId,omitempty" json:"InstrId,omitempty"`
-	EndToEndId *Max35Text        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 EndToEndId" json:"EndToEndId"`
-	TxId       *Max35Text        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TxId,omitempty" json:"TxId,omitempty"`
-	UETR       *UUIDv4Identifier `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 UETR,omitempty" json:"UETR,omitempty"`
-	ClrSysRef  *Max35Text        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ClrSysRef,omitempty" json:"ClrSysRef,omitempty"`
+	InstrId    *Max35Text        `xml:"InstrId,omitempty" json:"InstrId,omitempty"`
+	EndToEndId *Max35Text        `xml:"EndToEndId" json:"EndToEndId"`
+	TxId       *Max35Text        `xml:"TxId,omitempty" json:"TxId,omitempty"`
+	UETR       *UUIDv4Identifier `xml:"UETR,omitempty" json:"UETR,omitempty"`
+	ClrSysRef  *Max35Text        `xml:"ClrSysRef,omitempty" json:"ClrSysRef,omitempty"`
 }
 
 type PaymentTypeInformation28 struct {
-	InstrPrty *Priority2Code          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstrPrty,omitempty" json:"InstrPrty,omitempty"`
-	ClrChanl  *ClearingChannel2Code   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ClrChanl,omitempty" json:"ClrChanl,omitempty"`
-	SvcLvl    []*ServiceLevel8Choice  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SvcLvl,omitempty" json:"SvcLvl,omitempty"`
-	LclInstrm *LocalInstrument2Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 LclInstrm,omitempty" json:"LclInstrm,omitempty"`
-	CtgyPurp  *CategoryPurpose1Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CtgyPurp,omitempty" json:"CtgyPurp,omitempty"`
+	InstrPrty *Priority2Code          `xml:"InstrPrty,omitempty" json:"InstrPrty,omitempty"`
+	ClrChanl  *ClearingChannel2Code   `xml:"ClrChanl,omitempty" json:"ClrChanl,omitempty"`
+	SvcLvl    []*ServiceLevel8Choice  `xml:"SvcLvl,omitempty" json:"SvcLvl,omitempty"`
+	LclInstrm *LocalInstrument2Choice `xml:"LclInstrm,omitempty" json:"LclInstrm,omitempty"`
+	CtgyPurp  *CategoryPurpose1Choice `xml:"CtgyPurp,omitempty" json:"CtgyPurp,omitempty"`
 }
 
 type PersonIdentification13 struct {
-	DtAndPlcOfBirth *DateAndPlaceOfBirth1           `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DtAndPlcOfBirth,omitempty" json:"DtAndPlcOfBirth,omitempty"`
-	Othr            []*GenericPersonIdentification1 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Othr,omitempty" json:"Othr,omitempty"`
+	DtAndPlcOfBirth *DateAndPlaceOfBirth1           `xml:"DtAndPlcOfBirth,omitempty" json:"DtAndPlcOfBirth,omitempty"`
+	Othr            []*GenericPersonIdentification1 `xml:"Othr,omitempty" json:"Othr,omitempty"`
 }
 
 type PersonIdentificationSchemeName1Choice struct {
-	Cd    *ExternalPersonIdentification1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalPersonIdentification1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                         `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
+}
+
+// PercentageRate May be no more than 11 digits long, with no more than 10 fraction digits
+type PercentageRate Decimal
+
+func (d *PercentageRate) UnmarshalText(text []byte) error {
+	return (*Decimal)(d).UnmarshalText(text)
+}
+func (d PercentageRate) MarshalText() ([]byte, error) {
+	return Decimal(d).MarshalText()
+}
+func (d *PercentageRate) UnmarshalJSON(data []byte) error {
+	return (*Decimal)(d).UnmarshalJSON(data)
+}
+func (d PercentageRate) MarshalJSON() ([]byte, error) {
+	return Decimal(d).MarshalJSON()
+}
+func (d PercentageRate) Validate() error {
+	return checkDigits(Decimal(d), 11, 10)
 }
 
 // PhoneNumber Must match the pattern \+[0-9]{1,3}-[0-9()+\-]{1,30}
 type PhoneNumber string
 
+func (v PhoneNumber) Validate() error {
+	return checkPattern(string(v), `\+[0-9]{1,3}-[0-9()+\-]{1,30}`)
+}
+
 type PostalAddress24 struct {
-	AdrTp       *AddressType3Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 AdrTp,omitempty" json:"AdrTp,omitempty"`
-	Dept        *Max70Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Dept,omitempty" json:"Dept,omitempty"`
-	SubDept     *Max70Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SubDept,omitempty" json:"SubDept,omitempty"`
-	StrtNm      *Max70Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 StrtNm,omitempty" json:"StrtNm,omitempty"`
-	BldgNb      *Max16Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 BldgNb,omitempty" json:"BldgNb,omitempty"`
-	BldgNm      *Max35Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 BldgNm,omitempty" json:"BldgNm,omitempty"`
-	Flr         *Max70Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Flr,omitempty" json:"Flr,omitempty"`
-	PstBx       *Max16Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PstBx,omitempty" json:"PstBx,omitempty"`
-	Room        *Max70Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Room,omitempty" json:"Room,omitempty"`
-	PstCd       *Max16Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PstCd,omitempty" json:"PstCd,omitempty"`
-	TwnNm       *Max35Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TwnNm,omitempty" json:"TwnNm,omitempty"`
-	TwnLctnNm   *Max35Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TwnLctnNm,omitempty" json:"TwnLctnNm,omitempty"`
-	DstrctNm    *Max35Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DstrctNm,omitempty" json:"DstrctNm,omitempty"`
-	CtrySubDvsn *Max35Text          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CtrySubDvsn,omitempty" json:"CtrySubDvsn,omitempty"`
-	Ctry        *CountryCode        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Ctry,omitempty" json:"Ctry,omitempty"`
-	AdrLine     []*Max70Text        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 AdrLine,omitempty" json:"AdrLine,omitempty"`
+	AdrTp       *AddressType3Choice `xml:"AdrTp,omitempty" json:"AdrTp,omitempty"`
+	Dept        *Max70Text          `xml:"Dept,omitempty" json:"Dept,omitempty"`
+	SubDept     *Max70Text          `xml:"SubDept,omitempty" json:"SubDept,omitempty"`
+	StrtNm      *Max70Text          `xml:"StrtNm,omitempty" json:"StrtNm,omitempty"`
+	BldgNb      *Max16Text          `xml:"BldgNb,omitempty" json:"BldgNb,omitempty"`
+	BldgNm      *Max35Text          `xml:"BldgNm,omitempty" json:"BldgNm,omitempty"`
+	Flr         *Max70Text          `xml:"Flr,omitempty" json:"Flr,omitempty"`
+	PstBx       *Max16Text          `xml:"PstBx,omitempty" json:"PstBx,omitempty"`
+	Room        *Max70Text          `xml:"Room,omitempty" json:"Room,omitempty"`
+	PstCd       *Max16Text          `xml:"PstCd,omitempty" json:"PstCd,omitempty"`
+	TwnNm       *Max35Text          `xml:"TwnNm,omitempty" json:"TwnNm,omitempty"`
+	TwnLctnNm   *Max35Text          `xml:"TwnLctnNm,omitempty" json:"TwnLctnNm,omitempty"`
+	DstrctNm    *Max35Text          `xml:"DstrctNm,omitempty" json:"DstrctNm,omitempty"`
+	CtrySubDvsn *Max35Text          `xml:"CtrySubDvsn,omitempty" json:"CtrySubDvsn,omitempty"`
+	Ctry        *CountryCode        `xml:"Ctry,omitempty" json:"Ctry,omitempty"`
+	AdrLine     []*Max70Text        `xml:"AdrLine,omitempty" json:"AdrLine,omitempty"`
 }
 
 // PreferredContactMethod1Code May be one of LETT, MAIL, PHON, FAXX, CELL
 type PreferredContactMethod1Code string
 
+func (v PreferredContactMethod1Code) Validate() error {
+	return checkEnumeration(string(v), "LETT", "MAIL", "PHON", "FAXX", "CELL")
+}
+
 // Priority2Code May be one of HIGH, NORM
 type Priority2Code string
 
+func (v Priority2Code) Validate() error {
+	return checkEnumeration(string(v), "HIGH", "NORM")
+}
+
 // Priority3Code May be one of URGT, HIGH, NORM
 type Priority3Code string
 
+func (v Priority3Code) Validate() error {
+	return checkEnumeration(string(v), "URGT", "HIGH", "NORM")
+}
+
 type ProxyAccountIdentification1 struct {
-	Tp *ProxyAccountType1Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp,omitempty" json:"Tp,omitempty"`
-	Id *Max2048Text             `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Id" json:"Id"`
+	Tp *ProxyAccountType1Choice `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	Id *Max2048Text             `xml:"Id" json:"Id"`
 }
 
 type ProxyAccountType1Choice struct {
-	Cd    *ExternalProxyAccountType1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                     `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalProxyAccountType1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                     `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type Purpose2Choice struct {
-	Cd    *ExternalPurpose1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text            `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalPurpose1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text            `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type ReferredDocumentInformation7 struct {
-	Tp       *ReferredDocumentType4      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp,omitempty" json:"Tp,omitempty"`
-	Nb       *Max35Text                  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Nb,omitempty" json:"Nb,omitempty"`
-	RltdDt   *ISODate                    `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RltdDt,omitempty" json:"RltdDt,omitempty"`
-	LineDtls []*DocumentLineInformation1 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 LineDtls,omitempty" json:"LineDtls,omitempty"`
+	Tp       *ReferredDocumentType4      `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	Nb       *Max35Text                  `xml:"Nb,omitempty" json:"Nb,omitempty"`
+	RltdDt   *ISODate                    `xml:"RltdDt,omitempty" json:"RltdDt,omitempty"`
+	LineDtls []*DocumentLineInformation1 `xml:"LineDtls,omitempty" json:"LineDtls,omitempty"`
 }
 
 type ReferredDocumentType3Choice struct {
-	Cd    *DocumentType6Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *DocumentType6Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text         `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type ReferredDocumentType4 struct {
-	CdOrPrtry *ReferredDocumentType3Choice `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdOrPrtry" json:"CdOrPrtry"`
-	Issr      *Max35Text                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Issr,omitempty" json:"Issr,omitempty"`
+	CdOrPrtry *ReferredDocumentType3Choice `xml:"CdOrPrtry" json:"CdOrPrtry"`
+	Issr      *Max35Text                   `xml:"Issr,omitempty" json:"Issr,omitempty"`
 }
 
 type RegulatoryAuthority2 struct {
-	Nm   *Max140Text  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Nm,omitempty" json:"Nm,omitempty"`
-	Ctry *CountryCode `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Ctry,omitempty" json:"Ctry,omitempty"`
+	Nm   *Max140Text  `xml:"Nm,omitempty" json:"Nm,omitempty"`
+	Ctry *CountryCode `xml:"Ctry,omitempty" json:"Ctry,omitempty"`
 }
 
 type RegulatoryReporting3 struct {
-	DbtCdtRptgInd *RegulatoryReportingType1Code     `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DbtCdtRptgInd,omitempty" json:"DbtCdtRptgInd,omitempty"`
-	Authrty       *RegulatoryAuthority2             `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Authrty,omitempty" json:"Authrty,omitempty"`
-	Dtls          []*StructuredRegulatoryReporting3 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Dtls,omitempty" json:"Dtls,omitempty"`
+	DbtCdtRptgInd *RegulatoryReportingType1Code     `xml:"DbtCdtRptgInd,omitempty" json:"DbtCdtRptgInd,omitempty"`
+	Authrty       *RegulatoryAuthority2             `xml:"Authrty,omitempty" json:"Authrty,omitempty"`
+	Dtls          []*StructuredRegulatoryReporting3 `xml:"Dtls,omitempty" json:"Dtls,omitempty"`
 }
 
 // RegulatoryReportingType1Code May be one of CRED, DEBT, BOTH
 type RegulatoryReportingType1Code string
 
+func (v RegulatoryReportingType1Code) Validate() error {
+	return checkEnumeration(string(v), "CRED", "DEBT", "BOTH")
+}
+
 type RemittanceAmount2 struct {
-	DuePyblAmt        *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DuePyblAmt,omitempty" json:"DuePyblAmt,omitempty"`
-	DscntApldAmt      []*DiscountAmountAndType1          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DscntApldAmt,omitempty" json:"DscntApldAmt,omitempty"`
-	CdtNoteAmt        *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdtNoteAmt,omitempty" json:"CdtNoteAmt,omitempty"`
-	TaxAmt            []*TaxAmountAndType1               `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TaxAmt,omitempty" json:"TaxAmt,omitempty"`
-	AdjstmntAmtAndRsn []*DocumentAdjustment1             `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 AdjstmntAmtAndRsn,omitempty" json:"AdjstmntAmtAndRsn,omitempty"`
-	RmtdAmt           *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RmtdAmt,omitempty" json:"RmtdAmt,omitempty"`
+	DuePyblAmt        *ActiveOrHistoricCurrencyAndAmount `xml:"DuePyblAmt,omitempty" json:"DuePyblAmt,omitempty"`
+	DscntApldAmt      []*DiscountAmountAndType1          `xml:"DscntApldAmt,omitempty" json:"DscntApldAmt,omitempty"`
+	CdtNoteAmt        *ActiveOrHistoricCurrencyAndAmount `xml:"CdtNoteAmt,omitempty" json:"CdtNoteAmt,omitempty"`
+	TaxAmt            []*TaxAmountAndType1               `xml:"TaxAmt,omitempty" json:"TaxAmt,omitempty"`
+	AdjstmntAmtAndRsn []*DocumentAdjustment1             `xml:"AdjstmntAmtAndRsn,omitempty" json:"AdjstmntAmtAndRsn,omitempty"`
+	RmtdAmt           *ActiveOrHistoricCurrencyAndAmount `xml:"RmtdAmt,omitempty" json:"RmtdAmt,omitempty"`
 }
 
 type RemittanceAmount3 struct {
-	DuePyblAmt        *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DuePyblAmt,omitempty" json:"DuePyblAmt,omitempty"`
-	DscntApldAmt      []*DiscountAmountAndType1          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DscntApldAmt,omitempty" json:"DscntApldAmt,omitempty"`
-	CdtNoteAmt        *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdtNoteAmt,omitempty" json:"CdtNoteAmt,omitempty"`
-	TaxAmt            []*TaxAmountAndType1               `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TaxAmt,omitempty" json:"TaxAmt,omitempty"`
-	AdjstmntAmtAndRsn []*DocumentAdjustment1             `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 AdjstmntAmtAndRsn,omitempty" json:"AdjstmntAmtAndRsn,omitempty"`
-	RmtdAmt           *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RmtdAmt,omitempty" json:"RmtdAmt,omitempty"`
+	DuePyblAmt        *ActiveOrHistoricCurrencyAndAmount `xml:"DuePyblAmt,omitempty" json:"DuePyblAmt,omitempty"`
+	DscntApldAmt      []*DiscountAmountAndType1          `xml:"DscntApldAmt,omitempty" json:"DscntApldAmt,omitempty"`
+	CdtNoteAmt        *ActiveOrHistoricCurrencyAndAmount `xml:"CdtNoteAmt,omitempty" json:"CdtNoteAmt,omitempty"`
+	TaxAmt            []*TaxAmountAndType1               `xml:"TaxAmt,omitempty" json:"TaxAmt,omitempty"`
+	AdjstmntAmtAndRsn []*DocumentAdjustment1             `xml:"AdjstmntAmtAndRsn,omitempty" json:"AdjstmntAmtAndRsn,omitempty"`
+	RmtdAmt           *ActiveOrHistoricCurrencyAndAmount `xml:"RmtdAmt,omitempty" json:"RmtdAmt,omitempty"`
 }
 
 type RemittanceInformation16 struct {
-	Ustrd []*Max140Text                        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Ustrd,omitempty" json:"Ustrd,omitempty"`
-	Strd  []*StructuredRemittanceInformation16 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Strd,omitempty" json:"Strd,omitempty"`
+	Ustrd []*Max140Text                        `xml:"Ustrd,omitempty" json:"Ustrd,omitempty"`
+	Strd  []*StructuredRemittanceInformation16 `xml:"Strd,omitempty" json:"Strd,omitempty"`
 }
 
 type RemittanceLocation7 struct {
-	RmtId       *Max35Text                 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RmtId,omitempty" json:"RmtId,omitempty"`
-	RmtLctnDtls []*RemittanceLocationData1 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RmtLctnDtls,omitempty" json:"RmtLctnDtls,omitempty"`
+	RmtId       *Max35Text                 `xml:"RmtId,omitempty" json:"RmtId,omitempty"`
+	RmtLctnDtls []*RemittanceLocationData1 `xml:"RmtLctnDtls,omitempty" json:"RmtLctnDtls,omitempty"`
 }
 
 type RemittanceLocationData1 struct {
-	Mtd        *RemittanceLocationMethod2Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Mtd" json:"Mtd"`
-	ElctrncAdr *Max2048Text                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ElctrncAdr,omitempty" json:"ElctrncAdr,omitempty"`
-	PstlAdr    *NameAndAddress16              `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PstlAdr,omitempty" json:"PstlAdr,omitempty"`
+	Mtd        *RemittanceLocationMethod2Code `xml:"Mtd" json:"Mtd"`
+	ElctrncAdr *Max2048Text                   `xml:"ElctrncAdr,omitempty" json:"ElctrncAdr,omitempty"`
+	PstlAdr    *NameAndAddress16              `xml:"PstlAdr,omitempty" json:"PstlAdr,omitempty"`
 }
 
 // RemittanceLocationMethod2Code May be one of FAXI, EDIC, URID, EMAL, POST, SMSM
 type RemittanceLocationMethod2Code string
 
+func (v RemittanceLocationMethod2Code) Validate() error {
+	return checkEnumeration(string(v), "FAXI", "EDIC", "URID", "EMAL", "POST", "SMSM")
+}
+
 type ServiceLevel8Choice struct {
-	Cd    *ExternalServiceLevel1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalServiceLevel1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                 `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type SettlementDateTimeIndication1 struct {
-	DbtDtTm *ISODateTime `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DbtDtTm,omitempty" json:"DbtDtTm,omitempty"`
-	CdtDtTm *ISODateTime `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdtDtTm,omitempty" json:"CdtDtTm,omitempty"`
+	DbtDtTm *ISODateTime `xml:"DbtDtTm,omitempty" json:"DbtDtTm,omitempty"`
+	CdtDtTm *ISODateTime `xml:"CdtDtTm,omitempty" json:"CdtDtTm,omitempty"`
 }
 
 type SettlementInstruction7 struct {
-	SttlmMtd             *SettlementMethod1Code                        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SttlmMtd" json:"SttlmMtd"`
-	SttlmAcct            *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SttlmAcct,omitempty" json:"SttlmAcct,omitempty"`
-	ClrSys               *ClearingSystemIdentification3Choice          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ClrSys,omitempty" json:"ClrSys,omitempty"`
-	InstgRmbrsmntAgt     *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstgRmbrsmntAgt,omitempty" json:"InstgRmbrsmntAgt,omitempty"`
-	InstgRmbrsmntAgtAcct *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstgRmbrsmntAgtAcct,omitempty" json:"InstgRmbrsmntAgtAcct,omitempty"`
-	InstdRmbrsmntAgt     *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstdRmbrsmntAgt,omitempty" json:"InstdRmbrsmntAgt,omitempty"`
-	InstdRmbrsmntAgtAcct *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 InstdRmbrsmntAgtAcct,omitempty" json:"InstdRmbrsmntAgtAcct,omitempty"`
-	ThrdRmbrsmntAgt      *BranchAndFinancialInstitutionIdentification6 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ThrdRmbrsmntAgt,omitempty" json:"ThrdRmbrsmntAgt,omitempty"`
-	ThrdRmbrsmntAgtAcct  *CashAccount38                                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 ThrdRmbrsmntAgtAcct,omitempty" json:"thrd-rmbrsmnt-agt-acct,omitempty"`
+	SttlmMtd             *SettlementMethod1Code                        `xml:"SttlmMtd" json:"SttlmMtd"`
+	SttlmAcct            *CashAccount38                                `xml:"SttlmAcct,omitempty" json:"SttlmAcct,omitempty"`
+	ClrSys               *ClearingSystemIdentification3Choice          `xml:"ClrSys,omitempty" json:"ClrSys,omitempty"`
+	InstgRmbrsmntAgt     *BranchAndFinancialInstitutionIdentification6 `xml:"InstgRmbrsmntAgt,omitempty" json:"InstgRmbrsmntAgt,omitempty"`
+	InstgRmbrsmntAgtAcct *CashAccount38                                `xml:"InstgRmbrsmntAgtAcct,omitempty" json:"InstgRmbrsmntAgtAcct,omitempty"`
+	InstdRmbrsmntAgt     *BranchAndFinancialInstitutionIdentification6 `xml:"InstdRmbrsmntAgt,omitempty" json:"InstdRmbrsmntAgt,omitempty"`
+	InstdRmbrsmntAgtAcct *CashAccount38                                `xml:"InstdRmbrsmntAgtAcct,omitempty" json:"InstdRmbrsmntAgtAcct,omitempty"`
+	ThrdRmbrsmntAgt      *BranchAndFinancialInstitutionIdentification6 `xml:"ThrdRmbrsmntAgt,omitempty" json:"ThrdRmbrsmntAgt,omitempty"`
+	ThrdRmbrsmntAgtAcct  *CashAccount38                                `xml:"ThrdRmbrsmntAgtAcct,omitempty" json:"thrd-rmbrsmnt-agt-acct,omitempty"`
 }
 
 // SettlementMethod1Code May be one of INDA, INGA, COVE, CLRG
 type SettlementMethod1Code string
 
+func (v SettlementMethod1Code) Validate() error {
+	return checkEnumeration(string(v), "INDA", "INGA", "COVE", "CLRG")
+}
+
 type SettlementTimeRequest2 struct {
-	CLSTm  *ISOTime `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CLSTm,omitempty" json:"CLSTm,omitempty"`
-	TillTm *ISOTime `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TillTm,omitempty" json:"TillTm,omitempty"`
-	FrTm   *ISOTime `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 FrTm,omitempty" json:"fr-tm,omitempty"`
-	RjctTm *ISOTime `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RjctTm,omitempty" json:"RjctTm,omitempty"`
+	CLSTm  *ISOTime `xml:"CLSTm,omitempty" json:"CLSTm,omitempty"`
+	TillTm *ISOTime `xml:"TillTm,omitempty" json:"TillTm,omitempty"`
+	FrTm   *ISOTime `xml:"FrTm,omitempty" json:"fr-tm,omitempty"`
+	RjctTm *ISOTime `xml:"RjctTm,omitempty" json:"RjctTm,omitempty"`
+}
+
+// SignatureEnvelope holds the signature (e.g. XML-DSig) as raw XML so it can be verified byte for byte
+type SignatureEnvelope struct {
+	Item string `xml:",innerxml" json:"Item"`
 }
 
 type StructuredRegulatoryReporting3 struct {
-	Tp   *Max35Text                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp,omitempty" json:"Tp,omitempty"`
-	Dt   *ISODate                           `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Dt,omitempty" json:"Dt,omitempty"`
-	Ctry *CountryCode                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Ctry,omitempty" json:"Ctry,omitempty"`
-	Cd   *Max10Text                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Amt  *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Amt,omitempty" json:"Amt,omitempty"`
-	Inf  []*Max35Text                       `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Inf,omitempty" json:"Inf,omitempty"`
+	Tp   *Max35Text                         `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	Dt   *ISODate                           `xml:"Dt,omitempty" json:"Dt,omitempty"`
+	Ctry *CountryCode                       `xml:"Ctry,omitempty" json:"Ctry,omitempty"`
+	Cd   *Max10Text                         `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Amt  *ActiveOrHistoricCurrencyAndAmount `xml:"Amt,omitempty" json:"Amt,omitempty"`
+	Inf  []*Max35Text                       `xml:"Inf,omitempty" json:"Inf,omitempty"`
 }
 
 type StructuredRemittanceInformation16 struct {
-	RfrdDocInf  []*ReferredDocumentInformation7 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RfrdDocInf,omitempty" json:"RfrdDocInf,omitempty"`
-	RfrdDocAmt  *RemittanceAmount2              `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RfrdDocAmt,omitempty" json:"RfrdDocAmt,omitempty"`
-	CdtrRefInf  *CreditorReferenceInformation2  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CdtrRefInf,omitempty" json:"CdtrRefInf,omitempty"`
-	Invcr       *PartyIdentification135         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Invcr,omitempty" json:"Invcr,omitempty"`
-	Invcee      *PartyIdentification135         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Invcee,omitempty" json:"Invcee,omitempty"`
-	TaxRmt      *TaxInformation7                `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TaxRmt,omitempty" json:"TaxRmt,omitempty"`
-	GrnshmtRmt  *Garnishment3                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 GrnshmtRmt,omitempty" json:"GrnshmtRmt,omitempty"`
-	AddtlRmtInf []*Max140Text                   `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 AddtlRmtInf,omitempty" json:"AddtlRmtInf,omitempty"`
+	RfrdDocInf  []*ReferredDocumentInformation7 `xml:"RfrdDocInf,omitempty" json:"RfrdDocInf,omitempty"`
+	RfrdDocAmt  *RemittanceAmount2              `xml:"RfrdDocAmt,omitempty" json:"RfrdDocAmt,omitempty"`
+	CdtrRefInf  *CreditorReferenceInformation2  `xml:"CdtrRefInf,omitempty" json:"CdtrRefInf,omitempty"`
+	Invcr       *PartyIdentification135         `xml:"Invcr,omitempty" json:"Invcr,omitempty"`
+	Invcee      *PartyIdentification135         `xml:"Invcee,omitempty" json:"Invcee,omitempty"`
+	TaxRmt      *TaxInformation7                `xml:"TaxRmt,omitempty" json:"TaxRmt,omitempty"`
+	GrnshmtRmt  *Garnishment3                   `xml:"GrnshmtRmt,omitempty" json:"GrnshmtRmt,omitempty"`
+	AddtlRmtInf []*Max140Text                   `xml:"AddtlRmtInf,omitempty" json:"AddtlRmtInf,omitempty"`
 }
 
 type SupplementaryData1 struct {
-	PlcAndNm *Max350Text                 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 PlcAndNm,omitempty" json:"PlcAndNm,omitempty"`
-	Envlp    *SupplementaryDataEnvelope1 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Envlp" json:"Envlp"`
+	PlcAndNm *Max350Text                 `xml:"PlcAndNm,omitempty" json:"PlcAndNm,omitempty"`
+	Envlp    *SupplementaryDataEnvelope1 `xml:"Envlp" json:"Envlp"`
 }
 
 type SupplementaryDataEnvelope1 struct {
@@ -797,96 +1280,107 @@ type SupplementaryDataEnvelope1 struct {
 }
 
 type TaxAmount2 struct {
-	Rate         float64                            `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Rate,omitempty" json:"Rate,omitempty"`
-	TaxblBaseAmt *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TaxblBaseAmt,omitempty" json:"TaxblBaseAmt,omitempty"`
-	TtlAmt       *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TtlAmt,omitempty" json:"TtlAmt,omitempty"`
-	Dtls         []*TaxRecordDetails2               `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Dtls,omitempty" json:"Dtls,omitempty"`
+	Rate         *PercentageRate                    `xml:"Rate,omitempty" json:"Rate,omitempty"`
+	TaxblBaseAmt *ActiveOrHistoricCurrencyAndAmount `xml:"TaxblBaseAmt,omitempty" json:"TaxblBaseAmt,omitempty"`
+	TtlAmt       *ActiveOrHistoricCurrencyAndAmount `xml:"TtlAmt,omitempty" json:"TtlAmt,omitempty"`
+	Dtls         []*TaxRecordDetails2               `xml:"Dtls,omitempty" json:"Dtls,omitempty"`
 }
 
 type TaxAmountAndType1 struct {
-	Tp  *TaxAmountType1Choice              `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp,omitempty" json:"Tp,omitempty"`
-	Amt *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Amt" json:"Amt"`
+	Tp  *TaxAmountType1Choice              `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	Amt *ActiveOrHistoricCurrencyAndAmount `xml:"Amt" json:"Amt"`
 }
 
 type TaxAmountType1Choice struct {
-	Cd    *ExternalTaxAmountType1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cd,omitempty" json:"Cd,omitempty"`
-	Prtry *Max35Text                  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prtry,omitempty" json:"Prtry,omitempty"`
+	Cd    *ExternalTaxAmountType1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                  `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
 }
 
 type TaxAuthorisation1 struct {
-	Titl *Max35Text  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Titl,omitempty" json:"Titl,omitempty"`
-	Nm   *Max140Text `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Nm,omitempty" json:"Nm,omitempty"`
+	Titl *Max35Text  `xml:"Titl,omitempty" json:"Titl,omitempty"`
+	Nm   *Max140Text `xml:"Nm,omitempty" json:"Nm,omitempty"`
 }
 
 type TaxInformation7 struct {
-	Cdtr            *TaxParty1                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cdtr,omitempty" json:"Cdtr,omitempty"`
-	Dbtr            *TaxParty2                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Dbtr,omitempty" json:"Dbtr,omitempty"`
-	UltmtDbtr       *TaxParty2                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 UltmtDbtr,omitempty" json:"UltmtDbtr,omitempty"`
-	AdmstnZone      *Max35Text                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 AdmstnZone,omitempty" json:"AdmstnZone,omitempty"`
-	RefNb           *Max140Text                        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RefNb,omitempty" json:"RefNb,omitempty"`
-	Mtd             *Max35Text                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Mtd,omitempty" json:"Mtd,omitempty"`
-	TtlTaxblBaseAmt *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TtlTaxblBaseAmt,omitempty" json:"TtlTaxblBaseAmt,omitempty"`
-	TtlTaxAmt       *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TtlTaxAmt,omitempty" json:"TtlTaxAmt,omitempty"`
-	Dt              *ISODate                           `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Dt,omitempty" json:"Dt,omitempty"`
-	SeqNb           float64                            `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SeqNb,omitempty" json:"SeqNb,omitempty"`
-	Rcrd            []*TaxRecord2                      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Rcrd,omitempty" json:"Rcrd,omitempty"`
+	Cdtr            *TaxParty1                         `xml:"Cdtr,omitempty" json:"Cdtr,omitempty"`
+	Dbtr            *TaxParty2                         `xml:"Dbtr,omitempty" json:"Dbtr,omitempty"`
+	UltmtDbtr       *TaxParty2                         `xml:"UltmtDbtr,omitempty" json:"UltmtDbtr,omitempty"`
+	AdmstnZone      *Max35Text                         `xml:"AdmstnZone,omitempty" json:"AdmstnZone,omitempty"`
+	RefNb           *Max140Text                        `xml:"RefNb,omitempty" json:"RefNb,omitempty"`
+	Mtd             *Max35Text                         `xml:"Mtd,omitempty" json:"Mtd,omitempty"`
+	TtlTaxblBaseAmt *ActiveOrHistoricCurrencyAndAmount `xml:"TtlTaxblBaseAmt,omitempty" json:"TtlTaxblBaseAmt,omitempty"`
+	TtlTaxAmt       *ActiveOrHistoricCurrencyAndAmount `xml:"TtlTaxAmt,omitempty" json:"TtlTaxAmt,omitempty"`
+	Dt              *ISODate                           `xml:"Dt,omitempty" json:"Dt,omitempty"`
+	SeqNb           float64                            `xml:"SeqNb,omitempty" json:"SeqNb,omitempty"`
+	Rcrd            []*TaxRecord2                      `xml:"Rcrd,omitempty" json:"Rcrd,omitempty"`
 }
 
 type TaxInformation8 struct {
-	Cdtr            *TaxParty1                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Cdtr,omitempty" json:"Cdtr,omitempty"`
-	Dbtr            *TaxParty2                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Dbtr,omitempty" json:"Dbtr,omitempty"`
-	AdmstnZone      *Max35Text                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 AdmstnZone,omitempty" json:"AdmstnZone,omitempty"`
-	RefNb           *Max140Text                        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RefNb,omitempty" json:"RefNb,omitempty"`
-	Mtd             *Max35Text                         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Mtd,omitempty" json:"Mtd,omitempty"`
-	TtlTaxblBaseAmt *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TtlTaxblBaseAmt,omitempty" json:"TtlTaxblBaseAmt,omitempty"`
-	TtlTaxAmt       *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TtlTaxAmt,omitempty" json:"TtlTaxAmt,omitempty"`
-	Dt              *ISODate                           `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Dt,omitempty" json:"Dt,omitempty"`
-	SeqNb           float64                            `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 SeqNb,omitempty" json:"SeqNb,omitempty"`
-	Rcrd            []*TaxRecord2                      `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Rcrd,omitempty" json:"Rcrd,omitempty"`
+	Cdtr            *TaxParty1                         `xml:"Cdtr,omitempty" json:"Cdtr,omitempty"`
+	Dbtr            *TaxParty2                         `xml:"Dbtr,omitempty" json:"Dbtr,omitempty"`
+	AdmstnZone      *Max35Text                         `xml:"AdmstnZone,omitempty" json:"AdmstnZone,omitempty"`
+	RefNb           *Max140Text                        `xml:"RefNb,omitempty" json:"RefNb,omitempty"`
+	Mtd             *Max35Text                         `xml:"Mtd,omitempty" json:"Mtd,omitempty"`
+	TtlTaxblBaseAmt *ActiveOrHistoricCurrencyAndAmount `xml:"TtlTaxblBaseAmt,omitempty" json:"TtlTaxblBaseAmt,omitempty"`
+	TtlTaxAmt       *ActiveOrHistoricCurrencyAndAmount `xml:"TtlTaxAmt,omitempty" json:"TtlTaxAmt,omitempty"`
+	Dt              *ISODate                           `xml:"Dt,omitempty" json:"Dt,omitempty"`
+	SeqNb           float64                            `xml:"SeqNb,omitempty" json:"SeqNb,omitempty"`
+	Rcrd            []*TaxRecord2                      `xml:"Rcrd,omitempty" json:"Rcrd,omitempty"`
 }
 
 type TaxParty1 struct {
-	TaxId  *Max35Text `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TaxId,omitempty" json:"TaxId,omitempty"`
-	RegnId *Max35Text `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RegnId,omitempty" json:"RegnId,omitempty"`
-	TaxTp  *Max35Text `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TaxTp,omitempty" json:"TaxTp,omitempty"`
+	TaxId  *Max35Text `xml:"TaxId,omitempty" json:"TaxId,omitempty"`
+	RegnId *Max35Text `xml:"RegnId,omitempty" json:"RegnId,omitempty"`
+	TaxTp  *Max35Text `xml:"TaxTp,omitempty" json:"TaxTp,omitempty"`
 }
 
 type TaxParty2 struct {
-	TaxId   *Max35Text         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TaxId,omitempty" json:"TaxId,omitempty"`
-	RegnId  *Max35Text         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 RegnId,omitempty" json:"RegnId,omitempty"`
-	TaxTp   *Max35Text         `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TaxTp,omitempty" json:"TaxTp,omitempty"`
-	Authstn *TaxAuthorisation1 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Authstn,omitempty" json:"Authstn,omitempty"`
+	TaxId   *Max35Text         `xml:"TaxId,omitempty" json:"TaxId,omitempty"`
+	RegnId  *Max35Text         `xml:"RegnId,omitempty" json:"RegnId,omitempty"`
+	TaxTp   *Max35Text         `xml:"TaxTp,omitempty" json:"TaxTp,omitempty"`
+	Authstn *TaxAuthorisation1 `xml:"Authstn,omitempty" json:"Authstn,omitempty"`
 }
 
 type TaxPeriod2 struct {
-	Yr     *ISODate              `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Yr,omitempty" json:"Yr,omitempty"`
-	Tp     *TaxRecordPeriod1Code `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp,omitempty" json:"Tp,omitempty"`
-	FrToDt *DatePeriod2          `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 FrToDt,omitempty" json:"FrToDt,omitempty"`
+	Yr     *ISODate              `xml:"Yr,omitempty" json:"Yr,omitempty"`
+	Tp     *TaxRecordPeriod1Code `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	FrToDt *DatePeriod2          `xml:"FrToDt,omitempty" json:"FrToDt,omitempty"`
 }
 
 type TaxRecord2 struct {
-	Tp       *Max35Text  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Tp,omitempty" json:"Tp,omitempty"`
-	Ctgy     *Max35Text  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Ctgy,omitempty" json:"Ctgy,omitempty"`
-	CtgyDtls *Max35Text  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CtgyDtls,omitempty" json:"CtgyDtls,omitempty"`
-	DbtrSts  *Max35Text  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 DbtrSts,omitempty" json:"DbtrSts,omitempty"`
-	CertId   *Max35Text  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 CertId,omitempty" json:"CertId,omitempty"`
-	FrmsCd   *Max35Text  `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 FrmsCd,omitempty" json:"FrmsCd,omitempty"`
-	Prd      *TaxPeriod2 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prd,omitempty" json:"Prd,omitempty"`
-	TaxAmt   *TaxAmount2 `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 TaxAmt,omitempty" json:"TaxAmt,omitempty"`
-	AddtlInf *Max140Text `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 AddtlInf,omitempty" json:"AddtlInf,omitempty"`
+	Tp       *Max35Text  `xml:"Tp,omitempty" json:"Tp,omitempty"`
+	Ctgy     *Max35Text  `xml:"Ctgy,omitempty" json:"Ctgy,omitempty"`
+	CtgyDtls *Max35Text  `xml:"CtgyDtls,omitempty" json:"CtgyDtls,omitempty"`
+	DbtrSts  *Max35Text  `xml:"DbtrSts,omitempty" json:"DbtrSts,omitempty"`
+	CertId   *Max35Text  `xml:"CertId,omitempty" json:"CertId,omitempty"`
+	FrmsCd   *Max35Text  `xml:"FrmsCd,omitempty" json:"FrmsCd,omitempty"`
+	Prd      *TaxPeriod2 `xml:"Prd,omitempty" json:"Prd,omitempty"`
+	TaxAmt   *TaxAmount2 `xml:"TaxAmt,omitempty" json:"TaxAmt,omitempty"`
+	AddtlInf *Max140Text `xml:"AddtlInf,omitempty" json:"AddtlInf,omitempty"`
 }
 
 type TaxRecordDetails2 struct {
-	Prd *TaxPeriod2                        `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Prd,omitempty" json:"Prd,omitempty"`
-	Amt *ActiveOrHistoricCurrencyAndAmount `xml:"urn:iso:std:iso:20022:tech:xsd:pacs.008.001.09 Amt" json:"Amt"`
+	Prd *TaxPeriod2                        `xml:"Prd,omitempty" json:"Prd,omitempty"`
+	Amt *ActiveOrHistoricCurrencyAndAmount `xml:"Amt" json:"Amt"`
 }
 
 // TaxRecordPeriod1Code May be one of MM01, MM02, MM03, MM04, MM05, MM06, MM07, MM08, MM09, MM10, MM11, MM12, QTR1, QTR2, QTR3, QTR4, HLF1, HLF2
 type TaxRecordPeriod1Code string
 
+func (v TaxRecordPeriod1Code) Validate() error {
+	return checkEnumeration(string(v), "MM01", "MM02", "MM03", "MM04", "MM05", "MM06", "MM07", "MM08", "MM09", "MM10", "MM11", "MM12", "QTR1", "QTR2", "QTR3", "QTR4", "HLF1", "HLF2")
+}
+
+// UnicodeChartsCode Character set of the message, e.g. UTF-8
+type UnicodeChartsCode string
+
 // UUIDv4Identifier Must match the pattern [a-f0-9]{8}-[a-f0-9]{4}-4[a-f0-9]{3}-[89ab][a-f0-9]{3}-[a-f0-9]{12}
 type UUIDv4Identifier string
 
+func (v UUIDv4Identifier) Validate() error {
+	return checkPattern(string(v), `[a-f0-9]{8}-[a-f0-9]{4}-4[a-f0-9]{3}-[89ab][a-f0-9]{3}-[a-f0-9]{12}`)
+}
+
 type xsdBase64Binary []byte
 
 func (b *xsdBase64Binary) UnmarshalText(text []byte) (err error) {
@@ -926,15 +1420,22 @@ func (t xsdDate) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
 	m, err := t.MarshalText()
 	return xml.Attr{Name: name, Value: string(m)}, err
 }
+
+// noTimeZone marks values decoded without a time zone, so they are written back without one
+var noTimeZone = time.FixedZone("", 0)
+
 func _unmarshalTime(text []byte, t *time.Time, format string) (err error) {
 	s := string(bytes.TrimSpace(text))
-	*t, err = time.Parse(format, s)
+	*t, err = time.ParseInLocation(format, s, noTimeZone)
 	if _, ok := err.(*time.ParseError); ok {
 		*t, err = time.Parse(format+"Z07:00", s)
 	}
 	return err
 }
 func _marshalTime(t time.Time, format string) ([]byte, error) {
+	if t.Location() == noTimeZone {
+		return []byte(t.Format(format)), nil
+	}
 	return []byte(t.Format(format + "Z07:00")), nil
 }
 
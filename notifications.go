@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// notifyDebtor also notifies debited accounts of accepted transfers, credited accounts are always notified
+// notificationSink receives every notification when set, notifyAttempts is how often delivery is tried
+var (
+	notifyDebtor     bool
+	notificationSink NotificationSink
+	notifyAttempts   = 3
+)
+
+// Notification is a camt.054 notifying an account of the transfer it was generated for
+// ID is the stored camt.054, MessageID the pacs.008, both are set by Save
+type Notification struct {
+	XMLName     xml.Name   `xml:"Notification" json:"-"`
+	ID          string     `xml:"Id" json:"Id"`
+	MessageID   string     `xml:"MessageId" json:"MessageId"`
+	Account     string     `xml:"Account" json:"Account"`
+	CdtDbtInd   string     `xml:"CdtDbtInd" json:"CdtDbtInd"`
+	CreatedAt   time.Time  `xml:"CreatedAt" json:"CreatedAt"`
+	DeliveredAt *time.Time `xml:"DeliveredAt,omitempty" json:"DeliveredAt,omitempty"`
+	Attempts    int        `xml:"Attempts" json:"Attempts"` // deliveries tried
+	LastError   string     `xml:"LastError,omitempty" json:"LastError,omitempty"`
+	BusMsg      *BusMsg    `xml:"BusMsg,omitempty" json:"BusMsg,omitempty"` // set when listed
+}
+
+// NotificationFilter selects stored notifications, empty fields match everything
+// Notifications are listed oldest first, After is the ID of the last notification already read
+type NotificationFilter struct {
+	Account     string
+	MessageID   string
+	Undelivered bool
+	After       string
+	Limit       int
+}
+
+// NotificationPage is a page of GET /notifications results
+// NextCursor is empty on the last page
+type NotificationPage struct {
+	XMLName       xml.Name       `xml:"Notifications" json:"-"`
+	Notifications []Notification `xml:"Notification" json:"Notifications"`
+	NextCursor    string         `xml:"NextCursor,omitempty" json:"NextCursor,omitempty"`
+}
+
+// NotificationSink receives notifications as they are generated, e.g. a core banking system
+type NotificationSink interface {
+	Deliver(msg *StoredMessage) error
+}
+
+// HTTPSink posts each notification payload to URL, any other status than 2xx is a failed delivery
+type HTTPSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s *HTTPSink) Deliver(msg *StoredMessage) error {
+	contentType := "application/json"
+	if msg.Format == formatXML {
+		contentType = "application/xml"
+	}
+	resp, err := s.Client.Post(s.URL, contentType, bytes.NewReader(msg.Payload))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s answered %s", s.URL, resp.Status)
+	}
+	return nil
+}
+
+// DeliveryLog records each delivery attempt of a notification, deliveryErr is nil when it succeeded
+type DeliveryLog interface {
+	RecordDelivery(id string, at time.Time, deliveryErr error) error
+}
+
+var deliveries DeliveryLog
+
+// Build camt.054 notifications of msg, an accepted pacs.008: one for each credited account and, with notifyDebtor,
+// one for each debited account, holding an entry for every transaction of msg on that account
+// Transactions without an account identifier are not notified
+func transferNotifications(msg *StoredMessage) ([]*StoredMessage, error) {
+	transfer := msg.Message.Document.FIToFICstmrCdtTrf
+	if transfer == nil {
+		return nil, nil
+	}
+	var groupDate *ISODate
+	if transfer.GrpHdr != nil {
+		groupDate = transfer.GrpHdr.IntrBkSttlmDt
+	}
+
+	type side struct {
+		account   string
+		indicator CreditDebitCode
+	}
+	var sides []side
+	accounts := make(map[side]StatementAccount)
+	entries := make(map[side][]StatementEntry)
+	for _, tx := range transfer.CdtTrfTxInf {
+		if tx == nil {
+			continue
+		}
+		// entries are booked on the settlement date, or on the day of receipt when it is missing
+		date := msg.ReceivedAt.UTC().Truncate(24 * time.Hour)
+		if tx.IntrBkSttlmDt != nil {
+			date = time.Time(*tx.IntrBkSttlmDt)
+		} else if groupDate != nil {
+			date = time.Time(*groupDate)
+		}
+
+		notified := []side{{accountId(tx.CdtrAcct), "CRDT"}}
+		if notifyDebtor {
+			notified = append(notified, side{accountId(tx.DbtrAcct), "DBIT"})
+		}
+		for _, s := range notified {
+			if s.account == "" {
+				continue
+			}
+			if _, ok := accounts[s]; !ok {
+				sides = append(sides, s)
+				accounts[s] = transactionAccount(tx, s.indicator)
+			}
+			entries[s] = append(entries[s], StatementEntry{MsgId: msg.MsgId, Tx: tx, CdtDbtInd: s.indicator, BookingDate: date})
+		}
+	}
+
+	var notifications []*StoredMessage
+	for _, s := range sides {
+		notification := NewNotification(accounts[s], entries[s])
+		stored := &StoredMessage{
+			BizMsgIdr:    string(notification.AppHdr.BizMsgIdr),
+			MsgDefIdr:    string(notification.AppHdr.MsgDefIdr),
+			MsgId:        string(notification.AppHdr.BizMsgIdr),
+			ClientIP:     msg.ClientIP,
+			Format:       msg.Format,
+			Status:       statusAccepted,
+			ReceivedAt:   msg.ReceivedAt,
+			Message:      &notification,
+			Notification: &Notification{Account: s.account, CdtDbtInd: string(s.indicator), CreatedAt: msg.ReceivedAt},
+		}
+
+		var err error
+		if stored.Payload, err = marshalBusMsg(notification, stored.Format); err == nil {
+			stored.Document, err = marshalIndent(notification.Document, stored.Format)
+		}
+		if err == nil {
+			stored.Digest, err = documentDigest(notification.Document)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("notification of account %s: %w", s.account, err)
+		}
+		notifications = append(notifications, stored)
+	}
+	return notifications, nil
+}
+
+// Deliver saved notifications to sink, the notificationSink of the request saving them, trying each up to notifyAttempts times
+// Notifications that could not be delivered stay listed as undelivered, for the sink to pull them
+func deliverNotifications(sink NotificationSink, notifications []*StoredMessage) {
+	if sink == nil {
+		return
+	}
+	for _, msg := range notifications {
+		for attempt := 1; attempt <= notifyAttempts; attempt++ {
+			err := sink.Deliver(msg)
+			if recordErr := deliveries.RecordDelivery(msg.ID, time.Now(), err); recordErr != nil {
+				log.Printf("Error recording delivery of notification %s: %s", msg.ID, recordErr.Error())
+			}
+			if err == nil {
+				log.Printf("Notification %s delivered", msg.ID)
+				break
+			}
+			log.Printf("Error delivering notification %s, attempt %d of %d: %s", msg.ID, attempt, notifyAttempts, err.Error())
+			if attempt < notifyAttempts {
+				time.Sleep(time.Duration(attempt) * time.Second)
+			}
+		}
+	}
+}
+
+// List stored notifications with their camt.054, oldest first
+// Query parameters: account, message (the notified pacs.008, by any identifier getIso accepts),
+// undelivered=true, limit and cursor, the NextCursor of the previous page
+func listNotifications(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ipReq := getIP(r)
+	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new Notification Search Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
+
+	var response Response
+
+	format := acceptFormat(r)
+	query := r.URL.Query()
+
+	filter := NotificationFilter{
+		Account:     query.Get("account"),
+		Undelivered: query.Get("undelivered") == "true",
+		After:       query.Get("cursor"),
+		Limit:       defaultPageSize,
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			response.Status = statusRejected
+			response.Message = fmt.Sprintf("Invalid search parameter: limit must be a number from 1 to %d", maxPageSize)
+			log.Print(response.Message)
+			responseFormatter(w, format, response, http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	var page NotificationPage
+	var err error
+	if id := query.Get("message"); id != "" {
+		var msg *StoredMessage
+		if msg, err = finder.Find(id); errors.Is(err, ErrMessageNotFound) {
+			response.Status = statusRejected
+			response.Message = fmt.Sprintf("Message %s not found", id)
+			log.Print(response.Message)
+			responseFormatter(w, format, response, http.StatusNotFound)
+			return
+		}
+		if err == nil {
+			filter.MessageID = msg.ID
+		}
+	}
+	if err == nil {
+		page, err = finder.FindNotifications(filter)
+	}
+	for i := 0; err == nil && i < len(page.Notifications); i++ {
+		var msg *StoredMessage
+		if msg, err = finder.Find(page.Notifications[i].ID); err == nil {
+			var notification Iso20022
+			if notification, _, _, err = decodeMessage(msg.Payload, msg.Format); err == nil {
+				setHeadNamespace(&notification.BusMsg)
+				page.Notifications[i].BusMsg = &notification.BusMsg
+			}
+		}
+	}
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = "Error reading notifications"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
+	}
+	responseFormatter(w, format, page, http.StatusOK)
+}
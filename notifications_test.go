@@ -0,0 +1,179 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// The credited account is notified of an accepted transfer, the debited one only with notifyDebtor
+func TestTransferNotifications(t *testing.T) {
+	tests := []struct {
+		notifyDebtor bool
+		want         []string // account and side of each notification
+	}{
+		{false, []string{"987654321 CRDT"}},
+		{true, []string{"987654321 CRDT", "123456789 DBIT"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("notifyDebtor %v", tt.notifyDebtor), func(t *testing.T) {
+			saved := notifyDebtor
+			notifyDebtor = tt.notifyDebtor
+			t.Cleanup(func() { notifyDebtor = saved })
+
+			request := decodeSample(t, "pacs008.json", formatJSON)
+			msg := &StoredMessage{MsgId: "20210301INDOIDJA01012345678", Format: formatJSON, ReceivedAt: time.Now(), Message: &request.BusMsg}
+			notifications, err := transferNotifications(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(notifications) != len(tt.want) {
+				t.Fatalf("got %d notifications, want %v", len(notifications), tt.want)
+			}
+			for i, stored := range notifications {
+				if got := stored.Notification.Account + " " + stored.Notification.CdtDbtInd; got != tt.want[i] {
+					t.Errorf("notification %d is on %s, want %s", i, got, tt.want[i])
+				}
+				if stored.MsgDefIdr != "camt.054.001.08" || len(stored.Payload) == 0 {
+					t.Errorf("notification %d is a %s of %d bytes, want a camt.054.001.08 payload", i, stored.MsgDefIdr, len(stored.Payload))
+				}
+				ntfctn := stored.Message.Document.BkToCstmrDbtCdtNtfctn.Ntfctn
+				if len(ntfctn) != 1 || len(ntfctn[0].Ntry) != 1 || ntfctn[0].Ntry[0].Amt.Value.String() != "1234.56" {
+					t.Errorf("notification %d holds %+v, want one entry of 1234.56", i, ntfctn)
+				}
+			}
+		})
+	}
+}
+
+// Notifications are listed oldest first, a page at a time, by account and by notified transfer
+func TestListNotifications(t *testing.T) {
+	handler := newTestServer(t)
+	var transfers []string
+	for i := 0; i < 3; i++ {
+		body := samplePayload(t, func(msg *BusMsg) {
+			msg.Document.FIToFICstmrCdtTrf.GrpHdr.MsgId = optionalText(fmt.Sprintf("M%d", i))
+			tx := msg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0]
+			tx.PmtId.EndToEndId, tx.PmtId.TxId = optionalText(fmt.Sprintf("E%d", i)), optionalText(fmt.Sprintf("T%d", i))
+			if i == 2 {
+				id := Max34Text("555555555")
+				tx.CdtrAcct.Id.Othr.Id = &id
+			}
+		})
+		code, response := postIso(t, handler, "/iso20022", body, nil)
+		if code != http.StatusOK {
+			t.Fatalf("pacs.008 %d gives %d %+v", i, code, response)
+		}
+		transfers = append(transfers, response.MessageId)
+	}
+
+	var page NotificationPage
+	if code := getJSON(t, handler, "/notifications?limit=2", &page); code != http.StatusOK || len(page.Notifications) != 2 || page.NextCursor == "" {
+		t.Fatalf("got %d %+v, want 2 notifications and a next page", code, page)
+	}
+	first := page.Notifications
+	if first[0].MessageID != transfers[0] || first[1].MessageID != transfers[1] || first[0].BusMsg == nil {
+		t.Errorf("got notifications %+v, want those of the first two transfers with their camt.054", first)
+	}
+	cursor := page.NextCursor
+	page = NotificationPage{}
+	if getJSON(t, handler, "/notifications?limit=2&cursor="+cursor, &page); len(page.Notifications) != 1 || page.NextCursor != "" {
+		t.Fatalf("got %+v, want the last notification", page)
+	}
+	if page.Notifications[0].MessageID != transfers[2] || page.Notifications[0].Account != "555555555" {
+		t.Errorf("got %+v, want the notification of the last transfer", page.Notifications[0])
+	}
+
+	tests := []struct {
+		query    string
+		wantCode int
+		want     int
+	}{
+		{"account=987654321", http.StatusOK, 2},
+		{"account=123456789", http.StatusOK, 0},
+		{"message=" + transfers[1], http.StatusOK, 1},
+		{"message=M2", http.StatusOK, 1}, // by MsgId, like getIso
+		{"undelivered=true", http.StatusOK, 3},
+		{"message=UNKNOWN", http.StatusNotFound, 0},
+		{"limit=0", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		var page NotificationPage
+		if code := getJSON(t, handler, "/notifications?"+tt.query, &page); code != tt.wantCode || len(page.Notifications) != tt.want {
+			t.Errorf("%s gives %d and %d notifications, want %d and %d", tt.query, code, len(page.Notifications), tt.wantCode, tt.want)
+		}
+	}
+}
+
+// sinkFunc delivers notifications by calling itself
+type sinkFunc func(msg *StoredMessage) error
+
+func (f sinkFunc) Deliver(msg *StoredMessage) error {
+	return f(msg)
+}
+
+// Each attempt is recorded, notifications the sink refuses every time stay undelivered
+func TestDeliverNotifications(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      int // attempts failing before the sink accepts
+		wantAttempts  int
+		wantDelivered bool
+	}{
+		{"delivered", 0, 1, true},
+		{"delivered on retry", 1, 2, true},
+		{"refused", 2, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestServer(t)
+			if code, response := postIso(t, handler, "/iso20022", samplePayload(t, func(*BusMsg) {}), nil); code != http.StatusOK {
+				t.Fatalf("pacs.008 gives %d %+v", code, response)
+			}
+			var page NotificationPage
+			getJSON(t, handler, "/notifications", &page)
+			if len(page.Notifications) != 1 {
+				t.Fatalf("got %+v, want one notification", page)
+			}
+			msg, err := finder.Find(page.Notifications[0].ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			saved := notifyAttempts
+			t.Cleanup(func() { notifyAttempts = saved })
+			var delivered []string
+			calls := 0
+			sink := sinkFunc(func(msg *StoredMessage) error {
+				calls++
+				if calls <= tt.failures {
+					return errors.New("sink unavailable")
+				}
+				delivered = append(delivered, msg.ID)
+				return nil
+			})
+			notifyAttempts = 2
+			deliverNotifications(sink, []*StoredMessage{msg})
+
+			if tt.wantDelivered != (len(delivered) == 1 && delivered[0] == msg.ID) {
+				t.Errorf("sink got %v, want delivered %v", delivered, tt.wantDelivered)
+			}
+			page = NotificationPage{}
+			getJSON(t, handler, "/notifications", &page)
+			notification := page.Notifications[0]
+			if notification.Attempts != tt.wantAttempts || (notification.DeliveredAt != nil) != tt.wantDelivered {
+				t.Errorf("got %d attempts, delivered at %v, want %d attempts and delivered %v",
+					notification.Attempts, notification.DeliveredAt, tt.wantAttempts, tt.wantDelivered)
+			}
+			if !tt.wantDelivered && notification.LastError != "sink unavailable" {
+				t.Errorf("got last error %q, want the sink error", notification.LastError)
+			}
+			page = NotificationPage{}
+			if getJSON(t, handler, "/notifications?undelivered=true", &page); (len(page.Notifications) == 0) != tt.wantDelivered {
+				t.Errorf("got %d undelivered notifications, want delivered %v", len(page.Notifications), tt.wantDelivered)
+			}
+		})
+	}
+}
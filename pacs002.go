@@ -0,0 +1,233 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FIToFI payment status report (pacs.002.001.10)
+// Only elements this service fills in are modelled: charges, effective settlement date and
+// original transaction reference are left out of PaymentTransaction110
+
+const pacs002Namespace = "urn:iso:std:iso:20022:tech:xsd:pacs.002.001.10"
+
+type FIToFIPaymentStatusReportV10 struct {
+	GrpHdr            *GroupHeader91           `xml:"GrpHdr" json:"GrpHdr"`
+	OrgnlGrpInfAndSts []*OriginalGroupHeader17 `xml:"OrgnlGrpInfAndSts,omitempty" json:"OrgnlGrpInfAndSts,omitempty"`
+	TxInfAndSts       []*PaymentTransaction110 `xml:"TxInfAndSts,omitempty" json:"TxInfAndSts,omitempty"`
+	SplmtryData       []*SupplementaryData1    `xml:"SplmtryData,omitempty" json:"SplmtryData,omitempty"`
+}
+
+type GroupHeader91 struct {
+	MsgId    *Max35Text                                    `xml:"MsgId" json:"MsgId"`
+	CreDtTm  *ISODateTime                                  `xml:"CreDtTm" json:"CreDtTm"`
+	InstgAgt *BranchAndFinancialInstitutionIdentification6 `xml:"InstgAgt,omitempty" json:"InstgAgt,omitempty"`
+	InstdAgt *BranchAndFinancialInstitutionIdentification6 `xml:"InstdAgt,omitempty" json:"InstdAgt,omitempty"`
+}
+
+type OriginalGroupHeader17 struct {
+	OrgnlMsgId    *Max35Text                        `xml:"OrgnlMsgId" json:"OrgnlMsgId"`
+	OrgnlMsgNmId  *Max35Text                        `xml:"OrgnlMsgNmId" json:"OrgnlMsgNmId"`
+	OrgnlCreDtTm  *ISODateTime                      `xml:"OrgnlCreDtTm,omitempty" json:"OrgnlCreDtTm,omitempty"`
+	OrgnlNbOfTxs  *Max15NumericText                 `xml:"OrgnlNbOfTxs,omitempty" json:"OrgnlNbOfTxs,omitempty"`
+	OrgnlCtrlSum  *DecimalNumber                    `xml:"OrgnlCtrlSum,omitempty" json:"OrgnlCtrlSum,omitempty"`
+	GrpSts        *ExternalPaymentGroupStatus1Code  `xml:"GrpSts,omitempty" json:"GrpSts,omitempty"`
+	StsRsnInf     []*StatusReasonInformation12      `xml:"StsRsnInf,omitempty" json:"StsRsnInf,omitempty"`
+	NbOfTxsPerSts []*NumberOfTransactionsPerStatus5 `xml:"NbOfTxsPerSts,omitempty" json:"NbOfTxsPerSts,omitempty"`
+}
+
+type OriginalGroupInformation29 struct {
+	OrgnlMsgId   *Max35Text   `xml:"OrgnlMsgId" json:"OrgnlMsgId"`
+	OrgnlMsgNmId *Max35Text   `xml:"OrgnlMsgNmId" json:"OrgnlMsgNmId"`
+	OrgnlCreDtTm *ISODateTime `xml:"OrgnlCreDtTm,omitempty" json:"OrgnlCreDtTm,omitempty"`
+}
+
+type NumberOfTransactionsPerStatus5 struct {
+	DtldNbOfTxs *Max15NumericText                      `xml:"DtldNbOfTxs" json:"DtldNbOfTxs"`
+	DtldSts     *ExternalPaymentTransactionStatus1Code `xml:"DtldSts" json:"DtldSts"`
+	DtldCtrlSum *DecimalNumber                         `xml:"DtldCtrlSum,omitempty" json:"DtldCtrlSum,omitempty"`
+}
+
+type PaymentTransaction110 struct {
+	StsId           *Max35Text                                    `xml:"StsId,omitempty" json:"StsId,omitempty"`
+	OrgnlGrpInf     *OriginalGroupInformation29                   `xml:"OrgnlGrpInf,omitempty" json:"OrgnlGrpInf,omitempty"`
+	OrgnlInstrId    *Max35Text                                    `xml:"OrgnlInstrId,omitempty" json:"OrgnlInstrId,omitempty"`
+	OrgnlEndToEndId *Max35Text                                    `xml:"OrgnlEndToEndId,omitempty" json:"OrgnlEndToEndId,omitempty"`
+	OrgnlTxId       *Max35Text                                    `xml:"OrgnlTxId,omitempty" json:"OrgnlTxId,omitempty"`
+	OrgnlUETR       *UUIDv4Identifier                             `xml:"OrgnlUETR,omitempty" json:"OrgnlUETR,omitempty"`
+	TxSts           *ExternalPaymentTransactionStatus1Code        `xml:"TxSts,omitempty" json:"TxSts,omitempty"`
+	StsRsnInf       []*StatusReasonInformation12                  `xml:"StsRsnInf,omitempty" json:"StsRsnInf,omitempty"`
+	AccptncDtTm     *ISODateTime                                  `xml:"AccptncDtTm,omitempty" json:"AccptncDtTm,omitempty"`
+	AcctSvcrRef     *Max35Text                                    `xml:"AcctSvcrRef,omitempty" json:"AcctSvcrRef,omitempty"`
+	ClrSysRef       *Max35Text                                    `xml:"ClrSysRef,omitempty" json:"ClrSysRef,omitempty"`
+	InstgAgt        *BranchAndFinancialInstitutionIdentification6 `xml:"InstgAgt,omitempty" json:"InstgAgt,omitempty"`
+	InstdAgt        *BranchAndFinancialInstitutionIdentification6 `xml:"InstdAgt,omitempty" json:"InstdAgt,omitempty"`
+}
+
+type StatusReasonInformation12 struct {
+	Orgtr    *PartyIdentification135 `xml:"Orgtr,omitempty" json:"Orgtr,omitempty"`
+	Rsn      *StatusReason6Choice    `xml:"Rsn,omitempty" json:"Rsn,omitempty"`
+	AddtlInf []*Max105Text           `xml:"AddtlInf,omitempty" json:"AddtlInf,omitempty"`
+}
+
+type StatusReason6Choice struct {
+	Cd    *ExternalStatusReason1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                 `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
+}
+
+// ExternalPaymentGroupStatus1Code May be no more than 4 items long
+type ExternalPaymentGroupStatus1Code string
+
+func (v ExternalPaymentGroupStatus1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
+// ExternalPaymentTransactionStatus1Code May be no more than 4 items long
+type ExternalPaymentTransactionStatus1Code string
+
+func (v ExternalPaymentTransactionStatus1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
+// ExternalStatusReason1Code May be no more than 4 items long
+type ExternalStatusReason1Code string
+
+func (v ExternalStatusReason1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
+// Max105Text May be no more than 105 items long
+type Max105Text string
+
+func (v Max105Text) Validate() error {
+	return checkLength(string(v), 1, 105)
+}
+
+// Return a new message identifier: UTC time followed by random hex digits, 30 characters long
+func newMessageId() string {
+	random := make([]byte, 8)
+	rand.Read(random)
+	return time.Now().UTC().Format("20060102T150405") + hex.EncodeToString(random)[:15]
+}
+
+// Build header of a reply to the message with header original, sent back the way it came
+func replyHeader(original AppHdr, msgDefIdr string, id Max35Text, now ISODateTime) AppHdr {
+	return AppHdr{
+		Fr:        original.To,
+		To:        original.Fr,
+		BizMsgIdr: id,
+		MsgDefIdr: Max35Text(msgDefIdr),
+		CreDt:     now,
+		Rltd: []*BusinessApplicationHeader5{{
+			Fr:        original.Fr,
+			To:        original.To,
+			BizMsgIdr: &original.BizMsgIdr,
+			MsgDefIdr: &original.MsgDefIdr,
+			CreDt:     &original.CreDt,
+		}},
+	}
+}
+
+// Build pacs.002 status report answering msg, with status ACTC or RJCT
+// Error issues located in a transaction become status reasons of that transaction, other errors are group reasons
+func NewStatusReport(msg BusMsg, status string, issues ValidationErrors) BusMsg {
+	now := ISODateTime(time.Now().UTC().Truncate(time.Millisecond))
+	id := Max35Text(newMessageId())
+
+	original := msg.AppHdr
+	report := BusMsg{AppHdr: replyHeader(original, "pacs.002.001.10", id, now)}
+
+	groupStatus := ExternalPaymentGroupStatus1Code(status)
+	txStatus := ExternalPaymentTransactionStatus1Code(status)
+	header := &GroupHeader91{MsgId: &id, CreDtTm: &now}
+	group := &OriginalGroupHeader17{GrpSts: &groupStatus}
+
+	// original message name is taken from the header, as the document may be too broken to tell
+	msgNmId := original.MsgDefIdr
+	group.OrgnlMsgNmId = &msgNmId
+	group.OrgnlMsgId = &original.BizMsgIdr
+
+	grpHdr, transactions := summarize(msg.Document)
+	if grpHdr != nil {
+		if grpHdr.MsgId != nil {
+			group.OrgnlMsgId = grpHdr.MsgId
+		}
+		group.OrgnlCreDtTm = grpHdr.CreDtTm
+		group.OrgnlNbOfTxs = grpHdr.NbOfTxs
+		group.OrgnlCtrlSum = grpHdr.CtrlSum
+		// the report goes back the way the message came
+		header.InstgAgt = grpHdr.InstdAgt
+		header.InstdAgt = grpHdr.InstgAgt
+	}
+
+	txReasons := make(map[int][]*StatusReasonInformation12)
+	for _, issue := range issues {
+		if issue.Severity != severityError {
+			continue
+		}
+		reason := statusReason(issue)
+		if i := transactionIndex(transactions, issue.Location); i >= 0 {
+			txReasons[i] = append(txReasons[i], reason)
+			continue
+		}
+		group.StsRsnInf = append(group.StsRsnInf, reason)
+	}
+
+	count := Max15NumericText(strconv.Itoa(len(transactions)))
+	group.NbOfTxsPerSts = []*NumberOfTransactionsPerStatus5{{DtldNbOfTxs: &count, DtldSts: &txStatus}}
+
+	var txInfAndSts []*PaymentTransaction110
+	for i, tx := range transactions {
+		txReport := &PaymentTransaction110{
+			TxSts:           &txStatus,
+			StsRsnInf:       txReasons[i],
+			OrgnlInstrId:    tx.InstrId,
+			OrgnlEndToEndId: tx.EndToEndId,
+			OrgnlTxId:       tx.TxId,
+			OrgnlUETR:       tx.UETR,
+		}
+		if status == statusAccepted {
+			txReport.AccptncDtTm = &now
+		}
+		txInfAndSts = append(txInfAndSts, txReport)
+	}
+
+	report.Document.FIToFIPmtStsRpt = &FIToFIPaymentStatusReportV10{
+		GrpHdr:            header,
+		OrgnlGrpInfAndSts: []*OriginalGroupHeader17{group},
+		TxInfAndSts:       txInfAndSts,
+	}
+	return report
+}
+
+// Return index in transactions of the transaction holding location, -1 when there is none
+func transactionIndex(transactions []transactionSummary, location string) int {
+	for i, tx := range transactions {
+		if location == tx.Location || strings.HasPrefix(location, tx.Location+"/") {
+			return i
+		}
+	}
+	return -1
+}
+
+// Build status reason from issue, its location and message are split over AddtlInf lines of 105 characters
+func statusReason(issue ValidationError) *StatusReasonInformation12 {
+	code := ExternalStatusReason1Code(issue.Code)
+	reason := &StatusReasonInformation12{Rsn: &StatusReason6Choice{Cd: &code}}
+
+	text := []rune(fmt.Sprintf("%s: %s", issue.Location, issue.Message))
+	for len(text) > 0 {
+		n := len(text)
+		if n > 105 {
+			n = 105
+		}
+		line := Max105Text(text[:n])
+		reason.AddtlInf = append(reason.AddtlInf, &line)
+		text = text[n:]
+	}
+	return reason
+}
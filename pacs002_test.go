@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Issues located in a transaction are reasons of that transaction, other errors are group reasons, warnings are left out
+func TestNewStatusReport(t *testing.T) {
+	request := decodeSample(t, "pacs008.json", formatJSON)
+	issues := ValidationErrors{
+		ruleError("/Document/FIToFICstmrCdtTrf/CdtTrfTxInf[0]/IntrBkSttlmAmt", reasonInvalidCurrency, "%s", strings.Repeat("x", 150)),
+		ruleError("/Document/FIToFICstmrCdtTrf/GrpHdr/NbOfTxs", reasonInvalidNumberOfTxs, "NbOfTxs is wrong"),
+		{Location: "/Document/FIToFICstmrCdtTrf/GrpHdr/MsgId", Code: reasonUnknownOriginal, Severity: severityWarning, Message: "warning"},
+	}
+
+	report := NewStatusReport(request.BusMsg, statusRejected, issues)
+	if partyId(report.AppHdr.Fr) != "CENAIDJA" || partyId(report.AppHdr.To) != "INDOIDJA" || report.AppHdr.MsgDefIdr != "pacs.002.001.10" {
+		t.Errorf("got header %+v, want pacs.002.001.10 from CENAIDJA to INDOIDJA", report.AppHdr)
+	}
+
+	rpt := report.Document.FIToFIPmtStsRpt
+	if len(rpt.OrgnlGrpInfAndSts) != 1 {
+		t.Fatalf("got %d group statuses, want 1", len(rpt.OrgnlGrpInfAndSts))
+	}
+	group := rpt.OrgnlGrpInfAndSts[0]
+	if textValue(group.OrgnlMsgId) != "20210301INDOIDJA01012345678" || string(*group.GrpSts) != statusRejected {
+		t.Errorf("got group %s %s, want 20210301INDOIDJA01012345678 %s", textValue(group.OrgnlMsgId), *group.GrpSts, statusRejected)
+	}
+	if got := strings.Join(reasonCodes(group.StsRsnInf), ","); got != reasonInvalidNumberOfTxs {
+		t.Errorf("got group reasons %s, want %s", got, reasonInvalidNumberOfTxs)
+	}
+
+	if len(rpt.TxInfAndSts) != 1 {
+		t.Fatalf("got %d transaction statuses, want 1", len(rpt.TxInfAndSts))
+	}
+	tx := rpt.TxInfAndSts[0]
+	if string(*tx.TxSts) != statusRejected || textValue(tx.OrgnlEndToEndId) != "20210301INDOIDJA010ORB12345678" || tx.AccptncDtTm != nil {
+		t.Errorf("got transaction %+v, want it rejected without acceptance time", tx)
+	}
+	if got := strings.Join(reasonCodes(tx.StsRsnInf), ","); got != reasonInvalidCurrency {
+		t.Fatalf("got transaction reasons %s, want %s", got, reasonInvalidCurrency)
+	}
+	for _, line := range tx.StsRsnInf[0].AddtlInf {
+		if len([]rune(*line)) > 105 {
+			t.Errorf("AddtlInf line of %d characters, want at most 105", len([]rune(*line)))
+		}
+	}
+	if len(tx.StsRsnInf[0].AddtlInf) != 2 {
+		t.Errorf("got %d AddtlInf lines, want the reason split over 2", len(tx.StsRsnInf[0].AddtlInf))
+	}
+}
+
+// Parsed messages are answered with a pacs.002 when asked for, in place of Response
+func TestIngestStatusReportReply(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(msg *BusMsg)
+		wantCode int
+		wantSts  string
+	}{
+		{"accepted", func(*BusMsg) {}, http.StatusOK, statusAccepted},
+		{
+			name: "rejected",
+			change: func(msg *BusMsg) {
+				ccy := ActiveCurrencyCode("XXQ")
+				msg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0].IntrBkSttlmAmt.Ccy = &ccy
+			},
+			wantCode: http.StatusBadRequest,
+			wantSts:  statusRejected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestServer(t)
+			code, report := postMessage(t, handler, "/iso20022?reply=pacs.002", samplePayload(t, tt.change))
+			if code != tt.wantCode {
+				t.Fatalf("got %d, want %d", code, tt.wantCode)
+			}
+			rpt := report.Document.FIToFIPmtStsRpt
+			if rpt == nil || len(rpt.TxInfAndSts) != 1 {
+				t.Fatalf("got %+v, want a pacs.002 on one transaction", report.Document)
+			}
+			tx := rpt.TxInfAndSts[0]
+			if string(*rpt.OrgnlGrpInfAndSts[0].GrpSts) != tt.wantSts || string(*tx.TxSts) != tt.wantSts {
+				t.Errorf("got statuses %s and %s, want %s", *rpt.OrgnlGrpInfAndSts[0].GrpSts, *tx.TxSts, tt.wantSts)
+			}
+			if (tx.AccptncDtTm != nil) != (tt.wantSts == statusAccepted) {
+				t.Errorf("got AccptncDtTm %v for status %s", tx.AccptncDtTm, tt.wantSts)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Payment return (pacs.004.001.09)
+// Return chain and original transaction reference are not modelled, they are accepted and ignored
+
+const pacs004Namespace = "urn:iso:std:iso:20022:tech:xsd:pacs.004.001.09"
+
+type PaymentReturnV09 struct {
+	GrpHdr      *GroupHeader90           `xml:"GrpHdr" json:"GrpHdr"`
+	OrgnlGrpInf *OriginalGroupHeader18   `xml:"OrgnlGrpInf,omitempty" json:"OrgnlGrpInf,omitempty"`
+	TxInf       []*PaymentTransaction112 `xml:"TxInf,omitempty" json:"TxInf,omitempty"`
+	SplmtryData []*SupplementaryData1    `xml:"SplmtryData,omitempty" json:"SplmtryData,omitempty"`
+}
+
+type GroupHeader90 struct {
+	MsgId                 *Max35Text                                    `xml:"MsgId" json:"MsgId"`
+	CreDtTm               *ISODateTime                                  `xml:"CreDtTm" json:"CreDtTm"`
+	BtchBookg             bool                                          `xml:"BtchBookg,omitempty" json:"BtchBookg,omitempty"`
+	NbOfTxs               *Max15NumericText                             `xml:"NbOfTxs" json:"NbOfTxs"`
+	CtrlSum               *DecimalNumber                                `xml:"CtrlSum,omitempty" json:"CtrlSum,omitempty"`
+	GrpRtr                bool                                          `xml:"GrpRtr,omitempty" json:"GrpRtr,omitempty"`
+	TtlRtrdIntrBkSttlmAmt *ActiveCurrencyAndAmount                      `xml:"TtlRtrdIntrBkSttlmAmt,omitempty" json:"TtlRtrdIntrBkSttlmAmt,omitempty"`
+	IntrBkSttlmDt         *ISODate                                      `xml:"IntrBkSttlmDt,omitempty" json:"IntrBkSttlmDt,omitempty"`
+	SttlmInf              *SettlementInstruction7                       `xml:"SttlmInf" json:"SttlmInf"`
+	PmtTpInf              *PaymentTypeInformation28                     `xml:"PmtTpInf,omitempty" json:"PmtTpInf,omitempty"`
+	InstgAgt              *BranchAndFinancialInstitutionIdentification6 `xml:"InstgAgt,omitempty" json:"InstgAgt,omitempty"`
+	InstdAgt              *BranchAndFinancialInstitutionIdentification6 `xml:"InstdAgt,omitempty" json:"InstdAgt,omitempty"`
+}
+
+type OriginalGroupHeader18 struct {
+	OrgnlMsgId   *Max35Text              `xml:"OrgnlMsgId" json:"OrgnlMsgId"`
+	OrgnlMsgNmId *Max35Text              `xml:"OrgnlMsgNmId" json:"OrgnlMsgNmId"`
+	OrgnlCreDtTm *ISODateTime            `xml:"OrgnlCreDtTm,omitempty" json:"OrgnlCreDtTm,omitempty"`
+	RtrRsnInf    []*PaymentReturnReason6 `xml:"RtrRsnInf,omitempty" json:"RtrRsnInf,omitempty"`
+}
+
+type PaymentTransaction112 struct {
+	RtrId               *Max35Text                                    `xml:"RtrId,omitempty" json:"RtrId,omitempty"`
+	OrgnlGrpInf         *OriginalGroupInformation29                   `xml:"OrgnlGrpInf,omitempty" json:"OrgnlGrpInf,omitempty"`
+	OrgnlInstrId        *Max35Text                                    `xml:"OrgnlInstrId,omitempty" json:"OrgnlInstrId,omitempty"`
+	OrgnlEndToEndId     *Max35Text                                    `xml:"OrgnlEndToEndId,omitempty" json:"OrgnlEndToEndId,omitempty"`
+	OrgnlTxId           *Max35Text                                    `xml:"OrgnlTxId,omitempty" json:"OrgnlTxId,omitempty"`
+	OrgnlUETR           *UUIDv4Identifier                             `xml:"OrgnlUETR,omitempty" json:"OrgnlUETR,omitempty"`
+	OrgnlClrSysRef      *Max35Text                                    `xml:"OrgnlClrSysRef,omitempty" json:"OrgnlClrSysRef,omitempty"`
+	OrgnlIntrBkSttlmAmt *ActiveOrHistoricCurrencyAndAmount            `xml:"OrgnlIntrBkSttlmAmt,omitempty" json:"OrgnlIntrBkSttlmAmt,omitempty"`
+	OrgnlIntrBkSttlmDt  *ISODate                                      `xml:"OrgnlIntrBkSttlmDt,omitempty" json:"OrgnlIntrBkSttlmDt,omitempty"`
+	PmtTpInf            *PaymentTypeInformation28                     `xml:"PmtTpInf,omitempty" json:"PmtTpInf,omitempty"`
+	RtrdIntrBkSttlmAmt  *ActiveCurrencyAndAmount                      `xml:"RtrdIntrBkSttlmAmt" json:"RtrdIntrBkSttlmAmt"`
+	IntrBkSttlmDt       *ISODate                                      `xml:"IntrBkSttlmDt,omitempty" json:"IntrBkSttlmDt,omitempty"`
+	SttlmPrty           *Priority3Code                                `xml:"SttlmPrty,omitempty" json:"SttlmPrty,omitempty"`
+	SttlmTmIndctn       *SettlementDateTimeIndication1                `xml:"SttlmTmIndctn,omitempty" json:"SttlmTmIndctn,omitempty"`
+	SttlmTmReq          *SettlementTimeRequest2                       `xml:"SttlmTmReq,omitempty" json:"SttlmTmReq,omitempty"`
+	RtrdInstdAmt        *ActiveOrHistoricCurrencyAndAmount            `xml:"RtrdInstdAmt,omitempty" json:"RtrdInstdAmt,omitempty"`
+	XchgRate            *BaseOneRate                                  `xml:"XchgRate,omitempty" json:"XchgRate,omitempty"`
+	CompstnAmt          *ActiveOrHistoricCurrencyAndAmount            `xml:"CompstnAmt,omitempty" json:"CompstnAmt,omitempty"`
+	ChrgBr              *ChargeBearerType1Code                        `xml:"ChrgBr,omitempty" json:"ChrgBr,omitempty"`
+	ChrgsInf            []*Charges7                                   `xml:"ChrgsInf,omitempty" json:"ChrgsInf,omitempty"`
+	ClrSysRef           *Max35Text                                    `xml:"ClrSysRef,omitempty" json:"ClrSysRef,omitempty"`
+	InstgAgt            *BranchAndFinancialInstitutionIdentification6 `xml:"InstgAgt,omitempty" json:"InstgAgt,omitempty"`
+	InstdAgt            *BranchAndFinancialInstitutionIdentification6 `xml:"InstdAgt,omitempty" json:"InstdAgt,omitempty"`
+	RtrRsnInf           []*PaymentReturnReason6                       `xml:"RtrRsnInf,omitempty" json:"RtrRsnInf,omitempty"`
+	SplmtryData         []*SupplementaryData1                         `xml:"SplmtryData,omitempty" json:"SplmtryData,omitempty"`
+}
+
+type PaymentReturnReason6 struct {
+	Orgtr    *PartyIdentification135 `xml:"Orgtr,omitempty" json:"Orgtr,omitempty"`
+	Rsn      *ReturnReason5Choice    `xml:"Rsn,omitempty" json:"Rsn,omitempty"`
+	AddtlInf []*Max105Text           `xml:"AddtlInf,omitempty" json:"AddtlInf,omitempty"`
+}
+
+type ReturnReason5Choice struct {
+	Cd    *ExternalReturnReason1Code `xml:"Cd,omitempty" json:"Cd,omitempty"`
+	Prtry *Max35Text                 `xml:"Prtry,omitempty" json:"Prtry,omitempty"`
+}
+
+// ExternalReturnReason1Code May be no more than 4 items long
+type ExternalReturnReason1Code string
+
+func (v ExternalReturnReason1Code) Validate() error {
+	return checkLength(string(v), 1, 4)
+}
+
+// Link returned transactions of msg to their originals, rejecting returns that do not match them
+func handleReturn(finder MessageFinder, msg *StoredMessage) (ValidationErrors, error) {
+	originals, returned, issues, err := validateReturn(finder, msg.Message.Document.PmtRtr)
+	msg.Originals = originals
+	for _, original := range returned {
+		msg.Transitions = append(msg.Transitions, Transition{
+			Transaction: TransactionRef{MessageID: original.MessageID, Seq: original.Seq},
+			From:        original.State,
+			To:          stateReturned,
+			Event:       msg.MsgDefIdr,
+			At:          msg.ReceivedAt,
+		})
+	}
+	return issues, err
+}
+
+// Check every returned transaction refers to a stored pacs.008 transaction by OrgnlMsgId and OrgnlEndToEndId,
+// and that returned amounts, together with earlier returns, do not exceed the original amount
+// Originals must be in a state they can be returned from, they are Returned once their whole amount is
+// The original transaction of each returned transaction is returned by index, with the originals returned in full and the issues found
+func validateReturn(finder MessageFinder, ret *PaymentReturnV09) (map[int]TransactionRef, []*PaymentRecord, ValidationErrors, error) {
+	var errs ValidationErrors
+	var returnedInFull []*PaymentRecord
+	originals := make(map[int]TransactionRef)
+	if ret == nil {
+		return originals, returnedInFull, errs, nil
+	}
+
+	const root = "/Document/PmtRtr"
+	var groupMsgId *Max35Text
+	if ret.OrgnlGrpInf != nil {
+		groupMsgId = ret.OrgnlGrpInf.OrgnlMsgId
+		if name := ret.OrgnlGrpInf.OrgnlMsgNmId; name != nil && !isMessage(string(*name), "pacs.008") {
+			errs = append(errs, ruleError(root+"/OrgnlGrpInf/OrgnlMsgNmId", reasonIncorrectContent,
+				"returned message must be a pacs.008, not %s", *name))
+		}
+	}
+
+	// amounts returned so far by original transaction, including earlier transactions of this return
+	returned := make(map[TransactionRef]Decimal)
+
+	for i, tx := range ret.TxInf {
+		if tx == nil {
+			continue
+		}
+		location := fmt.Sprintf("%s/TxInf[%d]", root, i)
+
+		msgId := groupMsgId
+		if tx.OrgnlGrpInf != nil {
+			msgId = tx.OrgnlGrpInf.OrgnlMsgId
+			if name := tx.OrgnlGrpInf.OrgnlMsgNmId; name != nil && !isMessage(string(*name), "pacs.008") {
+				errs = append(errs, ruleError(location+"/OrgnlGrpInf/OrgnlMsgNmId", reasonIncorrectContent,
+					"returned message must be a pacs.008, not %s", *name))
+			}
+		}
+		if msgId == nil {
+			errs = append(errs, ruleError(location+"/OrgnlGrpInf", reasonMissingElement,
+				"OrgnlMsgId is required in OrgnlGrpInf of the transaction or of the group"))
+			continue
+		}
+		if tx.OrgnlEndToEndId == nil {
+			errs = append(errs, ruleError(location+"/OrgnlEndToEndId", reasonMissingElement,
+				"OrgnlEndToEndId is required to find the returned transaction"))
+			continue
+		}
+
+		original, err := finder.FindTransaction(string(*msgId), string(*tx.OrgnlEndToEndId), "")
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if original == nil {
+			errs = append(errs, ruleError(location+"/OrgnlEndToEndId", reasonUnknownOriginal,
+				"no pacs.008 transaction %s in message %s was received", *tx.OrgnlEndToEndId, *msgId))
+			continue
+		}
+		ref := TransactionRef{MessageID: original.MessageID, Seq: original.Seq}
+		originals[i] = ref
+		if !canTransition(original.State, stateReturned) {
+			errs = append(errs, transitionError(location+"/OrgnlEndToEndId", original, stateReturned))
+			continue
+		}
+
+		if amount := tx.OrgnlIntrBkSttlmAmt; amount != nil && amount.Value.Cmp(original.Amount) != 0 {
+			errs = append(errs, ruleError(location+"/OrgnlIntrBkSttlmAmt", reasonWrongAmount,
+				"OrgnlIntrBkSttlmAmt %s differs from original amount %s", amount.Value, original.Amount))
+		}
+
+		amount := tx.RtrdIntrBkSttlmAmt
+		if amount == nil {
+			continue
+		}
+		if amount.Ccy != nil && string(*amount.Ccy) != original.Currency {
+			errs = append(errs, ruleError(location+"/RtrdIntrBkSttlmAmt/Ccy", reasonIncorrectCurrency,
+				"returned currency %s differs from original currency %s", *amount.Ccy, original.Currency))
+			continue
+		}
+
+		total, ok := returned[ref]
+		if !ok {
+			earlier, err := finder.FindReturns(ref)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			for _, payment := range earlier {
+				total = total.Add(payment.Amount)
+			}
+		}
+		total = total.Add(amount.Value)
+		returned[ref] = total
+
+		switch total.Cmp(original.Amount) {
+		case 1:
+			errs = append(errs, ruleError(location+"/RtrdIntrBkSttlmAmt", reasonWrongAmount,
+				"returned amount %s exceeds original amount %s %s, %s returned in total", amount.Value, original.Currency, original.Amount, total))
+		case 0:
+			returnedInFull = append(returnedInFull, original)
+		}
+	}
+	return originals, returnedInFull, errs, nil
+}
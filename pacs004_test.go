@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// Returns are checked against the amount and currency of the original transaction, which is Returned once its
+// whole amount is
+func TestIngestReturn(t *testing.T) {
+	amount := func(value string, ccy string) func(t *testing.T, tx *PaymentTransaction112) {
+		return func(t *testing.T, tx *PaymentTransaction112) {
+			code := ActiveCurrencyCode(ccy)
+			tx.RtrdIntrBkSttlmAmt = &ActiveCurrencyAndAmount{Value: mustDecimal(t, value), Ccy: &code}
+		}
+	}
+	tests := []struct {
+		name      string
+		returns   []func(t *testing.T, tx *PaymentTransaction112) // all but the last are accepted
+		wantCode  int
+		wantIssue ValidationError // Location and Code of the expected issue, none when empty
+		wantState string
+	}{
+		{
+			name:      "returned in full",
+			returns:   []func(t *testing.T, tx *PaymentTransaction112){amount("1234.56", "IDR")},
+			wantCode:  http.StatusOK,
+			wantState: stateReturned,
+		},
+		{
+			name:      "returned in part",
+			returns:   []func(t *testing.T, tx *PaymentTransaction112){amount("1000.00", "IDR")},
+			wantCode:  http.StatusOK,
+			wantState: stateAccepted,
+		},
+		{
+			name:      "returned in two parts",
+			returns:   []func(t *testing.T, tx *PaymentTransaction112){amount("1000.00", "IDR"), amount("234.56", "IDR")},
+			wantCode:  http.StatusOK,
+			wantState: stateReturned,
+		},
+		{
+			name:      "parts exceeding the original",
+			returns:   []func(t *testing.T, tx *PaymentTransaction112){amount("1000.00", "IDR"), amount("234.57", "IDR")},
+			wantCode:  http.StatusBadRequest,
+			wantIssue: ValidationError{Location: "/Document/PmtRtr/TxInf[0]/RtrdIntrBkSttlmAmt", Code: reasonWrongAmount},
+			wantState: stateAccepted,
+		},
+		{
+			name:      "exceeding the original",
+			returns:   []func(t *testing.T, tx *PaymentTransaction112){amount("2000.00", "IDR")},
+			wantCode:  http.StatusBadRequest,
+			wantIssue: ValidationError{Location: "/Document/PmtRtr/TxInf[0]/RtrdIntrBkSttlmAmt", Code: reasonWrongAmount},
+			wantState: stateAccepted,
+		},
+		{
+			name:      "another currency",
+			returns:   []func(t *testing.T, tx *PaymentTransaction112){amount("1234.56", "USD")},
+			wantCode:  http.StatusBadRequest,
+			wantIssue: ValidationError{Location: "/Document/PmtRtr/TxInf[0]/RtrdIntrBkSttlmAmt/Ccy", Code: reasonIncorrectCurrency},
+			wantState: stateAccepted,
+		},
+		{
+			name: "wrong original amount",
+			returns: []func(t *testing.T, tx *PaymentTransaction112){func(t *testing.T, tx *PaymentTransaction112) {
+				tx.OrgnlIntrBkSttlmAmt.Value = mustDecimal(t, "1234.50")
+			}},
+			wantCode:  http.StatusBadRequest,
+			wantIssue: ValidationError{Location: "/Document/PmtRtr/TxInf[0]/OrgnlIntrBkSttlmAmt", Code: reasonWrongAmount},
+			wantState: stateAccepted,
+		},
+		{
+			name: "unknown original",
+			returns: []func(t *testing.T, tx *PaymentTransaction112){func(t *testing.T, tx *PaymentTransaction112) {
+				tx.OrgnlEndToEndId = optionalText("UNKNOWN")
+			}},
+			wantCode:  http.StatusBadRequest,
+			wantIssue: ValidationError{Location: "/Document/PmtRtr/TxInf[0]/OrgnlEndToEndId", Code: reasonUnknownOriginal},
+			wantState: stateAccepted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestServer(t)
+			_, transfer := postIso(t, handler, "/iso20022", samplePayload(t, func(*BusMsg) {}), nil)
+
+			for i, change := range tt.returns {
+				body := messagePayload(t, "pacs004.json", func(msg *BusMsg) {
+					msg.AppHdr.BizMsgIdr = Max35Text(fmt.Sprintf("20210302CENAIDJA010RTR0000000%d", i))
+					msg.Document.PmtRtr.GrpHdr.MsgId = optionalText(fmt.Sprintf("20210302CENAIDJA0100000000%d", i))
+					tx := msg.Document.PmtRtr.TxInf[0]
+					tx.RtrId = optionalText(fmt.Sprintf("RTR000%d", i))
+					change(t, tx)
+				})
+				code, response := postIso(t, handler, "/iso20022/return", body, nil)
+				if i < len(tt.returns)-1 {
+					if code != http.StatusOK {
+						t.Fatalf("return %d gives %d %+v", i, code, response)
+					}
+					continue
+				}
+				if code != tt.wantCode {
+					t.Fatalf("got %d %+v, want %d", code, response, tt.wantCode)
+				}
+				if tt.wantIssue.Code != "" && !hasIssue(response.Issues, tt.wantIssue) {
+					t.Errorf("got issues %+v, want %s at %s", response.Issues, tt.wantIssue.Code, tt.wantIssue.Location)
+				}
+			}
+
+			var lifecycle MessageLifecycle
+			getJSON(t, handler, "/iso20022/"+transfer.MessageId+"/lifecycle", &lifecycle)
+			if len(lifecycle.Transactions) != 1 || lifecycle.Transactions[0].State != tt.wantState {
+				t.Errorf("got lifecycle %+v, want the transaction %s", lifecycle.Transactions, tt.wantState)
+			}
+		})
+	}
+}
+
+// Return whether issues hold an issue at the Location and with the Code of want
+func hasIssue(issues ValidationErrors, want ValidationError) bool {
+	for _, issue := range issues {
+		if issue.Location == want.Location && issue.Code == want.Code {
+			return true
+		}
+	}
+	return false
+}
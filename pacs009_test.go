@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+// pacs.009 messages are dispatched by their header to FICdtTrf, with their debtor and creditor banks as agents
+func TestIngestFICreditTransfer(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		change    func(msg *BusMsg)
+		wantCode  int
+		wantIssue ValidationError // Location and Code of the expected issue, none when empty
+	}{
+		{name: "accepted", path: "/iso20022", wantCode: http.StatusOK},
+		{
+			name: "wrong number of transactions",
+			path: "/iso20022",
+			change: func(msg *BusMsg) {
+				nbOfTxs := Max15NumericText("2")
+				msg.Document.FICdtTrf.GrpHdr.NbOfTxs = &nbOfTxs
+			},
+			wantCode:  http.StatusBadRequest,
+			wantIssue: ValidationError{Location: "/Document/FICdtTrf/GrpHdr/NbOfTxs", Code: reasonInvalidNumberOfTxs},
+		},
+		{
+			name: "header naming another message",
+			path: "/iso20022",
+			change: func(msg *BusMsg) {
+				msg.AppHdr.MsgDefIdr = "pacs.008.001.09"
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{name: "sent as a return", path: "/iso20022/return", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestServer(t)
+			code, response := postIso(t, handler, tt.path, messagePayload(t, "pacs009.json", tt.change), nil)
+			if code != tt.wantCode {
+				t.Fatalf("got %d %+v, want %d", code, response, tt.wantCode)
+			}
+			if tt.wantIssue.Code != "" && !hasIssue(response.Issues, tt.wantIssue) {
+				t.Errorf("got issues %+v, want %s at %s", response.Issues, tt.wantIssue.Code, tt.wantIssue.Location)
+			}
+			if code != http.StatusOK {
+				return
+			}
+
+			var page PaymentPage
+			getJSON(t, handler, "/iso20022?dbtrAgt=INDOIDJA&cdtrAgt=CENAIDJA", &page)
+			if len(page.Payments) != 1 {
+				t.Fatalf("got payments %+v, want the pacs.009 transaction", page.Payments)
+			}
+			payment := page.Payments[0]
+			if payment.MessageID != response.MessageId || payment.TxId != "20210301INDOIDJA01087654321" || payment.Amount.String() != "1000000.00" {
+				t.Errorf("got payment %+v", payment)
+			}
+			if payment.State != "" {
+				t.Errorf("got state %s, want none as pacs.009 transactions have no lifecycle", payment.State)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FIToFI payment status request (pacs.028.001.03)
+// Original transaction reference is not modelled, transactions are looked up by their identifiers
+
+const pacs028Namespace = "urn:iso:std:iso:20022:tech:xsd:pacs.028.001.03"
+
+type FIToFIPaymentStatusRequestV03 struct {
+	GrpHdr      *GroupHeader91                `xml:"GrpHdr" json:"GrpHdr"`
+	OrgnlGrpInf []*OriginalGroupInformation27 `xml:"OrgnlGrpInf,omitempty" json:"OrgnlGrpInf,omitempty"`
+	TxInf       []*PaymentTransaction113      `xml:"TxInf,omitempty" json:"TxInf,omitempty"`
+	SplmtryData []*SupplementaryData1         `xml:"SplmtryData,omitempty" json:"SplmtryData,omitempty"`
+}
+
+type OriginalGroupInformation27 struct {
+	OrgnlMsgId   *Max35Text        `xml:"OrgnlMsgId" json:"OrgnlMsgId"`
+	OrgnlMsgNmId *Max35Text        `xml:"OrgnlMsgNmId" json:"OrgnlMsgNmId"`
+	OrgnlCreDtTm *ISODateTime      `xml:"OrgnlCreDtTm,omitempty" json:"OrgnlCreDtTm,omitempty"`
+	OrgnlNbOfTxs *Max15NumericText `xml:"OrgnlNbOfTxs,omitempty" json:"OrgnlNbOfTxs,omitempty"`
+	OrgnlCtrlSum *DecimalNumber    `xml:"OrgnlCtrlSum,omitempty" json:"OrgnlCtrlSum,omitempty"`
+}
+
+type PaymentTransaction113 struct {
+	StsReqId        *Max35Text                                    `xml:"StsReqId,omitempty" json:"StsReqId,omitempty"`
+	OrgnlGrpInf     *OriginalGroupInformation29                   `xml:"OrgnlGrpInf,omitempty" json:"OrgnlGrpInf,omitempty"`
+	OrgnlInstrId    *Max35Text                                    `xml:"OrgnlInstrId,omitempty" json:"OrgnlInstrId,omitempty"`
+	OrgnlEndToEndId *Max35Text                                    `xml:"OrgnlEndToEndId,omitempty" json:"OrgnlEndToEndId,omitempty"`
+	OrgnlTxId       *Max35Text                                    `xml:"OrgnlTxId,omitempty" json:"OrgnlTxId,omitempty"`
+	OrgnlUETR       *UUIDv4Identifier                             `xml:"OrgnlUETR,omitempty" json:"OrgnlUETR,omitempty"`
+	AccptncDtTm     *ISODateTime                                  `xml:"AccptncDtTm,omitempty" json:"AccptncDtTm,omitempty"`
+	ClrSysRef       *Max35Text                                    `xml:"ClrSysRef,omitempty" json:"ClrSysRef,omitempty"`
+	InstgAgt        *BranchAndFinancialInstitutionIdentification6 `xml:"InstgAgt,omitempty" json:"InstgAgt,omitempty"`
+	InstdAgt        *BranchAndFinancialInstitutionIdentification6 `xml:"InstdAgt,omitempty" json:"InstdAgt,omitempty"`
+	SplmtryData     []*SupplementaryData1                         `xml:"SplmtryData,omitempty" json:"SplmtryData,omitempty"`
+}
+
+// Return payment status of a stored transaction: its settlement or rejection when its lifecycle has come that far,
+// otherwise the status it was received with
+func lifecycleStatus(record *PaymentRecord) string {
+	switch record.State {
+	case stateSettled, stateReturned:
+		return "ACSC"
+	case stateRejected, stateCancelled:
+		return statusRejected
+	}
+	return record.Status
+}
+
+// Build pacs.002 answering request, a pacs.028 status request, with the status of every message and transaction it asks for
+// Messages and transactions never received are rejected with reason NOOR
+func AnswerStatusRequest(finder MessageFinder, request BusMsg) (BusMsg, error) {
+	now := ISODateTime(time.Now().UTC().Truncate(time.Millisecond))
+	id := Max35Text(newMessageId())
+
+	answer := BusMsg{AppHdr: replyHeader(request.AppHdr, "pacs.002.001.10", id, now)}
+	report := &FIToFIPaymentStatusReportV10{GrpHdr: &GroupHeader91{MsgId: &id, CreDtTm: &now}}
+	answer.Document.FIToFIPmtStsRpt = report
+
+	req := request.Document.FIToFIPmtStsReq
+	if req == nil {
+		return answer, nil
+	}
+	const root = "/Document/FIToFIPmtStsReq"
+	if req.GrpHdr != nil {
+		report.GrpHdr.InstgAgt, report.GrpHdr.InstdAgt = req.GrpHdr.InstdAgt, req.GrpHdr.InstgAgt
+	}
+
+	// transactions may leave out the original group when the request names a single one
+	var requestMsgId *Max35Text
+	if len(req.OrgnlGrpInf) == 1 && req.OrgnlGrpInf[0] != nil {
+		requestMsgId = req.OrgnlGrpInf[0].OrgnlMsgId
+	}
+
+	for i, group := range req.OrgnlGrpInf {
+		if group == nil {
+			continue
+		}
+		location := fmt.Sprintf("%s/OrgnlGrpInf[%d]", root, i)
+		groupStatus := &OriginalGroupHeader17{OrgnlMsgId: group.OrgnlMsgId, OrgnlMsgNmId: group.OrgnlMsgNmId, OrgnlCreDtTm: group.OrgnlCreDtTm}
+
+		msg, err := finder.FindMessage(textValue(group.OrgnlMsgId), "pacs.008")
+		if err != nil && !errors.Is(err, ErrMessageNotFound) {
+			return BusMsg{}, err
+		}
+		status := ExternalPaymentGroupStatus1Code(statusRejected)
+		if msg != nil {
+			status = ExternalPaymentGroupStatus1Code(msg.Status)
+		} else {
+			groupStatus.StsRsnInf = append(groupStatus.StsRsnInf, statusReason(ruleError(location+"/OrgnlMsgId", reasonUnknownOriginal,
+				"no pacs.008 message %s was received", textValue(group.OrgnlMsgId))))
+		}
+		groupStatus.GrpSts = &status
+		report.OrgnlGrpInfAndSts = append(report.OrgnlGrpInfAndSts, groupStatus)
+	}
+
+	for i, tx := range req.TxInf {
+		if tx == nil {
+			continue
+		}
+		location := fmt.Sprintf("%s/TxInf[%d]", root, i)
+		txStatus := &PaymentTransaction110{
+			StsId:           tx.StsReqId,
+			OrgnlGrpInf:     tx.OrgnlGrpInf,
+			OrgnlInstrId:    tx.OrgnlInstrId,
+			OrgnlEndToEndId: tx.OrgnlEndToEndId,
+			OrgnlTxId:       tx.OrgnlTxId,
+			OrgnlUETR:       tx.OrgnlUETR,
+		}
+		report.TxInfAndSts = append(report.TxInfAndSts, txStatus)
+
+		msgId := requestMsgId
+		if tx.OrgnlGrpInf != nil {
+			msgId = tx.OrgnlGrpInf.OrgnlMsgId
+		}
+		uetr := ""
+		if tx.OrgnlUETR != nil {
+			uetr = string(*tx.OrgnlUETR)
+		}
+		rejected := ExternalPaymentTransactionStatus1Code(statusRejected)
+		if uetr == "" && (msgId == nil || tx.OrgnlEndToEndId == nil) {
+			txStatus.TxSts = &rejected
+			txStatus.StsRsnInf = append(txStatus.StsRsnInf, statusReason(ruleError(location, reasonMissingElement,
+				"OrgnlUETR, or OrgnlMsgId and OrgnlEndToEndId, are required to find the transaction")))
+			continue
+		}
+
+		record, err := finder.FindTransaction(textValue(msgId), textValue(tx.OrgnlEndToEndId), uetr)
+		if err != nil {
+			return BusMsg{}, err
+		}
+		if record == nil {
+			txStatus.TxSts = &rejected
+			txStatus.StsRsnInf = append(txStatus.StsRsnInf, statusReason(ruleError(location, reasonUnknownOriginal,
+				"no pacs.008 transaction %s was received", transactionReference(textValue(msgId), textValue(tx.OrgnlEndToEndId), uetr))))
+			continue
+		}
+
+		status := ExternalPaymentTransactionStatus1Code(lifecycleStatus(record))
+		txStatus.TxSts = &status
+		txStatus.OrgnlEndToEndId = optionalText(record.EndToEndId)
+		txStatus.OrgnlTxId = optionalText(record.TxId)
+		if record.UETR != "" {
+			recordUETR := UUIDv4Identifier(record.UETR)
+			txStatus.OrgnlUETR = &recordUETR
+		}
+		if record.Status == statusAccepted {
+			accepted := ISODateTime(record.ReceivedAt.UTC().Truncate(time.Millisecond))
+			txStatus.AccptncDtTm = &accepted
+		}
+		if original, err := finder.Find(record.MessageID); err == nil {
+			msgNmId := Max35Text(original.MsgDefIdr)
+			txStatus.OrgnlGrpInf = &OriginalGroupInformation29{OrgnlMsgId: optionalText(record.MsgId), OrgnlMsgNmId: &msgNmId}
+		} else if !errors.Is(err, ErrMessageNotFound) {
+			return BusMsg{}, err
+		}
+	}
+	return answer, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// A status request is answered with the lifecycle status of the messages and transactions it asks for
+func TestAnswerStatusRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		transfer   bool // send the pacs.008 sample first
+		settle     bool // then report it settled with the pacs.002 sample
+		change     func(msg *BusMsg)
+		wantGroup  string
+		wantTx     string
+		wantReason string // reason of the transaction status, empty when it has none
+	}{
+		{name: "never received", wantGroup: statusRejected, wantTx: statusRejected, wantReason: reasonUnknownOriginal},
+		{name: "accepted", transfer: true, wantGroup: statusAccepted, wantTx: statusAccepted},
+		{name: "settled", transfer: true, settle: true, wantGroup: statusAccepted, wantTx: "ACSC"},
+		{
+			name:     "transaction without identifiers",
+			transfer: true,
+			change: func(msg *BusMsg) {
+				msg.Document.FIToFIPmtStsReq.TxInf[0].OrgnlEndToEndId = nil
+			},
+			wantGroup:  statusAccepted,
+			wantTx:     statusRejected,
+			wantReason: reasonMissingElement,
+		},
+		{
+			name:     "transaction by UETR of another group",
+			transfer: true,
+			change: func(msg *BusMsg) {
+				uetr := UUIDv4Identifier("8a562c67-ca16-48ba-b074-65581be6f001")
+				msg.Document.FIToFIPmtStsReq.TxInf[0].OrgnlUETR = &uetr
+				msg.Document.FIToFIPmtStsReq.TxInf[0].OrgnlEndToEndId = nil
+			},
+			wantGroup:  statusAccepted,
+			wantTx:     statusRejected,
+			wantReason: reasonUnknownOriginal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestServer(t)
+			if tt.transfer {
+				if code, response := postIso(t, handler, "/iso20022", samplePayload(t, func(*BusMsg) {}), nil); code != http.StatusOK {
+					t.Fatalf("pacs.008 gives %d %+v", code, response)
+				}
+			}
+			if tt.settle {
+				if code, response := postIso(t, handler, "/iso20022", messagePayload(t, "pacs002.json", nil), nil); code != http.StatusOK {
+					t.Fatalf("pacs.002 gives %d %+v", code, response)
+				}
+			}
+
+			// the request is not saved, asking twice gives the same answer
+			body := messagePayload(t, "pacs028.json", tt.change)
+			for i := 0; i < 2; i++ {
+				code, answer := postMessage(t, handler, "/iso20022/status", body)
+				if code != http.StatusOK {
+					t.Fatalf("got %d, want %d", code, http.StatusOK)
+				}
+				if string(answer.AppHdr.MsgDefIdr) != "pacs.002.001.10" || partyId(answer.AppHdr.To) != "CENAIDJA" {
+					t.Errorf("got answer %s to %+v, want pacs.002.001.10 to CENAIDJA", answer.AppHdr.MsgDefIdr, answer.AppHdr.To)
+				}
+				report := answer.Document.FIToFIPmtStsRpt
+				if len(report.OrgnlGrpInfAndSts) != 1 || string(*report.OrgnlGrpInfAndSts[0].GrpSts) != tt.wantGroup {
+					t.Errorf("got group statuses %+v, want %s", report.OrgnlGrpInfAndSts, tt.wantGroup)
+				}
+				if len(report.TxInfAndSts) != 1 {
+					t.Fatalf("got %d transaction statuses, want 1", len(report.TxInfAndSts))
+				}
+				tx := report.TxInfAndSts[0]
+				if string(*tx.TxSts) != tt.wantTx || strings.Join(reasonCodes(tx.StsRsnInf), ",") != tt.wantReason {
+					t.Errorf("got transaction %s %v, want %s %s", *tx.TxSts, reasonCodes(tx.StsRsnInf), tt.wantTx, tt.wantReason)
+				}
+				if tt.wantReason == "" && (textValue(tx.OrgnlTxId) != "20210301INDOIDJA01012345678" || tx.OrgnlGrpInf == nil) {
+					t.Errorf("got transaction %+v, want it completed from the stored pacs.008", tx)
+				}
+			}
+		})
+	}
+}
+
+// Reason codes of status reasons, in order
+func reasonCodes(reasons []*StatusReasonInformation12) []string {
+	var codes []string
+	for _, reason := range reasons {
+		if reason != nil && reason.Rsn != nil && reason.Rsn.Cd != nil {
+			codes = append(codes, string(*reason.Rsn.Cd))
+		}
+	}
+	return codes
+}
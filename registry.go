@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MessageType describes how messages of one definition are decoded, validated and handled
+// Messages are decoded into a canonical model, each version of a message converts to and from it
+// Decode defaults to decodeIso and Encode to the canonical model, Validate and Handle are optional
+type MessageType struct {
+	MsgDefIdr string // e.g. pacs.008.001.09
+	Root      string // element Document holds the message in, e.g. FIToFICstmrCdtTrf
+
+	// Decode reads a payload of this definition into the canonical model
+	// Elements dropped or defaulted on the way are reported as warnings
+	Decode func(body []byte, format string) (Iso20022, ValidationErrors, error)
+
+	// Encode converts a canonical message into a BusMsg of this definition, ready to marshal
+	Encode func(msg BusMsg) (interface{}, ValidationErrors, error)
+
+	// Validate checks business rules, after XSD facets
+	Validate func(msg BusMsg) ValidationErrors
+
+	// Handle checks msg against stored messages and completes it before it is saved
+	// It runs under ingestMu, after duplicate checks, and is given the finder of stored messages
+	Handle func(finder MessageFinder, msg *StoredMessage) (ValidationErrors, error)
+
+	// Lifecycle is set when transactions of the message keep a lifecycle state from receipt
+	// Such messages are saved when rejected as well, so the rejection is part of their history
+	Lifecycle bool
+
+	// DecodeOnly is set for messages this service generates but does not accept, e.g. statements
+	// Such messages are decoded and converted, as when stored ones are read back, but never ingested
+	DecodeOnly bool
+
+	// Endpoint is set for messages only accepted by their own endpoint, e.g. status requests that are answered, not saved
+	// POST /iso20022 rejects them
+	Endpoint string
+}
+
+// Registered message types by MsgDefIdr
+var messageTypes = make(map[string]*MessageType)
+
+// Register t so messages naming its MsgDefIdr are accepted
+// Registering a definition twice is a programming error and panics
+func RegisterMessageType(t MessageType) {
+	if _, ok := messageTypes[t.MsgDefIdr]; ok {
+		panic(fmt.Sprintf("message type %s registered twice", t.MsgDefIdr))
+	}
+	if t.Decode == nil {
+		t.Decode = func(body []byte, format string) (Iso20022, ValidationErrors, error) {
+			request, err := decodeIso(body, format)
+			return request, nil, err
+		}
+	}
+	if t.Encode == nil {
+		msgDefIdr := Max35Text(t.MsgDefIdr)
+		t.Encode = func(msg BusMsg) (interface{}, ValidationErrors, error) {
+			msg.AppHdr.MsgDefIdr = msgDefIdr
+			return msg, nil, nil
+		}
+	}
+	messageTypes[t.MsgDefIdr] = &t
+}
+
+// Return registered message type of msgDefIdr, nil when there is none
+func lookupMessageType(msgDefIdr string) *MessageType {
+	return messageTypes[msgDefIdr]
+}
+
+// Return registered message definitions accepted by the service, sorted
+func messageDefinitions() []string {
+	definitions := make([]string, 0, len(messageTypes))
+	for msgDefIdr, t := range messageTypes {
+		if !t.DecodeOnly {
+			definitions = append(definitions, msgDefIdr)
+		}
+	}
+	sort.Strings(definitions)
+	return definitions
+}
+
+// Decode only the AppHdr of a payload, and the Document namespace of an XML payload,
+// to find out which message type it carries
+func decodeHeader(body []byte, format string) (AppHdr, string, error) {
+	var msg struct {
+		AppHdr   AppHdr `xml:"AppHdr" json:"AppHdr"`
+		Document struct {
+			XMLName xml.Name
+		} `xml:"Document" json:"-"`
+	}
+	err := unmarshalPayload(body, format, &msg)
+	return msg.AppHdr, msg.Document.XMLName.Space, err
+}
+
+// Return message type named by MsgDefIdr, or else by the Document namespace, nil when neither is registered
+func detectMessageType(header AppHdr, namespace string) *MessageType {
+	if t := lookupMessageType(string(header.MsgDefIdr)); t != nil {
+		return t
+	}
+	if namespace != "" {
+		return lookupMessageType(namespaceMessage(namespace))
+	}
+	return nil
+}
+
+// Decode payload into the canonical model of the message type it carries
+func decodeMessage(body []byte, format string) (Iso20022, *MessageType, ValidationErrors, error) {
+	header, namespace, err := decodeHeader(body, format)
+	if err != nil {
+		return Iso20022{}, nil, nil, err
+	}
+	t := detectMessageType(header, namespace)
+	if t == nil {
+		return Iso20022{}, nil, nil, fmt.Errorf("MsgDefIdr %q is not one of %s", header.MsgDefIdr, strings.Join(messageDefinitions(), ", "))
+	}
+	request, report, err := t.Decode(body, format)
+	return request, t, report, err
+}
+
+func init() {
+	RegisterMessageType(MessageType{
+		MsgDefIdr: canonicalPacs008,
+		Root:      "FIToFICstmrCdtTrf",
+		Validate:  validateCustomerCreditTransfer,
+		Handle:    handleCreditTransfer,
+		Lifecycle: true,
+	})
+	RegisterMessageType(MessageType{
+		MsgDefIdr: "pacs.008.001.08",
+		Root:      "FIToFICstmrCdtTrf",
+		Decode:    decodePacs008V08,
+		Encode:    encodePacs008V08,
+		Validate:  validateCustomerCreditTransfer,
+		Handle:    handleCreditTransfer,
+		Lifecycle: true,
+	})
+	RegisterMessageType(MessageType{
+		MsgDefIdr: "pacs.008.001.10",
+		Root:      "FIToFICstmrCdtTrf",
+		Decode:    decodePacs008V10,
+		Encode:    encodePacs008V10,
+		Validate:  validateCustomerCreditTransfer,
+		Handle:    handleCreditTransfer,
+		Lifecycle: true,
+	})
+	RegisterMessageType(MessageType{
+		MsgDefIdr: "pacs.009.001.09",
+		Root:      "FICdtTrf",
+		Validate:  validateFICreditTransfer,
+	})
+	RegisterMessageType(MessageType{
+		MsgDefIdr: "pacs.004.001.09",
+		Root:      "PmtRtr",
+		Handle:    handleReturn,
+	})
+	RegisterMessageType(MessageType{
+		MsgDefIdr: "pacs.002.001.10",
+		Root:      "FIToFIPmtStsRpt",
+		Handle:    handleStatusReport,
+	})
+	RegisterMessageType(MessageType{
+		MsgDefIdr: "camt.056.001.08",
+		Root:      "FIToFIPmtCxlReq",
+		Validate:  validateCancellation,
+		Handle:    handleCancellation,
+	})
+	RegisterMessageType(MessageType{
+		MsgDefIdr:  "camt.029.001.09",
+		Root:       "RsltnOfInvstgtn",
+		DecodeOnly: true,
+	})
+	RegisterMessageType(MessageType{
+		MsgDefIdr: "pacs.028.001.03",
+		Root:      "FIToFIPmtStsReq",
+		Endpoint:  "/iso20022/status",
+	})
+	RegisterMessageType(MessageType{
+		MsgDefIdr:  "camt.053.001.08",
+		Root:       "BkToCstmrStmt",
+		DecodeOnly: true,
+	})
+	RegisterMessageType(MessageType{
+		MsgDefIdr:  "camt.054.001.08",
+		Root:       "BkToCstmrDbtCdtNtfctn",
+		DecodeOnly: true,
+	})
+}
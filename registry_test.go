@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+// POST /iso20022 only ingests message types it saves, the others are rejected before decoding
+func TestIngestMessageTypes(t *testing.T) {
+	tests := []struct {
+		msgDefIdr string
+		path      string
+		wantCode  int
+	}{
+		{canonicalPacs008, "/iso20022", http.StatusOK},
+		{"pacs.028.001.03", "/iso20022", http.StatusBadRequest},
+		{"camt.029.001.09", "/iso20022", http.StatusBadRequest},
+		{"camt.053.001.08", "/iso20022", http.StatusBadRequest},
+		{"camt.054.001.08", "/iso20022", http.StatusBadRequest},
+		{"pacs.999.001.01", "/iso20022", http.StatusBadRequest},
+		{canonicalPacs008, "/iso20022/return", http.StatusBadRequest},
+		{canonicalPacs008, "/iso20022/status", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msgDefIdr+" at "+tt.path, func(t *testing.T) {
+			handler := newTestServer(t)
+			body := samplePayload(t, func(msg *BusMsg) { msg.AppHdr.MsgDefIdr = Max35Text(tt.msgDefIdr) })
+			code, response := postIso(t, handler, tt.path, body, nil)
+			if code != tt.wantCode {
+				t.Fatalf("got %d %+v, want %d", code, response, tt.wantCode)
+			}
+			if code != http.StatusOK {
+				checkIssues(t, response.Issues, ValidationErrors{{Location: "/AppHdr/MsgDefIdr", Code: reasonIncorrectContent}})
+				if response.MessageId != "" {
+					t.Errorf("rejected message saved as %s", response.MessageId)
+				}
+			}
+		})
+	}
+}
+
+func TestMessageDefinitions(t *testing.T) {
+	definitions := make(map[string]bool)
+	for _, msgDefIdr := range messageDefinitions() {
+		definitions[msgDefIdr] = true
+	}
+	for msgDefIdr, msgType := range messageTypes {
+		if definitions[msgDefIdr] == msgType.DecodeOnly {
+			t.Errorf("%s listed %v, decode only %v", msgDefIdr, definitions[msgDefIdr], msgType.DecodeOnly)
+		}
+	}
+}
+
+func TestRegisterMessageTypeTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a definition twice does not panic")
+		}
+	}()
+	RegisterMessageType(MessageType{MsgDefIdr: canonicalPacs008})
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Build an error issue for a violated business rule
+func ruleError(location string, code string, format string, args ...interface{}) ValidationError {
+	return ValidationError{Location: location, Code: code, Severity: severityError, Message: fmt.Sprintf(format, args...)}
+}
+
+// Check FIToFICstmrCdtTrf group header against its transactions
+func validateCustomerCreditTransfer(msg BusMsg) ValidationErrors {
+	transfer := msg.Document.FIToFICstmrCdtTrf
+	if transfer == nil {
+		return nil
+	}
+	amounts := make([]*ActiveCurrencyAndAmount, len(transfer.CdtTrfTxInf))
+	dates := make([]*ISODate, len(transfer.CdtTrfTxInf))
+	for i, tx := range transfer.CdtTrfTxInf {
+		if tx != nil {
+			amounts[i], dates[i] = tx.IntrBkSttlmAmt, tx.IntrBkSttlmDt
+		}
+	}
+	return validateGroupHeader("/Document/FIToFICstmrCdtTrf", transfer.GrpHdr, amounts, dates)
+}
+
+// Check FICdtTrf group header against its transactions
+func validateFICreditTransfer(msg BusMsg) ValidationErrors {
+	transfer := msg.Document.FICdtTrf
+	if transfer == nil {
+		return nil
+	}
+	amounts := make([]*ActiveCurrencyAndAmount, len(transfer.CdtTrfTxInf))
+	dates := make([]*ISODate, len(transfer.CdtTrfTxInf))
+	for i, tx := range transfer.CdtTrfTxInf {
+		if tx != nil {
+			amounts[i], dates[i] = tx.IntrBkSttlmAmt, tx.IntrBkSttlmDt
+		}
+	}
+	return validateGroupHeader("/Document/FICdtTrf", transfer.GrpHdr, amounts, dates)
+}
+
+// Check credit transfer group header against the settlement amount and date of each CdtTrfTxInf:
+// NbOfTxs, CtrlSum, TtlIntrBkSttlmAmt, IntrBkSttlmDt and SttlmInf
+func validateGroupHeader(root string, header *GroupHeader93, amounts []*ActiveCurrencyAndAmount, dates []*ISODate) ValidationErrors {
+	var errs ValidationErrors
+	if header == nil {
+		return errs
+	}
+
+	if header.NbOfTxs != nil {
+		count, err := strconv.Atoi(string(*header.NbOfTxs))
+		if err == nil && count != len(amounts) {
+			errs = append(errs, ruleError(root+"/GrpHdr/NbOfTxs", reasonInvalidNumberOfTxs,
+				"NbOfTxs is %d but message has %d transactions", count, len(amounts)))
+		}
+	}
+
+	// Sum transaction amounts, CtrlSum ignores currency while TtlIntrBkSttlmAmt requires a single one
+	var sum Decimal
+	for i, amount := range amounts {
+		if amount == nil {
+			continue
+		}
+		sum = sum.Add(amount.Value)
+
+		total := header.TtlIntrBkSttlmAmt
+		if total != nil && total.Ccy != nil && amount.Ccy != nil && *total.Ccy != *amount.Ccy {
+			errs = append(errs, ruleError(fmt.Sprintf("%s/CdtTrfTxInf[%d]/IntrBkSttlmAmt/Ccy", root, i), reasonIncorrectCurrency,
+				"currency %s differs from TtlIntrBkSttlmAmt currency %s", *amount.Ccy, *total.Ccy))
+		}
+	}
+
+	if header.CtrlSum != nil && Decimal(*header.CtrlSum).Cmp(sum) != 0 {
+		errs = append(errs, ruleError(root+"/GrpHdr/CtrlSum", reasonInvalidControlSum,
+			"CtrlSum is %s but transactions sum up to %s", Decimal(*header.CtrlSum), sum))
+	}
+
+	if header.TtlIntrBkSttlmAmt != nil {
+		if header.TtlIntrBkSttlmAmt.Value.Cmp(sum) != 0 {
+			errs = append(errs, ruleError(root+"/GrpHdr/TtlIntrBkSttlmAmt", reasonInvalidControlSum,
+				"TtlIntrBkSttlmAmt is %s but transactions sum up to %s", header.TtlIntrBkSttlmAmt.Value, sum))
+		}
+		if header.IntrBkSttlmDt == nil {
+			errs = append(errs, ruleError(root+"/GrpHdr/IntrBkSttlmDt", reasonMissingElement,
+				"IntrBkSttlmDt is required when TtlIntrBkSttlmAmt is present"))
+		}
+	}
+
+	if header.IntrBkSttlmDt != nil {
+		date := time.Time(*header.IntrBkSttlmDt)
+		for i, txDate := range dates {
+			if txDate == nil {
+				continue
+			}
+			if txDate := time.Time(*txDate); !sameDate(txDate, date) {
+				errs = append(errs, ruleError(fmt.Sprintf("%s/CdtTrfTxInf[%d]/IntrBkSttlmDt", root, i), reasonInvalidDate,
+					"IntrBkSttlmDt %s differs from group IntrBkSttlmDt %s", txDate.Format("2006-01-02"), date.Format("2006-01-02")))
+			}
+		}
+	}
+
+	errs = append(errs, validateSettlement(header.SttlmInf, root+"/GrpHdr/SttlmInf")...)
+	return errs
+}
+
+// Check settlement method against settlement account and reimbursement agents
+func validateSettlement(settlement *SettlementInstruction7, location string) ValidationErrors {
+	var errs ValidationErrors
+	if settlement == nil || settlement.SttlmMtd == nil {
+		return errs
+	}
+
+	method := *settlement.SttlmMtd
+	reimbursement := settlement.InstgRmbrsmntAgt != nil || settlement.InstdRmbrsmntAgt != nil || settlement.ThrdRmbrsmntAgt != nil
+
+	switch method {
+	case "CLRG":
+		if settlement.SttlmAcct != nil {
+			errs = append(errs, ruleError(location+"/SttlmAcct", reasonElementNotAllowed,
+				"SttlmAcct is not allowed when SttlmMtd is CLRG"))
+		}
+		if reimbursement {
+			errs = append(errs, ruleError(location, reasonElementNotAllowed,
+				"reimbursement agents are not allowed when SttlmMtd is CLRG"))
+		}
+	case "INDA", "INGA":
+		if reimbursement {
+			errs = append(errs, ruleError(location, reasonElementNotAllowed,
+				"reimbursement agents are not allowed when SttlmMtd is %s", method))
+		}
+	case "COVE":
+		if settlement.InstgRmbrsmntAgt == nil && settlement.InstdRmbrsmntAgt == nil {
+			errs = append(errs, ruleError(location, reasonMissingElement,
+				"InstgRmbrsmntAgt or InstdRmbrsmntAgt is required when SttlmMtd is COVE"))
+		}
+	}
+
+	if settlement.ThrdRmbrsmntAgt != nil && (settlement.InstgRmbrsmntAgt == nil || settlement.InstdRmbrsmntAgt == nil) {
+		errs = append(errs, ruleError(location+"/ThrdRmbrsmntAgt", reasonMissingElement,
+			"ThrdRmbrsmntAgt requires both InstgRmbrsmntAgt and InstdRmbrsmntAgt"))
+	}
+	return errs
+}
+
+// Return true when both times fall on the same calendar date
+func sameDate(a time.Time, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
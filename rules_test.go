@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateGroupHeader(t *testing.T) {
+	const root = "/Document/FIToFICstmrCdtTrf"
+	idr := []*ActiveCurrencyAndAmount{testAmount(t, "100.00", "IDR"), testAmount(t, "50.5", "IDR")}
+	dates := []*ISODate{testDate(t, "2021-03-01"), testDate(t, "2021-03-01")}
+
+	tests := []struct {
+		name    string
+		header  string
+		amounts []*ActiveCurrencyAndAmount
+		dates   []*ISODate
+		want    ValidationErrors // Location and Code only
+	}{
+		{
+			name:    "consistent",
+			header:  `{"NbOfTxs": "2", "CtrlSum": "150.5", "TtlIntrBkSttlmAmt": {"Value": "150.50", "Ccy": "IDR"}, "IntrBkSttlmDt": "2021-03-01", "SttlmInf": {"SttlmMtd": "CLRG"}}`,
+			amounts: idr, dates: dates,
+		},
+		{
+			name:    "NbOfTxs",
+			header:  `{"NbOfTxs": "3"}`,
+			amounts: idr, dates: dates,
+			want: ValidationErrors{{Location: root + "/GrpHdr/NbOfTxs", Code: reasonInvalidNumberOfTxs}},
+		},
+		{
+			name:    "CtrlSum",
+			header:  `{"NbOfTxs": "2", "CtrlSum": "150.49"}`,
+			amounts: idr, dates: dates,
+			want: ValidationErrors{{Location: root + "/GrpHdr/CtrlSum", Code: reasonInvalidControlSum}},
+		},
+		{
+			name:    "CtrlSum ignores currencies",
+			header:  `{"NbOfTxs": "2", "CtrlSum": "150.5"}`,
+			amounts: []*ActiveCurrencyAndAmount{testAmount(t, "100", "IDR"), testAmount(t, "50.5", "USD")}, dates: dates,
+		},
+		{
+			name:    "TtlIntrBkSttlmAmt sum, currency and date",
+			header:  `{"NbOfTxs": "2", "TtlIntrBkSttlmAmt": {"Value": "150.50", "Ccy": "IDR"}}`,
+			amounts: []*ActiveCurrencyAndAmount{testAmount(t, "100", "IDR"), testAmount(t, "50", "USD")}, dates: dates,
+			want: ValidationErrors{
+				{Location: root + "/CdtTrfTxInf[1]/IntrBkSttlmAmt/Ccy", Code: reasonIncorrectCurrency},
+				{Location: root + "/GrpHdr/TtlIntrBkSttlmAmt", Code: reasonInvalidControlSum},
+				{Location: root + "/GrpHdr/IntrBkSttlmDt", Code: reasonMissingElement},
+			},
+		},
+		{
+			name:    "IntrBkSttlmDt",
+			header:  `{"NbOfTxs": "2", "IntrBkSttlmDt": "2021-03-01"}`,
+			amounts: idr, dates: []*ISODate{testDate(t, "2021-03-01"), testDate(t, "2021-03-02")},
+			want: ValidationErrors{{Location: root + "/CdtTrfTxInf[1]/IntrBkSttlmDt", Code: reasonInvalidDate}},
+		},
+		{
+			name:    "CLRG with settlement account and reimbursement agent",
+			header:  `{"NbOfTxs": "2", "SttlmInf": {"SttlmMtd": "CLRG", "SttlmAcct": {"Id": {"Othr": {"Id": "1"}}}, "InstgRmbrsmntAgt": {"FinInstnId": {"BICFI": "DEUTDEFF"}}}}`,
+			amounts: idr, dates: dates,
+			want: ValidationErrors{
+				{Location: root + "/GrpHdr/SttlmInf/SttlmAcct", Code: reasonElementNotAllowed},
+				{Location: root + "/GrpHdr/SttlmInf", Code: reasonElementNotAllowed},
+			},
+		},
+		{
+			name:    "INDA with reimbursement agent",
+			header:  `{"NbOfTxs": "2", "SttlmInf": {"SttlmMtd": "INDA", "InstdRmbrsmntAgt": {"FinInstnId": {"BICFI": "DEUTDEFF"}}}}`,
+			amounts: idr, dates: dates,
+			want: ValidationErrors{{Location: root + "/GrpHdr/SttlmInf", Code: reasonElementNotAllowed}},
+		},
+		{
+			name:    "COVE without reimbursement agent",
+			header:  `{"NbOfTxs": "2", "SttlmInf": {"SttlmMtd": "COVE"}}`,
+			amounts: idr, dates: dates,
+			want: ValidationErrors{{Location: root + "/GrpHdr/SttlmInf", Code: reasonMissingElement}},
+		},
+		{
+			name:    "COVE with third reimbursement agent only",
+			header:  `{"NbOfTxs": "2", "SttlmInf": {"SttlmMtd": "COVE", "InstgRmbrsmntAgt": {"FinInstnId": {"BICFI": "DEUTDEFF"}}, "ThrdRmbrsmntAgt": {"FinInstnId": {"BICFI": "CENAIDJA"}}}}`,
+			amounts: idr, dates: dates,
+			want: ValidationErrors{{Location: root + "/GrpHdr/SttlmInf/ThrdRmbrsmntAgt", Code: reasonMissingElement}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header GroupHeader93
+			if err := json.Unmarshal([]byte(tt.header), &header); err != nil {
+				t.Fatal(err)
+			}
+
+			checkIssues(t, validateGroupHeader(root, &header, tt.amounts, tt.dates), tt.want)
+		})
+	}
+}
+
+// Check issues have the locations and reason codes of want, in order
+func checkIssues(t *testing.T, issues ValidationErrors, want ValidationErrors) {
+	t.Helper()
+	var got ValidationErrors
+	for _, issue := range issues {
+		got = append(got, ValidationError{Location: issue.Location, Code: issue.Code})
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got issues %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("got issue %v, want %v", got[i], want[i])
+		}
+	}
+}
+
+// Return amount in currency, failing the test when value is invalid
+func testAmount(t *testing.T, value string, ccy string) *ActiveCurrencyAndAmount {
+	t.Helper()
+	code := ActiveCurrencyCode(ccy)
+	return &ActiveCurrencyAndAmount{Value: mustDecimal(t, value), Ccy: &code}
+}
+
+// Return YYYY-MM-DD date, failing the test when it is invalid
+func testDate(t *testing.T, text string) *ISODate {
+	t.Helper()
+	var date ISODate
+	if err := date.UnmarshalText([]byte(text)); err != nil {
+		t.Fatal(err)
+	}
+	return &date
+}
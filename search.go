@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Page size limits of GET /iso20022
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// PaymentFilter selects stored transactions, empty fields match everything
+// Dates are inclusive, ReceivedTo is exclusive
+type PaymentFilter struct {
+	From         string // settlement date YYYY-MM-DD
+	To           string
+	ReceivedFrom time.Time
+	ReceivedTo   time.Time
+	Agent        string // debtor or creditor agent
+	DbtrAgt      string
+	CdtrAgt      string
+	Account      string // debtor or creditor account
+	Currency     string
+	MinAmount    *Decimal
+	MaxAmount    *Decimal
+	Status       string
+	State        string
+	ClientIP     string
+	Limit        int
+	Cursor       *PaymentCursor
+}
+
+// PaymentCursor is the position after the last transaction of a page
+// Transactions are listed newest first
+type PaymentCursor struct {
+	ReceivedAt string
+	MessageID  string
+	Seq        int
+}
+
+// PaymentRecord is a stored transaction as listed by GET /iso20022
+// Original is set on returns, pointing at the returned transaction
+type PaymentRecord struct {
+	MessageID  string          `xml:"MessageId" json:"MessageId"`
+	Seq        int             `xml:"Seq" json:"Seq"`
+	MsgId      string          `xml:"MsgId,omitempty" json:"MsgId,omitempty"`
+	EndToEndId string          `xml:"EndToEndId,omitempty" json:"EndToEndId,omitempty"`
+	TxId       string          `xml:"TxId,omitempty" json:"TxId,omitempty"`
+	UETR       string          `xml:"UETR,omitempty" json:"UETR,omitempty"`
+	DbtrAgt    string          `xml:"DbtrAgt,omitempty" json:"DbtrAgt,omitempty"`
+	CdtrAgt    string          `xml:"CdtrAgt,omitempty" json:"CdtrAgt,omitempty"`
+	DbtrAcct   string          `xml:"DbtrAcct,omitempty" json:"DbtrAcct,omitempty"`
+	CdtrAcct   string          `xml:"CdtrAcct,omitempty" json:"CdtrAcct,omitempty"`
+	Amount     Decimal         `xml:"Amount" json:"Amount"`
+	Currency   string          `xml:"Ccy,omitempty" json:"Ccy,omitempty"`
+	SttlmDt    string          `xml:"SttlmDt,omitempty" json:"SttlmDt,omitempty"`
+	Status     string          `xml:"Status" json:"Status"`
+	State      string          `xml:"State,omitempty" json:"State,omitempty"` // lifecycle state of pacs.008 transactions
+	ClientIP   string          `xml:"ClientIP" json:"ClientIP"`
+	ReceivedAt time.Time       `xml:"ReceivedAt" json:"ReceivedAt"`
+	Original   *TransactionRef `xml:"Original,omitempty" json:"Original,omitempty"`
+}
+
+// PaymentPage is a page of GET /iso20022 results
+// NextCursor is empty on the last page
+type PaymentPage struct {
+	XMLName    xml.Name        `xml:"Payments" json:"-"`
+	Payments   []PaymentRecord `xml:"Payment" json:"Payments"`
+	NextCursor string          `xml:"NextCursor,omitempty" json:"NextCursor,omitempty"`
+}
+
+// Build PaymentFilter from query parameters:
+// from, to, receivedFrom, receivedTo, agent, dbtrAgt, cdtrAgt, account, ccy, minAmount, maxAmount, status, state, clientIp, limit and cursor
+func parsePaymentFilter(query url.Values) (PaymentFilter, error) {
+	filter := PaymentFilter{
+		Agent:    query.Get("agent"),
+		DbtrAgt:  query.Get("dbtrAgt"),
+		CdtrAgt:  query.Get("cdtrAgt"),
+		Account:  query.Get("account"),
+		Currency: strings.ToUpper(query.Get("ccy")),
+		Status:   query.Get("status"),
+		State:    query.Get("state"),
+		ClientIP: query.Get("clientIp"),
+		Limit:    defaultPageSize,
+	}
+
+	for name, date := range map[string]*string{"from": &filter.From, "to": &filter.To} {
+		if value := query.Get(name); value != "" {
+			if _, err := time.Parse("2006-01-02", value); err != nil {
+				return filter, fmt.Errorf("%s must be a YYYY-MM-DD date", name)
+			}
+			*date = value
+		}
+	}
+
+	var err error
+	if value := query.Get("receivedFrom"); value != "" {
+		if filter.ReceivedFrom, _, err = parseTimeParam(value); err != nil {
+			return filter, fmt.Errorf("receivedFrom: %s", err.Error())
+		}
+	}
+	if value := query.Get("receivedTo"); value != "" {
+		var dateOnly bool
+		if filter.ReceivedTo, dateOnly, err = parseTimeParam(value); err != nil {
+			return filter, fmt.Errorf("receivedTo: %s", err.Error())
+		}
+		// a date includes the whole day
+		if dateOnly {
+			filter.ReceivedTo = filter.ReceivedTo.AddDate(0, 0, 1)
+		}
+	}
+
+	for name, amount := range map[string]**Decimal{"minAmount": &filter.MinAmount, "maxAmount": &filter.MaxAmount} {
+		if value := query.Get(name); value != "" {
+			d, err := ParseDecimal(value)
+			if err != nil {
+				return filter, fmt.Errorf("%s: %s", name, err.Error())
+			}
+			*amount = &d
+		}
+	}
+
+	if value := query.Get("limit"); value != "" {
+		filter.Limit, err = strconv.Atoi(value)
+		if err != nil || filter.Limit < 1 || filter.Limit > maxPageSize {
+			return filter, fmt.Errorf("limit must be a number from 1 to %d", maxPageSize)
+		}
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		if filter.Cursor, err = decodeCursor(value); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+// Parse RFC 3339 time or YYYY-MM-DD date (UTC), reporting whether value was a date
+func parseTimeParam(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return t, false, errors.New("must be an RFC 3339 time or YYYY-MM-DD date")
+	}
+	return t, true, nil
+}
+
+// Check amount is within MinAmount and MaxAmount
+func (filter PaymentFilter) matchAmount(amount Decimal) bool {
+	if filter.MinAmount != nil && amount.Cmp(*filter.MinAmount) < 0 {
+		return false
+	}
+	if filter.MaxAmount != nil && amount.Cmp(*filter.MaxAmount) > 0 {
+		return false
+	}
+	return true
+}
+
+// Encode cursor as opaque URL-safe token
+func (c PaymentCursor) String() string {
+	token := fmt.Sprintf("%s\x00%s\x00%d", c.ReceivedAt, c.MessageID, c.Seq)
+	return base64.RawURLEncoding.EncodeToString([]byte(token))
+}
+
+// Decode cursor token written by PaymentCursor.String
+func decodeCursor(token string) (*PaymentCursor, error) {
+	invalid := errors.New("invalid cursor")
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+	parts := strings.Split(string(data), "\x00")
+	if len(parts) != 3 {
+		return nil, invalid
+	}
+	seq, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, invalid
+	}
+	return &PaymentCursor{ReceivedAt: parts[0], MessageID: parts[1], Seq: seq}, nil
+}
@@ -0,0 +1,924 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLStore keeps received messages in an embedded SQLite database
+// Every transaction gets its own row so payments can be looked up by MsgId, EndToEndId, TxId or UETR
+type SQLStore struct {
+	db *sql.DB
+}
+
+// Receipt times are stored in UTC with a fixed number of digits, so they sort as text
+const sqlTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+const sqlSchema = `
+CREATE TABLE IF NOT EXISTS messages (
+	id          TEXT PRIMARY KEY,
+	biz_msg_idr TEXT NOT NULL,
+	msg_def_idr TEXT NOT NULL,
+	msg_id      TEXT NOT NULL,
+	sender      TEXT NOT NULL,
+	client_ip   TEXT NOT NULL,
+	format      TEXT NOT NULL,
+	status      TEXT NOT NULL,
+	received_at TEXT NOT NULL,
+	payload     BLOB NOT NULL,
+	document    BLOB NOT NULL,
+	digest      TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS transactions (
+	message_id       TEXT NOT NULL REFERENCES messages(id),
+	seq              INTEGER NOT NULL,
+	msg_id           TEXT NOT NULL,
+	end_to_end_id    TEXT NOT NULL,
+	tx_id            TEXT NOT NULL,
+	uetr             TEXT NOT NULL,
+	dbtr_agt         TEXT NOT NULL,
+	cdtr_agt         TEXT NOT NULL,
+	dbtr_acct        TEXT NOT NULL,
+	cdtr_acct        TEXT NOT NULL,
+	amount           TEXT NOT NULL,
+	currency         TEXT NOT NULL,
+	settlement_date  TEXT NOT NULL,
+	status           TEXT NOT NULL,
+	received_at      TEXT NOT NULL,
+	orgnl_message_id TEXT NOT NULL,
+	orgnl_seq        INTEGER NOT NULL,
+	state            TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (message_id, seq)
+);
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+	scope        TEXT NOT NULL,
+	key          TEXT NOT NULL,
+	body_digest  TEXT NOT NULL,
+	status_code  INTEGER NOT NULL,
+	content_type TEXT NOT NULL,
+	body         BLOB NOT NULL,
+	created_at   TEXT NOT NULL,
+	PRIMARY KEY (scope, key)
+);
+CREATE TABLE IF NOT EXISTS cases (
+	id            TEXT PRIMARY KEY,
+	message_id    TEXT NOT NULL REFERENCES messages(id),
+	status        TEXT NOT NULL,
+	reason        TEXT NOT NULL,
+	created_at    TEXT NOT NULL,
+	resolved_at   TEXT NOT NULL,
+	resolution_id TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS case_transactions (
+	case_id             TEXT NOT NULL REFERENCES cases(id),
+	seq                 INTEGER NOT NULL,
+	cxl_id              TEXT NOT NULL,
+	orgnl_msg_id        TEXT NOT NULL,
+	orgnl_msg_nm_id     TEXT NOT NULL,
+	orgnl_end_to_end_id TEXT NOT NULL,
+	orgnl_tx_id         TEXT NOT NULL,
+	orgnl_uetr          TEXT NOT NULL,
+	orgnl_message_id    TEXT NOT NULL,
+	orgnl_seq           INTEGER NOT NULL,
+	PRIMARY KEY (case_id, seq)
+);
+CREATE TABLE IF NOT EXISTS transitions (
+	message_id       TEXT NOT NULL REFERENCES messages(id),
+	seq              INTEGER NOT NULL,
+	from_state       TEXT NOT NULL,
+	to_state         TEXT NOT NULL,
+	event            TEXT NOT NULL,
+	event_message_id TEXT NOT NULL REFERENCES messages(id),
+	at               TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS notifications (
+	id           TEXT PRIMARY KEY REFERENCES messages(id),
+	message_id   TEXT NOT NULL REFERENCES messages(id),
+	account      TEXT NOT NULL,
+	cdt_dbt_ind  TEXT NOT NULL,
+	created_at   TEXT NOT NULL,
+	delivered_at TEXT NOT NULL,
+	attempts     INTEGER NOT NULL,
+	last_error   TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS messages_biz_msg_idr ON messages(biz_msg_idr);
+CREATE INDEX IF NOT EXISTS messages_msg_id ON messages(msg_id);
+CREATE INDEX IF NOT EXISTS transactions_msg_id ON transactions(msg_id);
+CREATE INDEX IF NOT EXISTS transactions_end_to_end_id ON transactions(end_to_end_id);
+CREATE INDEX IF NOT EXISTS transactions_tx_id ON transactions(tx_id);
+CREATE INDEX IF NOT EXISTS transactions_uetr ON transactions(uetr);
+CREATE INDEX IF NOT EXISTS transactions_received_at ON transactions(received_at, message_id, seq);
+CREATE INDEX IF NOT EXISTS transactions_original ON transactions(orgnl_message_id, orgnl_seq);
+CREATE INDEX IF NOT EXISTS transactions_dbtr_acct ON transactions(dbtr_acct, settlement_date);
+CREATE INDEX IF NOT EXISTS transactions_cdtr_acct ON transactions(cdtr_acct, settlement_date);
+CREATE INDEX IF NOT EXISTS case_transactions_original ON case_transactions(orgnl_message_id, orgnl_seq);
+CREATE INDEX IF NOT EXISTS transitions_transaction ON transitions(message_id, seq);
+CREATE INDEX IF NOT EXISTS notifications_message ON notifications(message_id);
+CREATE INDEX IF NOT EXISTS notifications_created_at ON notifications(created_at, id);
+`
+
+// Open SQLStore at path, creating database file and tables when missing
+func NewSQLStore(path string) (*SQLStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer, sharing one connection avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+
+	if _, err = db.Exec(sqlSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Failed creating tables: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// Close database
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// Save message, its transactions and the notifications generated with it in a single database transaction
+// msg.ID is kept when already set by another store, otherwise it is built like FileStore names
+func (s *SQLStore) Save(msg *StoredMessage) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = saveMessage(tx, msg); err != nil {
+		return err
+	}
+	for _, notification := range msg.Notifications {
+		n := notification.Notification
+		n.MessageID = msg.ID
+		if err = saveMessage(tx, notification); err != nil {
+			return err
+		}
+		n.ID = notification.ID
+		_, err = tx.Exec(`INSERT INTO notifications (id, message_id, account, cdt_dbt_ind, created_at, delivered_at, attempts, last_error)
+			VALUES (?, ?, ?, ?, ?, '', 0, '')`,
+			n.ID, n.MessageID, n.Account, n.CdtDbtInd, n.CreatedAt.UTC().Format(sqlTimeFormat))
+		if err != nil {
+			return fmt.Errorf("Failed saving notification %s: %w", n.ID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Save message and its transactions, case and transitions in transaction tx
+func saveMessage(tx *sql.Tx, msg *StoredMessage) error {
+	var err error
+	if msg.ID == "" {
+		base := messageID(msg)
+		id := base
+		for i := 1; ; i++ {
+			var exists int
+			err = tx.QueryRow(`SELECT COUNT(*) FROM messages WHERE id = ?`, id).Scan(&exists)
+			if err != nil {
+				return err
+			}
+			if exists == 0 {
+				break
+			}
+			id = fmt.Sprintf("%s-%d", base, i)
+		}
+		msg.ID = id
+	}
+
+	receivedAt := msg.ReceivedAt.UTC().Format(sqlTimeFormat)
+	_, err = tx.Exec(`INSERT INTO messages (id, biz_msg_idr, msg_def_idr, msg_id, sender, client_ip, format, status, received_at, payload, document, digest)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		msg.ID, msg.BizMsgIdr, msg.MsgDefIdr, msg.MsgId, msg.Sender, msg.ClientIP, msg.Format, msg.Status, receivedAt, msg.Payload, msg.Document, msg.Digest)
+	if err != nil {
+		return fmt.Errorf("Failed saving message: %w", err)
+	}
+
+	for _, row := range transactionRows(msg) {
+		original := TransactionRef{Seq: -1}
+		if ref, ok := msg.Originals[row.Seq]; ok {
+			original = ref
+			// a return flows back from the original creditor agent to the original debtor agent
+			if row.DbtrAgt == "" && row.CdtrAgt == "" {
+				err = tx.QueryRow(`SELECT cdtr_agt, dbtr_agt FROM transactions WHERE message_id = ? AND seq = ?`, ref.MessageID, ref.Seq).
+					Scan(&row.DbtrAgt, &row.CdtrAgt)
+				if err != nil && err != sql.ErrNoRows {
+					return err
+				}
+			}
+		}
+
+		_, err = tx.Exec(`INSERT INTO transactions (message_id, seq, msg_id, end_to_end_id, tx_id, uetr, dbtr_agt, cdtr_agt, dbtr_acct, cdtr_acct, amount, currency, settlement_date, status, received_at, orgnl_message_id, orgnl_seq)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			msg.ID, row.Seq, msg.MsgId, row.EndToEndId, row.TxId, row.UETR, row.DbtrAgt, row.CdtrAgt, row.DbtrAcct, row.CdtrAcct, row.Amount, row.Currency, row.SettlementDate, msg.Status, receivedAt,
+			original.MessageID, original.Seq)
+		if err != nil {
+			return fmt.Errorf("Failed saving transaction %d: %w", row.Seq, err)
+		}
+	}
+
+	if c := msg.Case; c != nil {
+		if err = saveCase(tx, msg.ID, c); err != nil {
+			return fmt.Errorf("Failed saving case %s: %w", c.Id, err)
+		}
+	}
+
+	for i := range msg.Transitions {
+		t := &msg.Transitions[i]
+		t.MessageID = msg.ID
+		if t.Transaction.MessageID == "" {
+			t.Transaction.MessageID = msg.ID
+		}
+		_, err = tx.Exec(`INSERT INTO transitions (message_id, seq, from_state, to_state, event, event_message_id, at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			t.Transaction.MessageID, t.Transaction.Seq, t.From, t.To, t.Event, t.MessageID, t.At.UTC().Format(sqlTimeFormat))
+		if err == nil {
+			_, err = tx.Exec(`UPDATE transactions SET state = ? WHERE message_id = ? AND seq = ?`, t.To, t.Transaction.MessageID, t.Transaction.Seq)
+		}
+		if err != nil {
+			return fmt.Errorf("Failed saving transition of transaction %d of %s: %w", t.Transaction.Seq, t.Transaction.MessageID, err)
+		}
+	}
+	return nil
+}
+
+// Open case c as the message saved with it, or resolve it when it was already opened
+func saveCase(tx *sql.Tx, messageID string, c *InvestigationCase) error {
+	if c.MessageID != "" {
+		c.ResolutionID = messageID
+		resolvedAt := ""
+		if c.ResolvedAt != nil {
+			resolvedAt = c.ResolvedAt.UTC().Format(sqlTimeFormat)
+		}
+		_, err := tx.Exec(`UPDATE cases SET status = ?, reason = ?, resolved_at = ?, resolution_id = ? WHERE id = ?`,
+			c.Status, c.Reason, resolvedAt, c.ResolutionID, c.Id)
+		return err
+	}
+
+	c.MessageID = messageID
+	_, err := tx.Exec(`INSERT INTO cases (id, message_id, status, reason, created_at, resolved_at, resolution_id)
+		VALUES (?, ?, ?, ?, ?, '', '')`,
+		c.Id, c.MessageID, c.Status, c.Reason, c.CreatedAt.UTC().Format(sqlTimeFormat))
+	if err != nil {
+		return err
+	}
+	for _, t := range c.Transactions {
+		_, err = tx.Exec(`INSERT INTO case_transactions (case_id, seq, cxl_id, orgnl_msg_id, orgnl_msg_nm_id, orgnl_end_to_end_id, orgnl_tx_id, orgnl_uetr, orgnl_message_id, orgnl_seq)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			c.Id, t.Seq, t.CxlId, t.OrgnlMsgId, t.OrgnlMsgNmId, t.OrgnlEndToEndId, t.OrgnlTxId, t.OrgnlUETR, t.Original.MessageID, t.Original.Seq)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Columns read into StoredMessage by scanMessage
+const messageColumns = `m.id, m.biz_msg_idr, m.msg_def_idr, m.msg_id, m.sender, m.client_ip, m.format, m.status, m.received_at, m.payload, m.document, m.digest`
+
+// Read a row of messageColumns, ErrMessageNotFound is returned when there is none
+func scanMessage(row *sql.Row) (*StoredMessage, error) {
+	var msg StoredMessage
+	var receivedAt string
+	err := row.Scan(&msg.ID, &msg.BizMsgIdr, &msg.MsgDefIdr, &msg.MsgId, &msg.Sender, &msg.ClientIP, &msg.Format, &msg.Status, &receivedAt, &msg.Payload, &msg.Document, &msg.Digest)
+	if err == sql.ErrNoRows {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	msg.ReceivedAt, err = time.Parse(sqlTimeFormat, receivedAt)
+	return &msg, err
+}
+
+// Return latest message whose ID, BizMsgIdr, MsgId, or one of its EndToEndId, TxId or UETR equals id
+// Returns only match on the EndToEndId or UETR they refer to when no originating message does,
+// rejected messages only when no accepted one does
+func (s *SQLStore) Find(id string) (*StoredMessage, error) {
+	return scanMessage(s.db.QueryRow(`SELECT `+messageColumns+`
+		FROM messages m
+		WHERE m.id = ?1 OR m.biz_msg_idr = ?1 OR m.msg_id = ?1
+			OR m.id IN (SELECT message_id FROM transactions WHERE end_to_end_id = ?1 OR tx_id = ?1 OR uetr = ?1)
+		ORDER BY CASE WHEN m.id = ?1 OR m.biz_msg_idr = ?1 OR m.msg_id = ?1
+				OR m.id IN (SELECT message_id FROM transactions WHERE tx_id = ?1)
+				OR m.msg_def_idr NOT LIKE 'pacs.004.%' THEN 0 ELSE 1 END,
+			m.status = ?2,
+			m.received_at DESC
+		LIMIT 1`, id, statusRejected))
+}
+
+// Return latest message of type name, e.g. pacs.008, whose MsgId equals msgId
+// A rejected message is only returned when no accepted one has that MsgId
+func (s *SQLStore) FindMessage(msgId string, name string) (*StoredMessage, error) {
+	return scanMessage(s.db.QueryRow(`SELECT `+messageColumns+`
+		FROM messages m
+		WHERE m.msg_id = ? AND m.msg_def_idr LIKE ?
+		ORDER BY m.status = ?, m.received_at DESC
+		LIMIT 1`, msgId, name+".%", statusRejected))
+}
+
+// Return identifiers of msg already used by messages of the same type received since the given time
+// MsgId is only compared with messages of the same sender, TxId, RtrId and UETR with every message
+// Each reused identifier is reported with the earliest message using it, rejected messages do not count
+func (s *SQLStore) FindDuplicates(msg *StoredMessage, since time.Time) ([]DuplicateId, error) {
+	var duplicates []DuplicateId
+	after := since.UTC().Format(sqlTimeFormat)
+	sameType := messageName(msg.MsgDefIdr) + ".%"
+
+	if msg.MsgId != "" {
+		original, err := scanMessage(s.db.QueryRow(`SELECT `+messageColumns+`
+			FROM messages m
+			WHERE m.msg_id = ? AND m.sender = ? AND m.msg_def_idr LIKE ? AND m.received_at >= ? AND m.status <> ?
+			ORDER BY m.received_at
+			LIMIT 1`, msg.MsgId, msg.Sender, sameType, after, statusRejected))
+		if err != nil && !errors.Is(err, ErrMessageNotFound) {
+			return nil, err
+		}
+		if original != nil {
+			root := ""
+			if msg.Message != nil {
+				root, _ = msg.Message.Document.root()
+			}
+			duplicates = append(duplicates, DuplicateId{Location: msgIdLocation(root), Value: msg.MsgId, Original: original})
+		}
+	}
+
+	for _, row := range transactionRows(msg) {
+		for _, id := range row.UniqueIds {
+			column := uniqueIdColumns[path.Base(id.Element)]
+			original, err := scanMessage(s.db.QueryRow(`SELECT `+messageColumns+`
+				FROM transactions t JOIN messages m ON m.id = t.message_id
+				WHERE t.`+column+` = ? AND m.msg_def_idr LIKE ? AND t.received_at >= ? AND m.status <> ?
+				ORDER BY t.received_at
+				LIMIT 1`, id.Value, sameType, after, statusRejected))
+			if err != nil && !errors.Is(err, ErrMessageNotFound) {
+				return nil, err
+			}
+			if original != nil {
+				duplicates = append(duplicates, DuplicateId{Location: row.Location + "/" + id.Element, Value: id.Value, Original: original})
+			}
+		}
+	}
+	return duplicates, nil
+}
+
+// Transaction column holding each unique identifier element
+var uniqueIdColumns = map[string]string{
+	"TxId":  "tx_id",
+	"RtrId": "tx_id",
+	"UETR":  "uetr",
+}
+
+// Columns read into PaymentRecord by scanPayment
+const paymentColumns = `t.message_id, t.seq, t.msg_id, t.end_to_end_id, t.tx_id, t.uetr, t.dbtr_agt, t.cdtr_agt, t.dbtr_acct, t.cdtr_acct,
+	t.amount, t.currency, t.settlement_date, t.status, t.state, m.client_ip, t.received_at, t.orgnl_message_id, t.orgnl_seq`
+
+// Read a row of paymentColumns with scan, which is Scan of *sql.Row or *sql.Rows
+// Receipt time is also returned as stored, for cursors
+func scanPayment(scan func(dest ...interface{}) error) (PaymentRecord, string, error) {
+	var payment PaymentRecord
+	var amount, receivedAt string
+	var original TransactionRef
+	err := scan(&payment.MessageID, &payment.Seq, &payment.MsgId, &payment.EndToEndId, &payment.TxId, &payment.UETR, &payment.DbtrAgt, &payment.CdtrAgt, &payment.DbtrAcct, &payment.CdtrAcct,
+		&amount, &payment.Currency, &payment.SttlmDt, &payment.Status, &payment.State, &payment.ClientIP, &receivedAt, &original.MessageID, &original.Seq)
+	if err != nil {
+		return payment, "", err
+	}
+	if original.MessageID != "" {
+		payment.Original = &original
+	}
+	if payment.Amount, err = ParseDecimal(amount); err != nil {
+		return payment, "", err
+	}
+	payment.ReceivedAt, err = time.Parse(sqlTimeFormat, receivedAt)
+	return payment, receivedAt, err
+}
+
+// Return latest pacs.008 transaction with UETR, or when uetr is empty with EndToEndId in message MsgId
+// A rejected transaction is only returned when no accepted one matches, nil is returned when there is none
+func (s *SQLStore) FindTransaction(msgId string, endToEndId string, uetr string) (*PaymentRecord, error) {
+	match, args := `t.msg_id = ? AND t.end_to_end_id = ?`, []interface{}{msgId, endToEndId}
+	if uetr != "" {
+		match, args = `t.uetr = ?`, []interface{}{uetr}
+	}
+	row := s.db.QueryRow(`SELECT `+paymentColumns+`
+		FROM transactions t JOIN messages m ON m.id = t.message_id
+		WHERE `+match+` AND m.msg_def_idr LIKE 'pacs.008.%'
+		ORDER BY m.status = ?, t.received_at DESC
+		LIMIT 1`, append(args, statusRejected)...)
+	payment, _, err := scanPayment(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// Return stored transaction ref points at, nil when there is none
+func (s *SQLStore) FindPayment(ref TransactionRef) (*PaymentRecord, error) {
+	row := s.db.QueryRow(`SELECT `+paymentColumns+`
+		FROM transactions t JOIN messages m ON m.id = t.message_id
+		WHERE t.message_id = ? AND t.seq = ?`, ref.MessageID, ref.Seq)
+	payment, _, err := scanPayment(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// Return state and state history of every transaction of a message, oldest transition first
+func (s *SQLStore) FindLifecycle(messageID string) ([]TransactionLifecycle, error) {
+	rows, err := s.db.Query(`SELECT seq, end_to_end_id, uetr, state
+		FROM transactions
+		WHERE message_id = ?
+		ORDER BY seq`, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lifecycle := []TransactionLifecycle{}
+	for rows.Next() {
+		var tx TransactionLifecycle
+		if err := rows.Scan(&tx.Seq, &tx.EndToEndId, &tx.UETR, &tx.State); err != nil {
+			return nil, err
+		}
+		tx.History = []Transition{}
+		lifecycle = append(lifecycle, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	rows, err = s.db.Query(`SELECT seq, from_state, to_state, event, event_message_id, at
+		FROM transitions
+		WHERE message_id = ?
+		ORDER BY at, rowid`, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t Transition
+		var at string
+		if err := rows.Scan(&t.Transaction.Seq, &t.From, &t.To, &t.Event, &t.MessageID, &at); err != nil {
+			return nil, err
+		}
+		if t.At, err = time.Parse(sqlTimeFormat, at); err != nil {
+			return nil, err
+		}
+		t.Transaction.MessageID = messageID
+		for i := range lifecycle {
+			if lifecycle[i].Seq == t.Transaction.Seq {
+				lifecycle[i].History = append(lifecycle[i].History, t)
+			}
+		}
+	}
+	return lifecycle, rows.Err()
+}
+
+// Return accepted return transactions linked to the original transaction
+func (s *SQLStore) FindReturns(original TransactionRef) ([]PaymentRecord, error) {
+	rows, err := s.db.Query(`SELECT `+paymentColumns+`
+		FROM transactions t JOIN messages m ON m.id = t.message_id
+		WHERE t.orgnl_message_id = ? AND t.orgnl_seq = ? AND m.msg_def_idr LIKE 'pacs.004.%' AND t.status = ?
+		ORDER BY t.received_at`, original.MessageID, original.Seq, statusAccepted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []PaymentRecord
+	for rows.Next() {
+		payment, _, err := scanPayment(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+// Return pacs.008 transactions crediting or debiting account in currency, settled on date (YYYY-MM-DD)
+// Only transactions still Settled count, those since returned or cancelled have no reversal entry to offset them
+func (s *SQLStore) FindStatementEntries(account string, currency string, date string) ([]PaymentRecord, error) {
+	rows, err := s.db.Query(`SELECT `+paymentColumns+`
+		FROM transactions t JOIN messages m ON m.id = t.message_id
+		WHERE (t.dbtr_acct = ?1 OR t.cdtr_acct = ?1) AND t.currency = ?2 AND t.settlement_date = ?3
+			AND m.msg_def_idr LIKE 'pacs.008.%'
+			AND t.state = ?4
+		ORDER BY t.received_at, t.message_id, t.seq`, account, currency, date, stateSettled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []PaymentRecord
+	for rows.Next() {
+		payment, _, err := scanPayment(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+// Return latest pacs.008 transaction crediting or debiting account, nil when there is none
+func (s *SQLStore) FindAccount(account string) (*PaymentRecord, error) {
+	row := s.db.QueryRow(`SELECT `+paymentColumns+`
+		FROM transactions t JOIN messages m ON m.id = t.message_id
+		WHERE (t.dbtr_acct = ?1 OR t.cdtr_acct = ?1) AND m.msg_def_idr LIKE 'pacs.008.%'
+		ORDER BY t.received_at DESC
+		LIMIT 1`, account)
+	payment, _, err := scanPayment(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// Return a page of notifications matching filter, oldest first
+func (s *SQLStore) FindNotifications(filter NotificationFilter) (NotificationPage, error) {
+	page := NotificationPage{Notifications: []Notification{}}
+	var where []string
+	var args []interface{}
+
+	add := func(condition string, values ...interface{}) {
+		where = append(where, condition)
+		args = append(args, values...)
+	}
+	if filter.Account != "" {
+		add("account = ?", filter.Account)
+	}
+	if filter.MessageID != "" {
+		add("message_id = ?", filter.MessageID)
+	}
+	if filter.Undelivered {
+		add("delivered_at = ''")
+	}
+	if filter.After != "" {
+		add("(created_at, id) > (SELECT created_at, id FROM notifications WHERE id = ?)", filter.After)
+	}
+
+	query := `SELECT id, message_id, account, cdt_dbt_ind, created_at, delivered_at, attempts, last_error FROM notifications`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	// one more row than the page holds means there is a next page
+	query += " ORDER BY created_at, id LIMIT ?"
+	args = append(args, filter.Limit+1)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return page, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var n Notification
+		var createdAt, deliveredAt string
+		err := rows.Scan(&n.ID, &n.MessageID, &n.Account, &n.CdtDbtInd, &createdAt, &deliveredAt, &n.Attempts, &n.LastError)
+		if err != nil {
+			return page, err
+		}
+		if n.CreatedAt, err = time.Parse(sqlTimeFormat, createdAt); err != nil {
+			return page, err
+		}
+		if deliveredAt != "" {
+			t, err := time.Parse(sqlTimeFormat, deliveredAt)
+			if err != nil {
+				return page, err
+			}
+			n.DeliveredAt = &t
+		}
+		if len(page.Notifications) == filter.Limit {
+			page.NextCursor = page.Notifications[filter.Limit-1].ID
+			break
+		}
+		page.Notifications = append(page.Notifications, n)
+	}
+	return page, rows.Err()
+}
+
+// Record a delivery attempt of notification id at the given time, keeping the error of a failed one
+func (s *SQLStore) RecordDelivery(id string, at time.Time, deliveryErr error) error {
+	if deliveryErr != nil {
+		_, err := s.db.Exec(`UPDATE notifications SET attempts = attempts + 1, last_error = ? WHERE id = ?`, deliveryErr.Error(), id)
+		return err
+	}
+	_, err := s.db.Exec(`UPDATE notifications SET attempts = attempts + 1, delivered_at = ?, last_error = '' WHERE id = ?`,
+		at.UTC().Format(sqlTimeFormat), id)
+	return err
+}
+
+// Return investigation case by its identifier, with the transactions it cancels
+func (s *SQLStore) FindCase(id string) (*InvestigationCase, error) {
+	var c InvestigationCase
+	var createdAt, resolvedAt string
+	err := s.db.QueryRow(`SELECT id, message_id, status, reason, created_at, resolved_at, resolution_id
+		FROM cases
+		WHERE id = ?`, id).
+		Scan(&c.Id, &c.MessageID, &c.Status, &c.Reason, &createdAt, &resolvedAt, &c.ResolutionID)
+	if err == sql.ErrNoRows {
+		return nil, ErrCaseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.CreatedAt, err = time.Parse(sqlTimeFormat, createdAt); err != nil {
+		return nil, err
+	}
+	if resolvedAt != "" {
+		t, err := time.Parse(sqlTimeFormat, resolvedAt)
+		if err != nil {
+			return nil, err
+		}
+		c.ResolvedAt = &t
+	}
+
+	rows, err := s.db.Query(`SELECT seq, cxl_id, orgnl_msg_id, orgnl_msg_nm_id, orgnl_end_to_end_id, orgnl_tx_id, orgnl_uetr, orgnl_message_id, orgnl_seq
+		FROM case_transactions
+		WHERE case_id = ?
+		ORDER BY seq`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	c.Transactions = []CaseTransaction{}
+	for rows.Next() {
+		var t CaseTransaction
+		err := rows.Scan(&t.Seq, &t.CxlId, &t.OrgnlMsgId, &t.OrgnlMsgNmId, &t.OrgnlEndToEndId, &t.OrgnlTxId, &t.OrgnlUETR, &t.Original.MessageID, &t.Original.Seq)
+		if err != nil {
+			return nil, err
+		}
+		c.Transactions = append(c.Transactions, t)
+	}
+	return &c, rows.Err()
+}
+
+// Return pending case cancelling the original transaction, nil when there is none
+func (s *SQLStore) FindPendingCase(original TransactionRef) (*InvestigationCase, error) {
+	var id string
+	err := s.db.QueryRow(`SELECT c.id
+		FROM case_transactions t JOIN cases c ON c.id = t.case_id
+		WHERE t.orgnl_message_id = ? AND t.orgnl_seq = ? AND c.status = ?
+		LIMIT 1`, original.MessageID, original.Seq, caseStatusPending).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.FindCase(id)
+}
+
+// Return a page of transactions matching filter, newest first
+// Amounts are compared as exact decimals, so the amount range is applied while reading rows
+func (s *SQLStore) Search(filter PaymentFilter) (PaymentPage, error) {
+	var page PaymentPage
+	var where []string
+	var args []interface{}
+
+	add := func(condition string, values ...interface{}) {
+		where = append(where, condition)
+		args = append(args, values...)
+	}
+	if filter.From != "" {
+		add("t.settlement_date >= ?", filter.From)
+	}
+	if filter.To != "" {
+		add("t.settlement_date <= ?", filter.To)
+	}
+	if !filter.ReceivedFrom.IsZero() {
+		add("t.received_at >= ?", filter.ReceivedFrom.UTC().Format(sqlTimeFormat))
+	}
+	if !filter.ReceivedTo.IsZero() {
+		add("t.received_at < ?", filter.ReceivedTo.UTC().Format(sqlTimeFormat))
+	}
+	if filter.Agent != "" {
+		add("(t.dbtr_agt = ? OR t.cdtr_agt = ?)", filter.Agent, filter.Agent)
+	}
+	if filter.DbtrAgt != "" {
+		add("t.dbtr_agt = ?", filter.DbtrAgt)
+	}
+	if filter.CdtrAgt != "" {
+		add("t.cdtr_agt = ?", filter.CdtrAgt)
+	}
+	if filter.Account != "" {
+		add("(t.dbtr_acct = ? OR t.cdtr_acct = ?)", filter.Account, filter.Account)
+	}
+	if filter.Currency != "" {
+		add("t.currency = ?", filter.Currency)
+	}
+	if filter.Status != "" {
+		add("t.status = ?", filter.Status)
+	}
+	if filter.State != "" {
+		add("t.state = ?", filter.State)
+	}
+	if filter.ClientIP != "" {
+		// getIP keeps the port of RemoteAddr, a bare host matches any port
+		host := likeEscaper.Replace(filter.ClientIP)
+		add(`(m.client_ip = ? OR m.client_ip LIKE ? ESCAPE '\' OR m.client_ip LIKE ? ESCAPE '\')`, filter.ClientIP, host+":%", "["+host+"]:%")
+	}
+	if c := filter.Cursor; c != nil {
+		add("(t.received_at, t.message_id, t.seq) < (?, ?, ?)", c.ReceivedAt, c.MessageID, c.Seq)
+	}
+
+	query := `SELECT ` + paymentColumns + `
+		FROM transactions t JOIN messages m ON m.id = t.message_id`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY t.received_at DESC, t.message_id DESC, t.seq DESC"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return page, err
+	}
+	defer rows.Close()
+
+	page.Payments = []PaymentRecord{}
+	var last PaymentCursor
+	for rows.Next() {
+		payment, receivedAt, err := scanPayment(rows.Scan)
+		if err != nil {
+			return page, err
+		}
+		if !filter.matchAmount(payment.Amount) {
+			continue
+		}
+
+		// one more match than the page holds means there is a next page
+		if len(page.Payments) == filter.Limit {
+			page.NextCursor = last.String()
+			break
+		}
+		last = PaymentCursor{ReceivedAt: receivedAt, MessageID: payment.MessageID, Seq: payment.Seq}
+		page.Payments = append(page.Payments, payment)
+	}
+	return page, rows.Err()
+}
+
+// Return response cached for Idempotency-Key in scope, nil when there is none since the given time
+func (s *SQLStore) GetResponse(scope string, key string, since time.Time) (*CachedResponse, error) {
+	response := CachedResponse{Scope: scope, Key: key}
+	var createdAt string
+	err := s.db.QueryRow(`SELECT body_digest, status_code, content_type, body, created_at
+		FROM idempotency_keys
+		WHERE scope = ? AND key = ? AND created_at >= ?`, scope, key, since.UTC().Format(sqlTimeFormat)).
+		Scan(&response.BodyDigest, &response.StatusCode, &response.ContentType, &response.Body, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response.CreatedAt, err = time.Parse(sqlTimeFormat, createdAt)
+	return &response, err
+}
+
+// Cache response of Idempotency-Key, replacing an expired one
+func (s *SQLStore) PutResponse(response *CachedResponse) error {
+	_, err := s.db.Exec(`INSERT OR REPLACE INTO idempotency_keys (scope, key, body_digest, status_code, content_type, body, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		response.Scope, response.Key, response.BodyDigest, response.StatusCode, response.ContentType, response.Body, response.CreatedAt.UTC().Format(sqlTimeFormat))
+	return err
+}
+
+// Escape LIKE wildcards
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// transactionRow holds the queryable columns of a single transaction
+type transactionRow struct {
+	Seq            int    // index of the transaction in its message
+	Location       string // location of the transaction, for duplicate issues
+	EndToEndId     string
+	TxId           string
+	UETR           string
+	DbtrAgt        string
+	CdtrAgt        string
+	DbtrAcct       string
+	CdtrAcct       string
+	Amount         string
+	Currency       string
+	SettlementDate string
+	UniqueIds      []uniqueId
+}
+
+// Extract queryable columns of every transaction in msg
+func transactionRows(msg *StoredMessage) []transactionRow {
+	if msg.Message == nil {
+		return nil
+	}
+	_, txs := summarize(msg.Message.Document)
+
+	rows := make([]transactionRow, 0, len(txs))
+	for _, tx := range txs {
+		row := transactionRow{
+			Seq:        tx.Seq,
+			Location:   tx.Location,
+			EndToEndId: textValue(tx.EndToEndId),
+			TxId:       textValue(tx.TxId),
+			DbtrAgt:    agentId(tx.DbtrAgt),
+			CdtrAgt:    agentId(tx.CdtrAgt),
+			DbtrAcct:   accountId(tx.DbtrAcct),
+			CdtrAcct:   accountId(tx.CdtrAcct),
+			UniqueIds:  tx.UniqueIds,
+		}
+		if tx.UETR != nil {
+			row.UETR = string(*tx.UETR)
+		}
+		if amount := tx.Amount; amount != nil {
+			row.Amount = amount.Value.String()
+			if amount.Ccy != nil {
+				row.Currency = string(*amount.Ccy)
+			}
+		}
+		if tx.SttlmDt != nil {
+			row.SettlementDate = time.Time(*tx.SttlmDt).Format("2006-01-02")
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Return agent identifier: BICFI, clearing system member id, LEI or other id, whichever comes first
+func agentId(agent *BranchAndFinancialInstitutionIdentification6) string {
+	if agent == nil || agent.FinInstnId == nil {
+		return ""
+	}
+	id := agent.FinInstnId
+	switch {
+	case id.BICFI != nil:
+		return string(*id.BICFI)
+	case id.ClrSysMmbId != nil && id.ClrSysMmbId.MmbId != nil:
+		return string(*id.ClrSysMmbId.MmbId)
+	case id.LEI != nil:
+		return string(*id.LEI)
+	case id.Othr != nil:
+		return textValue(id.Othr.Id)
+	}
+	return ""
+}
+
+// Return identifier of party: its agent identifier, or AnyBIC or LEI of an organisation
+func partyId(party *Party44Choice) string {
+	switch {
+	case party == nil:
+		return ""
+	case party.FIId != nil:
+		return agentId(party.FIId)
+	case party.OrgId != nil && party.OrgId.Id != nil && party.OrgId.Id.OrgId != nil:
+		if id := party.OrgId.Id.OrgId; id.AnyBIC != nil {
+			return string(*id.AnyBIC)
+		} else if id.LEI != nil {
+			return string(*id.LEI)
+		}
+	}
+	return ""
+}
+
+// Return account identifier: IBAN, other id or proxy id, whichever comes first
+func accountId(account *CashAccount38) string {
+	if account == nil {
+		return ""
+	}
+	switch id := account.Id; {
+	case id != nil && id.IBAN != nil:
+		return string(*id.IBAN)
+	case id != nil && id.Othr != nil && id.Othr.Id != nil:
+		return string(*id.Othr.Id)
+	case account.Prxy != nil && account.Prxy.Id != nil:
+		return string(*account.Prxy.Id)
+	}
+	return ""
+}
+
+// Return text value or empty string when missing
+func textValue(text *Max35Text) string {
+	if text == nil {
+		return ""
+	}
+	return string(*text)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Return camt.053 statement of an account for a day: date (YYYY-MM-DD), opening and closing balances are required,
+// ccy defaults to the currency of the account, or else of its latest transaction
+// Entries are the pacs.008 transactions of that currency settled that day and not since returned or cancelled,
+// crediting or debiting the account
+// The statement is refused when the opening balance and the entries do not add up to the closing balance
+func getStatement(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ipReq := getIP(r)
+	log.Printf("[Conn: %v. Time: %v. Elapsed: %.6fs] Received new Statement Request\n", ipReq, time.Now().Format("15:04:05"), time.Since(start).Seconds())
+
+	var response Response
+
+	format := acceptFormat(r)
+	id := mux.Vars(r)["account"]
+	query := r.URL.Query()
+
+	date, err := time.Parse("2006-01-02", query.Get("date"))
+	if err != nil {
+		response.Message = "date must be a YYYY-MM-DD date"
+	}
+	balances := make(map[string]Decimal)
+	for _, name := range []string{"opening", "closing"} {
+		if response.Message != "" {
+			break
+		}
+		balance, err := ParseDecimal(query.Get(name))
+		if err == nil {
+			err = checkAmount(balance.Abs())
+		}
+		if err != nil {
+			response.Message = fmt.Sprintf("%s balance: %s", name, err.Error())
+		}
+		balances[name] = balance
+	}
+	if response.Message != "" {
+		response.Status = statusRejected
+		log.Print(response.Message)
+		responseFormatter(w, format, response, http.StatusBadRequest)
+		return
+	}
+
+	loader := newTransactionLoader()
+	account, ok := loadAccount(w, format, loader, id, strings.ToUpper(query.Get("ccy")))
+	if !ok {
+		return
+	}
+
+	var entries []StatementEntry
+	records, err := finder.FindStatementEntries(id, account.Currency, date.Format("2006-01-02"))
+	for i := 0; err == nil && i < len(records); i++ {
+		var tx *CreditTransferTransaction43
+		if tx, err = loader.load(records[i]); err == nil {
+			entries = append(entries, accountEntries(id, records[i], tx, date)...)
+		}
+	}
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = "Error reading transactions"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return
+	}
+
+	if expected := balances["opening"].Add(entriesNet(entries)); expected.Cmp(balances["closing"]) != 0 {
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Closing balance %s does not match opening balance %s and %d entries, %s expected",
+			balances["closing"], balances["opening"], len(entries), expected)
+		log.Print(response.Message)
+		responseFormatter(w, format, response, http.StatusUnprocessableEntity)
+		return
+	}
+
+	statement := NewStatement(account, date, balances["opening"], balances["closing"], entries)
+	log.Printf("Statement %s of account %s for %s holds %d entries", statement.AppHdr.BizMsgIdr, id, date.Format("2006-01-02"), len(entries))
+	writeBusMsg(w, format, statement, http.StatusOK)
+}
+
+// Return account id as it appeared in its latest transaction, with currency ccy or else its own currency,
+// writing the error response when that fails
+func loadAccount(w http.ResponseWriter, format string, loader *transactionLoader, id string, ccy string) (StatementAccount, bool) {
+	var response Response
+
+	record, err := finder.FindAccount(id)
+	if err == nil && record == nil {
+		response.Status = statusRejected
+		response.Message = fmt.Sprintf("Account %s not found", id)
+		log.Print(response.Message)
+		responseFormatter(w, format, response, http.StatusNotFound)
+		return StatementAccount{}, false
+	}
+
+	var tx *CreditTransferTransaction43
+	if err == nil {
+		tx, err = loader.load(*record)
+	}
+	if err != nil {
+		response.Status = statusRejected
+		response.Message = "Error reading account"
+		log.Printf("%s: %s", response.Message, err.Error())
+		responseFormatter(w, format, response, http.StatusInternalServerError)
+		return StatementAccount{}, false
+	}
+
+	side := CreditDebitCode("CRDT")
+	if record.CdtrAcct != id {
+		side = "DBIT"
+	}
+	account := transactionAccount(tx, side)
+	if ccy != "" {
+		account.Currency = ccy
+	}
+	return account, true
+}
+
+// Return entries a transaction makes on account: a credit when it is the creditor account, a debit when it is the
+// debtor account, both when the transfer is between two sides of the same account
+func accountEntries(account string, record PaymentRecord, tx *CreditTransferTransaction43, date time.Time) []StatementEntry {
+	var entries []StatementEntry
+	if record.CdtrAcct == account {
+		entries = append(entries, StatementEntry{MsgId: record.MsgId, Tx: tx, CdtDbtInd: "CRDT", BookingDate: date})
+	}
+	if record.DbtrAcct == account {
+		entries = append(entries, StatementEntry{MsgId: record.MsgId, Tx: tx, CdtDbtInd: "DBIT", BookingDate: date})
+	}
+	return entries
+}
+
+// transactionLoader reads stored pacs.008 transactions back from their messages, decoding each message once
+type transactionLoader struct {
+	messages map[string]*BusMsg
+}
+
+func newTransactionLoader() *transactionLoader {
+	return &transactionLoader{messages: make(map[string]*BusMsg)}
+}
+
+// Return the credit transfer transaction record was saved from
+func (l *transactionLoader) load(record PaymentRecord) (*CreditTransferTransaction43, error) {
+	msg, ok := l.messages[record.MessageID]
+	if !ok {
+		stored, err := finder.Find(record.MessageID)
+		if err != nil {
+			return nil, err
+		}
+		request, _, _, err := decodeMessage(stored.Payload, stored.Format)
+		if err != nil {
+			return nil, err
+		}
+		msg = &request.BusMsg
+		l.messages[record.MessageID] = msg
+	}
+
+	transfer := msg.Document.FIToFICstmrCdtTrf
+	if transfer == nil || record.Seq >= len(transfer.CdtTrfTxInf) || transfer.CdtTrfTxInf[record.Seq] == nil {
+		return nil, fmt.Errorf("message %s has no credit transfer transaction %d", record.MessageID, record.Seq)
+	}
+	return transfer.CdtTrfTxInf[record.Seq], nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Statements hold the transactions settled on the account that day, unless since returned, and are refused when
+// the balances do not add up
+func TestGetStatement(t *testing.T) {
+	tests := []struct {
+		name        string
+		settle      bool // report the pacs.008 sample settled with the pacs.002 sample
+		returned    bool // then return it in full
+		query       string
+		wantCode    int
+		wantEntries []CreditDebitCode
+	}{
+		{
+			name:        "credited",
+			settle:      true,
+			query:       "987654321?date=2021-03-01&opening=100.00&closing=1334.56",
+			wantCode:    http.StatusOK,
+			wantEntries: []CreditDebitCode{"CRDT"},
+		},
+		{
+			name:        "debited",
+			settle:      true,
+			query:       "123456789?date=2021-03-01&opening=2000&closing=765.44",
+			wantCode:    http.StatusOK,
+			wantEntries: []CreditDebitCode{"DBIT"},
+		},
+		{
+			name:        "overdrawn",
+			settle:      true,
+			query:       "123456789?date=2021-03-01&opening=0&closing=-1234.56",
+			wantCode:    http.StatusOK,
+			wantEntries: []CreditDebitCode{"DBIT"},
+		},
+		{
+			name:     "closing balance not matching",
+			settle:   true,
+			query:    "987654321?date=2021-03-01&opening=100.00&closing=100.00",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "not settled",
+			query:    "987654321?date=2021-03-01&opening=100.00&closing=100.00",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "returned after settlement",
+			settle:   true,
+			returned: true,
+			query:    "987654321?date=2021-03-01&opening=100.00&closing=100.00",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "another day",
+			settle:   true,
+			query:    "987654321?date=2021-03-02&opening=100.00&closing=100.00",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "another currency",
+			settle:   true,
+			query:    "987654321?date=2021-03-01&opening=100.00&closing=100.00&ccy=usd",
+			wantCode: http.StatusOK,
+		},
+		{name: "unknown account", query: "555555555?date=2021-03-01&opening=0&closing=0", wantCode: http.StatusNotFound},
+		{name: "missing date", query: "987654321?opening=0&closing=0", wantCode: http.StatusBadRequest},
+		{name: "invalid balance", query: "987654321?date=2021-03-01&opening=1.2.3&closing=0", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestServer(t)
+			if code, response := postIso(t, handler, "/iso20022", samplePayload(t, func(*BusMsg) {}), nil); code != http.StatusOK {
+				t.Fatalf("pacs.008 gives %d %+v", code, response)
+			}
+			if tt.settle {
+				if code, response := postIso(t, handler, "/iso20022", messagePayload(t, "pacs002.json", nil), nil); code != http.StatusOK {
+					t.Fatalf("pacs.002 gives %d %+v", code, response)
+				}
+			}
+			if tt.returned {
+				if code, response := postIso(t, handler, "/iso20022/return", messagePayload(t, "pacs004.json", nil), nil); code != http.StatusOK {
+					t.Fatalf("pacs.004 gives %d %+v", code, response)
+				}
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/statements/"+tt.query, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("got %d %s, want %d", w.Code, w.Body, tt.wantCode)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			statement, _, _, err := decodeMessage(w.Body.Bytes(), formatJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stmt := statement.BusMsg.Document.BkToCstmrStmt
+			if statement.BusMsg.AppHdr.MsgDefIdr != "camt.053.001.08" || stmt == nil || len(stmt.Stmt) != 1 {
+				t.Fatalf("got %s %+v, want a camt.053.001.08 holding one statement", statement.BusMsg.AppHdr.MsgDefIdr, stmt)
+			}
+			var got []CreditDebitCode
+			for _, entry := range stmt.Stmt[0].Ntry {
+				got = append(got, *entry.CdtDbtInd)
+			}
+			if len(got) != len(tt.wantEntries) {
+				t.Fatalf("got entries %v, want %v", got, tt.wantEntries)
+			}
+			for i := range got {
+				if got[i] != tt.wantEntries[i] {
+					t.Errorf("got entries %v, want %v", got, tt.wantEntries)
+				}
+			}
+		})
+	}
+}
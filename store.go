@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// StoredMessage is a received message together with its receipt metadata
+type StoredMessage struct {
+	ID         string
+	BizMsgIdr  string
+	MsgDefIdr  string
+	MsgId      string
+	Sender     string // agent of AppHdr/Fr, MsgId is unique per sender
+	ClientIP   string
+	Format     string
+	Status     string
+	ReceivedAt time.Time
+	Payload    []byte  // request body as received
+	Document   []byte  // parsed Document, indented in Format
+	Digest     string  // digest of parsed Document, equal for replays in either format
+	Message    *BusMsg // parsed message, used to extract queryable fields
+
+	// Originals links transactions of a return to the transactions they return, by index
+	Originals map[int]TransactionRef
+
+	// Case is opened by the cancellation request saving it, or resolved by the resolution saving it
+	Case *InvestigationCase
+
+	// Transitions are the lifecycle state changes msg causes, to its own transactions or to earlier ones
+	Transitions []Transition
+
+	// Notifications are the camt.054 generated for an accepted transfer, saved with it
+	// Notification is set on each of them, linking it to the transfer
+	Notifications []*StoredMessage
+	Notification  *Notification
+}
+
+// TransactionRef points at a stored transaction: its message and its index in that message
+type TransactionRef struct {
+	MessageID string `xml:"MessageId" json:"MessageId"`
+	Seq       int    `xml:"Seq" json:"Seq"`
+}
+
+// MessageStore persists received messages
+// Save fills in msg.ID, errors are returned to the caller instead of stopping the server
+type MessageStore interface {
+	Save(msg *StoredMessage) error
+}
+
+// MessageFinder looks up a stored message by BizMsgIdr, MsgId, EndToEndId or UETR
+// ErrMessageNotFound is returned when no message matches
+// FindMessage looks up a message by its MsgId only, among messages of one type, e.g. pacs.008
+// Search lists stored transactions page by page
+// FindDuplicates reports identifiers of msg already used by messages received since the given time
+// FindTransaction and FindReturns look up the pacs.008 transaction a return refers to and its earlier returns
+// FindCase returns ErrCaseNotFound when there is no such case, FindPendingCase nil when the transaction has no pending cancellation
+// FindPayment returns a stored transaction, nil when there is none, FindLifecycle the state history of the transactions of a message
+// FindStatementEntries and FindAccount look up the settled transactions of an account and its latest transaction, for statements
+// FindNotifications lists the camt.054 notifications generated for accepted transfers
+type MessageFinder interface {
+	Find(id string) (*StoredMessage, error)
+	FindMessage(msgId string, name string) (*StoredMessage, error)
+	Search(filter PaymentFilter) (PaymentPage, error)
+	FindDuplicates(msg *StoredMessage, since time.Time) ([]DuplicateId, error)
+	FindTransaction(msgId string, endToEndId string, uetr string) (*PaymentRecord, error)
+	FindReturns(original TransactionRef) ([]PaymentRecord, error)
+	FindCase(id string) (*InvestigationCase, error)
+	FindPendingCase(original TransactionRef) (*InvestigationCase, error)
+	FindPayment(ref TransactionRef) (*PaymentRecord, error)
+	FindLifecycle(messageID string) ([]TransactionLifecycle, error)
+	FindStatementEntries(account string, currency string, date string) ([]PaymentRecord, error)
+	FindAccount(account string) (*PaymentRecord, error)
+	FindNotifications(filter NotificationFilter) (NotificationPage, error)
+}
+
+var ErrMessageNotFound = errors.New("message not found")
+
+// MessageRemover is implemented by stores able to undo a Save, when a later store of a MultiStore fails
+type MessageRemover interface {
+	Remove(msg *StoredMessage) error
+}
+
+// MultiStore saves every message in each of its stores, in order
+// The first store sets msg.ID, later stores reuse it
+// When a store fails, the message is removed again from the stores that already saved it
+type MultiStore []MessageStore
+
+func (stores MultiStore) Save(msg *StoredMessage) error {
+	for i, s := range stores {
+		if err := s.Save(msg); err != nil {
+			id := msg.ID
+			for _, saved := range stores[:i] {
+				remover, ok := saved.(MessageRemover)
+				if !ok {
+					continue
+				}
+				if removeErr := remover.Remove(msg); removeErr != nil {
+					log.Printf("Error removing message %s: %s", id, removeErr.Error())
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// FileStore writes each message Document to its own file in Dir
+// Files are named <MsgId or BizMsgIdr>_<UTC receipt time>.<format>
+type FileStore struct {
+	Dir string
+}
+
+// Create FileStore, making its directory when missing
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{Dir: dir}, nil
+}
+
+// characters not allowed in file names on common filesystems
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// Return message key, MsgId is preferred as BizMsgIdr is not unique across senders
+func messageKey(msg *StoredMessage) string {
+	key := msg.MsgId
+	if key == "" {
+		key = msg.BizMsgIdr
+	}
+	if key == "" {
+		key = "unknown"
+	}
+	return unsafeFileChars.ReplaceAllString(key, "_")
+}
+
+// Return message ID without collision counter: <key>_<UTC receipt time>
+func messageID(msg *StoredMessage) string {
+	return fmt.Sprintf("%s_%s", messageKey(msg), msg.ReceivedAt.UTC().Format("20060102T150405.000000000Z"))
+}
+
+// Save Document, and the notifications generated with it, each in its own file
+func (s *FileStore) Save(msg *StoredMessage) error {
+	if err := s.saveFile(msg); err != nil {
+		return err
+	}
+	for _, notification := range msg.Notifications {
+		if err := s.saveFile(notification); err != nil {
+			s.Remove(msg)
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove the files saved for msg and its notifications, clearing their IDs
+func (s *FileStore) Remove(msg *StoredMessage) error {
+	var err error
+	for _, saved := range append([]*StoredMessage{msg}, msg.Notifications...) {
+		if saved.ID == "" {
+			continue
+		}
+		if removeErr := os.Remove(filepath.Join(s.Dir, saved.ID+"."+saved.Format)); removeErr != nil && err == nil {
+			err = removeErr
+		}
+		saved.ID = ""
+	}
+	return err
+}
+
+// Save Document atomically: write a temporary file, then link it under its final name
+// Linking never replaces an existing file, a counter is appended on collision
+func (s *FileStore) saveFile(msg *StoredMessage) error {
+	tmp, err := ioutil.TempFile(s.Dir, ".tmp-")
+	if err != nil {
+		return fmt.Errorf("Failed creating file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(msg.Document); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("Failed writing to file: %w", err)
+	}
+
+	base := messageID(msg)
+	id := base
+	for i := 1; ; i++ {
+		fileName := filepath.Join(s.Dir, id+"."+msg.Format)
+		err = os.Link(tmp.Name(), fileName)
+		if err == nil {
+			msg.ID = id
+			return nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("Failed creating file: %w", err)
+		}
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+)
+
+// groupSummary holds group header elements shared by every message type
+type groupSummary struct {
+	MsgId    *Max35Text
+	CreDtTm  *ISODateTime
+	NbOfTxs  *Max15NumericText
+	CtrlSum  *DecimalNumber
+	InstgAgt *BranchAndFinancialInstitutionIdentification6
+	InstdAgt *BranchAndFinancialInstitutionIdentification6
+}
+
+// transactionSummary holds the identifiers, agents, amount and date of a transaction, whatever message it is in
+type transactionSummary struct {
+	Seq        int    // index of the transaction in its message
+	Location   string // e.g. /Document/FIToFICstmrCdtTrf/CdtTrfTxInf[0]
+	InstrId    *Max35Text
+	EndToEndId *Max35Text
+	TxId       *Max35Text
+	UETR       *UUIDv4Identifier
+	DbtrAgt    *BranchAndFinancialInstitutionIdentification6
+	CdtrAgt    *BranchAndFinancialInstitutionIdentification6
+	DbtrAcct   *CashAccount38
+	CdtrAcct   *CashAccount38
+	Amount     *ActiveCurrencyAndAmount
+	SttlmDt    *ISODate
+	UniqueIds  []uniqueId // identifiers no other message may reuse
+}
+
+// uniqueId is an identifier element of a transaction, Element is relative to the transaction location
+type uniqueId struct {
+	Element string
+	Value   string
+}
+
+// Return group header and transactions of the message held by doc
+// Transaction settlement date falls back to the group header date
+func summarize(doc Document) (*groupSummary, []transactionSummary) {
+	var txs []transactionSummary
+	switch {
+	case doc.FIToFICstmrCdtTrf != nil:
+		transfer := doc.FIToFICstmrCdtTrf
+		var group *groupSummary
+		var groupDate *ISODate
+		if header := transfer.GrpHdr; header != nil {
+			group = &groupSummary{header.MsgId, header.CreDtTm, header.NbOfTxs, header.CtrlSum, header.InstgAgt, header.InstdAgt}
+			groupDate = header.IntrBkSttlmDt
+		}
+		for i, tx := range transfer.CdtTrfTxInf {
+			if tx == nil {
+				continue
+			}
+			summary := transactionSummary{
+				Seq:      i,
+				Location: fmt.Sprintf("/Document/FIToFICstmrCdtTrf/CdtTrfTxInf[%d]", i),
+				DbtrAgt:  tx.DbtrAgt,
+				CdtrAgt:  tx.CdtrAgt,
+				DbtrAcct: tx.DbtrAcct,
+				CdtrAcct: tx.CdtrAcct,
+				Amount:   tx.IntrBkSttlmAmt,
+				SttlmDt:  tx.IntrBkSttlmDt,
+			}
+			summary.addPaymentId(tx.PmtId)
+			if summary.SttlmDt == nil {
+				summary.SttlmDt = groupDate
+			}
+			txs = append(txs, summary)
+		}
+		return group, txs
+
+	case doc.FICdtTrf != nil:
+		transfer := doc.FICdtTrf
+		var group *groupSummary
+		var groupDate *ISODate
+		if header := transfer.GrpHdr; header != nil {
+			group = &groupSummary{header.MsgId, header.CreDtTm, header.NbOfTxs, header.CtrlSum, header.InstgAgt, header.InstdAgt}
+			groupDate = header.IntrBkSttlmDt
+		}
+		for i, tx := range transfer.CdtTrfTxInf {
+			if tx == nil {
+				continue
+			}
+			// debtor and creditor are banks themselves, their agents are only set when they do not settle directly
+			summary := transactionSummary{
+				Seq:      i,
+				Location: fmt.Sprintf("/Document/FICdtTrf/CdtTrfTxInf[%d]", i),
+				DbtrAgt:  tx.DbtrAgt,
+				CdtrAgt:  tx.CdtrAgt,
+				DbtrAcct: tx.DbtrAcct,
+				CdtrAcct: tx.CdtrAcct,
+				Amount:   tx.IntrBkSttlmAmt,
+				SttlmDt:  tx.IntrBkSttlmDt,
+			}
+			if summary.DbtrAgt == nil {
+				summary.DbtrAgt = tx.Dbtr
+			}
+			if summary.CdtrAgt == nil {
+				summary.CdtrAgt = tx.Cdtr
+			}
+			summary.addPaymentId(tx.PmtId)
+			if summary.SttlmDt == nil {
+				summary.SttlmDt = groupDate
+			}
+			txs = append(txs, summary)
+		}
+		return group, txs
+
+	case doc.PmtRtr != nil:
+		ret := doc.PmtRtr
+		var group *groupSummary
+		var groupDate *ISODate
+		if header := ret.GrpHdr; header != nil {
+			group = &groupSummary{header.MsgId, header.CreDtTm, header.NbOfTxs, header.CtrlSum, header.InstgAgt, header.InstdAgt}
+			groupDate = header.IntrBkSttlmDt
+		}
+		for i, tx := range ret.TxInf {
+			if tx == nil {
+				continue
+			}
+			// a return refers to the original EndToEndId and UETR, only RtrId is its own
+			summary := transactionSummary{
+				Seq:        i,
+				Location:   fmt.Sprintf("/Document/PmtRtr/TxInf[%d]", i),
+				InstrId:    tx.OrgnlInstrId,
+				EndToEndId: tx.OrgnlEndToEndId,
+				TxId:       tx.RtrId,
+				UETR:       tx.OrgnlUETR,
+				Amount:     tx.RtrdIntrBkSttlmAmt,
+				SttlmDt:    tx.IntrBkSttlmDt,
+			}
+			summary.addUniqueId("RtrId", textValue(tx.RtrId))
+			if summary.SttlmDt == nil {
+				summary.SttlmDt = groupDate
+			}
+			txs = append(txs, summary)
+		}
+		return group, txs
+
+	case doc.FIToFIPmtStsRpt != nil && doc.FIToFIPmtStsRpt.GrpHdr != nil:
+		header := doc.FIToFIPmtStsRpt.GrpHdr
+		return &groupSummary{MsgId: header.MsgId, CreDtTm: header.CreDtTm, InstgAgt: header.InstgAgt, InstdAgt: header.InstdAgt}, nil
+
+	case doc.FIToFIPmtStsReq != nil && doc.FIToFIPmtStsReq.GrpHdr != nil:
+		header := doc.FIToFIPmtStsReq.GrpHdr
+		return &groupSummary{MsgId: header.MsgId, CreDtTm: header.CreDtTm, InstgAgt: header.InstgAgt, InstdAgt: header.InstdAgt}, nil
+
+	// investigations have no transactions of their own, their assignment identifies the message
+	case doc.FIToFIPmtCxlReq != nil && doc.FIToFIPmtCxlReq.Assgnmt != nil:
+		return assignmentSummary(doc.FIToFIPmtCxlReq.Assgnmt), nil
+
+	case doc.RsltnOfInvstgtn != nil && doc.RsltnOfInvstgtn.Assgnmt != nil:
+		return assignmentSummary(doc.RsltnOfInvstgtn.Assgnmt), nil
+	}
+	return nil, nil
+}
+
+// Return group summary of a case assignment, assigner and assignee agents instruct and are instructed
+func assignmentSummary(assignment *CaseAssignment5) *groupSummary {
+	group := &groupSummary{MsgId: assignment.Id, CreDtTm: assignment.CreDtTm}
+	if assignment.Assgnr != nil {
+		group.InstgAgt = assignment.Assgnr.Agt
+	}
+	if assignment.Assgne != nil {
+		group.InstdAgt = assignment.Assgne.Agt
+	}
+	return group
+}
+
+// Return location of the element summarized as MsgId of the message held in root
+func msgIdLocation(root string) string {
+	switch root {
+	case "FIToFIPmtCxlReq", "RsltnOfInvstgtn":
+		return "/Document/" + root + "/Assgnmt/Id"
+	}
+	return "/Document/" + root + "/GrpHdr/MsgId"
+}
+
+// Add identifier when it is set
+func (summary *transactionSummary) addUniqueId(element string, value string) {
+	if value != "" {
+		summary.UniqueIds = append(summary.UniqueIds, uniqueId{element, value})
+	}
+}
+
+// Set identifiers of a credit transfer, TxId and UETR are unique
+// EndToEndId is not: the debtor chooses it, other senders may use the same value and NOTPROVIDED stands for none
+func (summary *transactionSummary) addPaymentId(id *PaymentIdentification13) {
+	if id == nil {
+		return
+	}
+	summary.InstrId, summary.EndToEndId, summary.TxId, summary.UETR = id.InstrId, id.EndToEndId, id.TxId, id.UETR
+	summary.addUniqueId("PmtId/TxId", textValue(id.TxId))
+	if id.UETR != nil {
+		summary.addUniqueId("PmtId/UETR", string(*id.UETR))
+	}
+}
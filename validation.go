@@ -0,0 +1,551 @@
+package main
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ISO 20022 status reason codes (ExternalStatusReason1Code) reported for issues
+const (
+	reasonInvalidFileFormat    = "FF01"
+	reasonInvalidDate          = "DT01"
+	reasonIncorrectContent     = "CH16"
+	reasonElementNotAllowed    = "CH17"
+	reasonMissingElement       = "CH21"
+	reasonInvalidCurrency      = "AM03"
+	reasonIncorrectCurrency    = "CURR"
+	reasonInvalidAmount        = "AM12"
+	reasonInvalidControlSum    = "AM10"
+	reasonInvalidNumberOfTxs   = "AM18"
+	reasonInvalidBankId        = "RC01"
+	reasonInvalidAccountNumber = "AC01"
+	reasonInvalidCountry       = "BE09"
+	reasonDuplication          = "AM05"
+	reasonDuplicateMessageId   = "DU01"
+	reasonDuplicateTransaction = "DU03"
+	reasonWrongAmount          = "AM09"
+	reasonUnknownOriginal      = "NOOR"
+	reasonNarrative            = "NARR"
+)
+
+// Issue severities, only errors cause the message to be rejected
+const (
+	severityError   = "ERROR"
+	severityWarning = "WARNING"
+)
+
+// ValidationError describes a value violating its ISO 20022 definition
+// Location points at the offending element, e.g. /Document/FIToFICstmrCdtTrf/CdtTrfTxInf[0]/ChrgBr
+type ValidationError struct {
+	Location string `xml:"Location" json:"Location"`
+	Code     string `xml:"Code" json:"Code"`
+	Severity string `xml:"Severity" json:"Severity"`
+	Message  string `xml:"Message" json:"Message"`
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Location, e.Message)
+}
+
+// ValidationErrors is the list of violations found in a message
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	return strings.Join(e.Strings(), "; ")
+}
+
+// Strings return every violation as "location: message"
+func (e ValidationErrors) Strings() []string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return messages
+}
+
+// Return true when any issue is severe enough to reject the message
+func (e ValidationErrors) HasErrors() bool {
+	for _, err := range e {
+		if err.Severity == severityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Translate a JSON/XML decoding error into issues without leaking Go type names
+func decodeIssues(err error) ValidationErrors {
+	issue := ValidationError{Location: "/", Code: reasonIncorrectContent, Severity: severityError, Message: err.Error()}
+
+	var jsonSyntax *json.SyntaxError
+	var jsonType *json.UnmarshalTypeError
+	var xmlSyntax *xml.SyntaxError
+	var timeParse *time.ParseError
+	var numError *strconv.NumError
+
+	switch {
+	case errors.As(err, &jsonSyntax):
+		issue.Code = reasonInvalidFileFormat
+		issue.Message = fmt.Sprintf("invalid JSON at offset %d: %s", jsonSyntax.Offset, jsonSyntax.Error())
+	case errors.As(err, &jsonType):
+		issue.Location = jsonLocation(jsonType.Field)
+		issue.Message = fmt.Sprintf("expected %s, got JSON %s", jsonKind(jsonType.Type), jsonType.Value)
+	case errors.As(err, &xmlSyntax):
+		issue.Code = reasonInvalidFileFormat
+		issue.Message = fmt.Sprintf("invalid XML at line %d: %s", xmlSyntax.Line, xmlSyntax.Msg)
+	case errors.As(err, &timeParse):
+		issue.Code = reasonInvalidDate
+		issue.Message = fmt.Sprintf("invalid date/time %q", timeParse.Value)
+	case errors.As(err, &numError):
+		issue.Message = fmt.Sprintf("invalid number %q", numError.Num)
+	default:
+		var xmlError xml.UnmarshalError
+		if errors.As(err, &xmlError) {
+			issue.Code = reasonInvalidFileFormat
+		}
+	}
+
+	var located *decodeError
+	if errors.As(err, &located) {
+		issue.Location = located.Location
+	}
+	return ValidationErrors{issue}
+}
+
+// decodeError is a value of a payload that could not be decoded, Location points at its element
+type decodeError struct {
+	Location string
+	Err      error
+}
+
+func (e *decodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.Err
+}
+
+// Decode payload body into v, a pointer to a BusMsg of any version, from XML or from JSON wrapped in {"BusMsg": ...}
+// Values failing to decode are returned as decodeError, located at their element like validation issues
+func unmarshalPayload(body []byte, format string, v interface{}) error {
+	var err error
+	if format == formatXML {
+		err = xml.Unmarshal(body, v)
+	} else {
+		wrapper := struct {
+			BusMsg interface{} `json:"BusMsg"`
+		}{v}
+		err = json.Unmarshal(body, &wrapper)
+	}
+
+	var jsonSyntax *json.SyntaxError
+	var xmlSyntax *xml.SyntaxError
+	if err == nil || errors.As(err, &jsonSyntax) || errors.As(err, &xmlSyntax) {
+		return err
+	}
+	if location := valueErrorLocation(body, format, reflect.TypeOf(v)); location != "" {
+		return &decodeError{Location: location, Err: err}
+	}
+	return err
+}
+
+// Return location of the first value of payload body not decoding into t, the BusMsg type, empty when there is none
+func valueErrorLocation(body []byte, format string, t reflect.Type) string {
+	if format == formatXML {
+		decoder := xml.NewDecoder(bytes.NewReader(body))
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return ""
+			}
+			if start, ok := token.(xml.StartElement); ok {
+				location, _ := xmlErrorLocation(decoder, start, t, "")
+				return location
+			}
+		}
+	}
+
+	var wrapper struct {
+		BusMsg json.RawMessage `json:"BusMsg"`
+	}
+	if json.Unmarshal(body, &wrapper) != nil {
+		return ""
+	}
+	return jsonErrorLocation(wrapper.BusMsg, t, "")
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Return true when values of t are decoded as a whole, rather than element by element
+func isLeafType(t reflect.Type) bool {
+	switch {
+	case reflect.PtrTo(t).Implements(jsonUnmarshalerType), reflect.PtrTo(t).Implements(textUnmarshalerType):
+		return true
+	case t.Kind() == reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	}
+	return t.Kind() != reflect.Struct
+}
+
+// Return true when field holds the character data of its element, e.g. the amount of an ActiveCurrencyAndAmount
+// Values of such fields are located at the element itself
+func isCharData(field reflect.StructField) bool {
+	return strings.HasSuffix(field.Tag.Get("xml"), ",chardata")
+}
+
+// Return location of the first value of JSON data not decoding into t, empty when there is none
+func jsonErrorLocation(data []byte, t reflect.Type, location string) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if isLeafType(t) {
+		if json.Unmarshal(data, reflect.New(t).Interface()) != nil {
+			return location
+		}
+		return ""
+	}
+
+	if t.Kind() == reflect.Slice {
+		var elements []json.RawMessage
+		if json.Unmarshal(data, &elements) != nil {
+			return location
+		}
+		for i, element := range elements {
+			if found := jsonErrorLocation(element, t.Elem(), fmt.Sprintf("%s[%d]", location, i)); found != "" {
+				return found
+			}
+		}
+		return ""
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return location
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.PkgPath != "" || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		value, ok := fields[name]
+		if !ok {
+			continue
+		}
+		fieldLocation := location + "/" + name
+		if isCharData(field) {
+			fieldLocation = location
+		}
+		if found := jsonErrorLocation(value, field.Type, fieldLocation); found != "" {
+			return found
+		}
+	}
+	return ""
+}
+
+// Return location of the first value of XML element start, read from decoder, not decoding into t,
+// empty when there is none
+// Elements of repeated fields are indexed, as they are by ValidateIso, even when they occur once
+func xmlErrorLocation(decoder *xml.Decoder, start xml.StartElement, t reflect.Type, location string) (string, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if isLeafType(t) {
+		if decoder.DecodeElement(reflect.New(t).Interface(), &start) != nil {
+			return location, nil
+		}
+		return "", nil
+	}
+
+	var charData *reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if isCharData(field) {
+			charData = &field
+		}
+		if !strings.HasSuffix(field.Tag.Get("xml"), ",attr") {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("xml"), ",")[0]
+		for _, attr := range start.Attr {
+			if attr.Name.Local == name && !unmarshalsText(field.Type, []byte(attr.Value)) {
+				return location + "/" + name, nil
+			}
+		}
+	}
+
+	var text []byte
+	count := make(map[string]int)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			field, ok := xmlField(t, token.Name.Local)
+			if !ok {
+				if err := decoder.Skip(); err != nil {
+					return "", err
+				}
+				continue
+			}
+			fieldType, fieldLocation := field.Type, location+"/"+token.Name.Local
+			if fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() != reflect.Uint8 {
+				fieldType, fieldLocation = fieldType.Elem(), fmt.Sprintf("%s[%d]", fieldLocation, count[token.Name.Local])
+				count[token.Name.Local]++
+			}
+			found, err := xmlErrorLocation(decoder, token, fieldType, fieldLocation)
+			if found != "" || err != nil {
+				return found, err
+			}
+		case xml.CharData:
+			text = append(text, token...)
+		case xml.EndElement:
+			if charData != nil && !unmarshalsText(charData.Type, text) {
+				return location, nil
+			}
+			return "", nil
+		}
+	}
+}
+
+// Return field of struct t holding child elements named name
+func xmlField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("xml")
+		if field.PkgPath != "" || strings.Contains(tag, ",") && !strings.HasSuffix(tag, ",omitempty") {
+			continue
+		}
+		if elementName(field) == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// Return true when text decodes into a value of t, only types implementing encoding.TextUnmarshaler can fail
+func unmarshalsText(t reflect.Type, text []byte) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	value, ok := reflect.New(t).Interface().(encoding.TextUnmarshaler)
+	return !ok || value.UnmarshalText(text) == nil
+}
+
+// Convert JSON field path (BusMsg.Document.CdtTrfTxInf.0.PmtId) into a location (/Document/CdtTrfTxInf[0]/PmtId)
+func jsonLocation(field string) string {
+	var location string
+	for _, name := range strings.Split(strings.TrimPrefix(field, "BusMsg."), ".") {
+		if _, err := strconv.Atoi(name); err == nil {
+			location += "[" + name + "]"
+			continue
+		}
+		location += "/" + name
+	}
+	return location
+}
+
+// Return JSON name of the kind of value expected by Go type
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Ptr:
+		return jsonKind(t.Elem())
+	}
+	return "number"
+}
+
+// reasonError is returned by Validate when the violation has a more specific reason code than CH16
+type reasonError struct {
+	Code    string
+	Message string
+}
+
+func (e reasonError) Error() string {
+	return e.Message
+}
+
+// validator is implemented by every type restricted by XSD facets
+type validator interface {
+	Validate() error
+}
+
+var validatorType = reflect.TypeOf((*validator)(nil)).Elem()
+
+// Validate every facet-restricted value in the decoded message, then its business rules
+// Paths are rooted at BusMsg, so they start with /AppHdr or /Document
+func ValidateIso(request Iso20022) ValidationErrors {
+	var errs ValidationErrors
+	errs = append(errs, validateDocument(request.BusMsg)...)
+	walkValidate(reflect.ValueOf(request.BusMsg), "", &errs)
+	errs = append(errs, validateRules(request.BusMsg)...)
+	return errs
+}
+
+// Check business rules of the message type named by AppHdr.MsgDefIdr
+func validateRules(msg BusMsg) ValidationErrors {
+	if t := lookupMessageType(string(msg.AppHdr.MsgDefIdr)); t != nil && t.Validate != nil {
+		return t.Validate(msg)
+	}
+	return nil
+}
+
+// Check Document holds exactly one message root, in the namespace of a version of that root, and AppHdr names that version
+func validateDocument(msg BusMsg) ValidationErrors {
+	var errs ValidationErrors
+	doc := msg.Document
+	roots := 0
+	for _, root := range []bool{doc.FIToFICstmrCdtTrf != nil, doc.FIToFIPmtStsRpt != nil, doc.PmtRtr != nil, doc.FICdtTrf != nil,
+		doc.FIToFIPmtCxlReq != nil, doc.RsltnOfInvstgtn != nil, doc.FIToFIPmtStsReq != nil, doc.BkToCstmrStmt != nil,
+		doc.BkToCstmrDbtCdtNtfctn != nil} {
+		if root {
+			roots++
+		}
+	}
+	if roots != 1 {
+		errs = append(errs, ValidationError{Location: "/Document", Code: reasonInvalidFileFormat, Severity: severityError,
+			Message: fmt.Sprintf("Document must hold exactly one message, found %d", roots)})
+		return errs
+	}
+
+	// namespace and MsgDefIdr must both name a message type held in the root element found
+	element, _ := doc.root()
+	space := doc.XMLName.Space
+	if t := lookupMessageType(namespaceMessage(space)); space != "" && (t == nil || t.Root != element) {
+		errs = append(errs, ValidationError{Location: "/Document", Code: reasonInvalidFileFormat, Severity: severityError,
+			Message: fmt.Sprintf("namespace %q does not match message %s", space, element)})
+	}
+	msgDefIdr := string(msg.AppHdr.MsgDefIdr)
+	if t := lookupMessageType(msgDefIdr); t == nil || t.Root != element {
+		errs = append(errs, ValidationError{Location: "/AppHdr/MsgDefIdr", Code: reasonIncorrectContent, Severity: severityError,
+			Message: fmt.Sprintf("MsgDefIdr %q does not match Document message %s", msgDefIdr, element)})
+	} else if space != "" && namespaceMessage(space) != msgDefIdr {
+		errs = append(errs, ValidationError{Location: "/AppHdr/MsgDefIdr", Code: reasonIncorrectContent, Severity: severityError,
+			Message: fmt.Sprintf("MsgDefIdr %q does not match Document namespace %q", msgDefIdr, space)})
+	}
+	return errs
+}
+
+// Walk value recursively, calling Validate on every value implementing validator
+func walkValidate(v reflect.Value, path string, errs *ValidationErrors) {
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Type().Implements(validatorType) {
+		if err := v.Interface().(validator).Validate(); err != nil {
+			code := reasonIncorrectContent
+			var reason reasonError
+			if errors.As(err, &reason) {
+				code = reason.Code
+			}
+			*errs = append(*errs, ValidationError{Location: path, Code: code, Severity: severityError, Message: err.Error()})
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := elementName(field)
+			if field.PkgPath != "" || name == "" {
+				continue
+			}
+			walkValidate(v.Field(i), path+"/"+name, errs)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			walkValidate(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// Return ISO 20022 element name of struct field, empty if the field is not part of the message
+func elementName(field reflect.StructField) string {
+	if field.Name == "XMLName" {
+		return ""
+	}
+
+	tag := field.Tag.Get("xml")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		// drop namespace, e.g. "urn:... Document"
+		return name[strings.LastIndex(name, " ")+1:]
+	}
+
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
+
+// Check value is between min and max characters long
+func checkLength(value string, min int, max int) error {
+	length := utf8.RuneCountInString(value)
+	if length < min || length > max {
+		return fmt.Errorf("%q must be %d to %d characters long, got %d", value, min, max, length)
+	}
+	return nil
+}
+
+// Check value is one of the enumerated codes
+func checkEnumeration(value string, codes ...string) error {
+	for _, code := range codes {
+		if value == code {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q must be one of %s", value, strings.Join(codes, ", "))
+}
+
+// compiled XSD patterns, shared by concurrent requests
+var patterns sync.Map
+
+// Check the whole value matches XSD pattern
+func checkPattern(value string, pattern string) error {
+	re, ok := patterns.Load(pattern)
+	if !ok {
+		re, _ = patterns.LoadOrStore(pattern, regexp.MustCompile("^(?:"+pattern+")$"))
+	}
+
+	if !re.(*regexp.Regexp).MatchString(value) {
+		return fmt.Errorf("%q must match the pattern %s", value, pattern)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFacets(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   validator
+		wantErr bool
+	}{
+		{"Max35Text", Max35Text("20210301INDOIDJA01012345678"), false},
+		{"Max35Text of 35 characters", Max35Text("12345678901234567890123456789012345"), false},
+		{"Max35Text of 36 characters", Max35Text("123456789012345678901234567890123456"), true},
+		{"Max35Text counts characters, not bytes", Max35Text("ÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄÄ"), false},
+		{"empty Max35Text", Max35Text(""), true},
+		{"Max4Text", Max4Text("ABCD"), false},
+		{"Max4Text too long", Max4Text("ABCDE"), true},
+		{"ChargeBearerType1Code", ChargeBearerType1Code("SLEV"), false},
+		{"unknown ChargeBearerType1Code", ChargeBearerType1Code("XXXX"), true},
+		{"ChargeBearerType1Code is case sensitive", ChargeBearerType1Code("debt"), true},
+		{"CountryCode pattern", CountryCode("id"), true},
+		{"UUIDv4Identifier", UUIDv4Identifier("eb6305c9-1f7f-49de-aed0-16487c27b42d"), false},
+		{"UUIDv4Identifier of another version", UUIDv4Identifier("eb6305c9-1f7f-19de-aed0-16487c27b42d"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.value.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Patterns must match the whole value, as XSD patterns do
+func TestCheckPattern(t *testing.T) {
+	tests := []struct {
+		value   string
+		pattern string
+		wantErr bool
+	}{
+		{"ID", "[A-Z]{2,2}", false},
+		{"IDN", "[A-Z]{2,2}", true},
+		{"xID", "[A-Z]{2,2}", true},
+		{"123", "[0-9]{1,15}", false},
+		{"", "[0-9]{1,15}", true},
+		{"a|b", "a|b", true},
+		{"b", "a|b", false},
+	}
+	for _, tt := range tests {
+		if err := checkPattern(tt.value, tt.pattern); (err != nil) != tt.wantErr {
+			t.Errorf("checkPattern(%q, %q) gives error %v, want error %v", tt.value, tt.pattern, err, tt.wantErr)
+		}
+	}
+}
+
+// Decode sample payload of testdata into the canonical model
+func decodeSample(t *testing.T, name string, format string) Iso20022 {
+	t.Helper()
+	request, _, _, err := decodeMessage(readSample(t, name), format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return request
+}
+
+// Every violation is reported with the location of the element and its reason code
+func TestValidateIso(t *testing.T) {
+	const tx = "/Document/FIToFICstmrCdtTrf/CdtTrfTxInf[0]"
+
+	tests := []struct {
+		name   string
+		change func(msg *BusMsg)
+		want   ValidationErrors // Location and Code only
+	}{
+		{name: "valid", change: func(msg *BusMsg) {}},
+		{
+			name: "enumeration",
+			change: func(msg *BusMsg) {
+				chrgBr := ChargeBearerType1Code("XXXX")
+				msg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0].ChrgBr = &chrgBr
+			},
+			want: ValidationErrors{{Location: tx + "/ChrgBr", Code: reasonIncorrectContent}},
+		},
+		{
+			name: "currency attribute",
+			change: func(msg *BusMsg) {
+				ccy := ActiveCurrencyCode("XXQ")
+				msg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0].IntrBkSttlmAmt.Ccy = &ccy
+			},
+			want: ValidationErrors{{Location: tx + "/IntrBkSttlmAmt/Ccy", Code: reasonInvalidCurrency}},
+		},
+		{
+			name: "every violation",
+			change: func(msg *BusMsg) {
+				msgId := Max35Text("123456789012345678901234567890123456")
+				msg.Document.FIToFICstmrCdtTrf.GrpHdr.MsgId = &msgId
+				bic := BICFIDec2014Identifier("DEUTQQFF")
+				msg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf[0].DbtrAgt.FinInstnId.BICFI = &bic
+			},
+			want: ValidationErrors{
+				{Location: "/Document/FIToFICstmrCdtTrf/GrpHdr/MsgId", Code: reasonIncorrectContent},
+				{Location: tx + "/DbtrAgt/FinInstnId/BICFI", Code: reasonInvalidBankId},
+			},
+		},
+		{
+			name: "MsgDefIdr of another message",
+			change: func(msg *BusMsg) {
+				msg.AppHdr.MsgDefIdr = "pacs.002.001.10"
+			},
+			want: ValidationErrors{{Location: "/AppHdr/MsgDefIdr", Code: reasonIncorrectContent}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := decodeSample(t, "pacs008.json", formatJSON)
+			tt.change(&request.BusMsg)
+
+			issues := ValidateIso(request)
+			for _, issue := range issues {
+				if issue.Severity != severityError {
+					t.Errorf("got %s issue, want only errors: %s", issue.Severity, issue.Error())
+				}
+			}
+			checkIssues(t, issues, tt.want)
+		})
+	}
+}
+
+// Decoding errors are reported with a reason code and, where known, the location of the element
+func TestDecodeIssues(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		format       string
+		wantLocation string
+		wantCode     string
+	}{
+		{"JSON syntax", `{"BusMsg": {`, formatJSON, "/", reasonInvalidFileFormat},
+		{"XML syntax", `<BusMsg><AppHdr></BusMsg>`, formatXML, "/", reasonInvalidFileFormat},
+		{"JSON type", `{"BusMsg": {"AppHdr": {"MsgDefIdr": "pacs.008.001.09"}, "Document": {"FIToFICstmrCdtTrf": {"GrpHdr": {"NbOfTxs": 1}}}}}`,
+			formatJSON, "/Document/FIToFICstmrCdtTrf/GrpHdr/NbOfTxs", reasonIncorrectContent},
+		{"date", `{"BusMsg": {"AppHdr": {"MsgDefIdr": "pacs.008.001.09", "CreDt": "01/03/2021"}}}`, formatJSON, "/AppHdr/CreDt", reasonInvalidDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := decodeMessage([]byte(tt.body), tt.format)
+			if err == nil {
+				t.Fatal("got no error")
+			}
+			issues := decodeIssues(err)
+			if len(issues) != 1 {
+				t.Fatalf("got issues %v, want one", issues)
+			}
+			if issues[0].Location != tt.wantLocation || issues[0].Code != tt.wantCode {
+				t.Errorf("got %s at %s, want %s at %s", issues[0].Code, issues[0].Location, tt.wantCode, tt.wantLocation)
+			}
+		})
+	}
+}
+
+// Values of the sample failing to decode are located at their element in either format
+func TestDecodeIssuesLocation(t *testing.T) {
+	const tx = "/Document/FIToFICstmrCdtTrf/CdtTrfTxInf[0]"
+	tests := []struct {
+		name         string
+		sample       string
+		old, new     string
+		wantLocation string
+		wantCode     string
+	}{
+		{"JSON amount", "pacs008.json", `"Value": "1234.56"`, `"Value": "1234,56"`, tx + "/IntrBkSttlmAmt", reasonIncorrectContent},
+		{"JSON date", "pacs008.json", `"IntrBkSttlmDt": "2021-03-01"`, `"IntrBkSttlmDt": "01/03/2021"`, tx + "/IntrBkSttlmDt", reasonInvalidDate},
+		{"JSON date and time", "pacs008.json", `"CreDtTm": "2021-03-01T19:00:00.123+07:00"`, `"CreDtTm": "2021-03-01 19:00"`,
+			"/Document/FIToFICstmrCdtTrf/GrpHdr/CreDtTm", reasonInvalidDate},
+		{"JSON type in a list", "pacs008.json", `"ChrgBr": "DEBT"`, `"ChrgBr": 1`, tx + "/ChrgBr", reasonIncorrectContent},
+		{"XML amount", "pacs008.xml", `>1234.56<`, `>1234,56<`, tx + "/IntrBkSttlmAmt", reasonIncorrectContent},
+		{"XML date", "pacs008.xml", `<IntrBkSttlmDt>2021-03-01<`, `<IntrBkSttlmDt>01/03/2021<`, tx + "/IntrBkSttlmDt", reasonInvalidDate},
+		{"XML date and time", "pacs008.xml", `<CreDt>2021-03-01T19:00:00Z<`, `<CreDt>2021-03-01 19:00<`, "/AppHdr/CreDt", reasonInvalidDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := strings.Replace(string(readSample(t, tt.sample)), tt.old, tt.new, 1)
+			format := formatJSON
+			if strings.HasSuffix(tt.sample, ".xml") {
+				format = formatXML
+			}
+			_, _, _, err := decodeMessage([]byte(body), format)
+			if err == nil {
+				t.Fatal("got no error")
+			}
+			issues := decodeIssues(err)
+			if len(issues) != 1 || issues[0].Location != tt.wantLocation || issues[0].Code != tt.wantCode {
+				t.Errorf("got issues %v, want %s at %s", issues, tt.wantCode, tt.wantLocation)
+			}
+		})
+	}
+}
+
+func TestJSONLocation(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"BusMsg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf.0.PmtId", "/Document/FIToFICstmrCdtTrf/CdtTrfTxInf[0]/PmtId"},
+		{"BusMsg.AppHdr.Rltd.1.CreDt", "/AppHdr/Rltd[1]/CreDt"},
+		{"BusMsg.AppHdr", "/AppHdr"},
+	}
+	for _, tt := range tests {
+		if got := jsonLocation(tt.field); got != tt.want {
+			t.Errorf("jsonLocation(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
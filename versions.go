@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Canonical pacs.008 version, other versions are converted to it when decoded and from it when encoded
+const canonicalPacs008 = "pacs.008.001.09"
+
+// Issue reporting an element dropped or defaulted by a version conversion
+func conversionWarning(location string, format string, args ...interface{}) ValidationError {
+	return ValidationError{Location: location, Code: reasonNarrative, Severity: severityWarning, Message: fmt.Sprintf(format, args...)}
+}
+
+// Copy src into dst, a message root of another version, through their JSON form
+// adjust reshapes the JSON tree of src where the versions differ in structure and reports what it drops
+// Elements of src dst has no room for, and elements dst sets that src did not, are reported as well
+func convertVersion(src interface{}, dst interface{}, location string, from string, to string,
+	adjust func(tree map[string]interface{}, location string) ValidationErrors) (ValidationErrors, error) {
+	var report ValidationErrors
+
+	data, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := jsonTree(data)
+	if err != nil {
+		return nil, err
+	}
+	if root, ok := tree.(map[string]interface{}); ok && adjust != nil {
+		report = append(report, adjust(root, location)...)
+	}
+
+	if data, err = json.Marshal(tree); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, dst); err != nil {
+		return nil, err
+	}
+
+	if data, err = json.Marshal(dst); err != nil {
+		return nil, err
+	}
+	converted, err := jsonTree(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, dropped := range missingElements(tree, converted, location) {
+		report = append(report, conversionWarning(dropped, "dropped converting %s to %s, which has no such element", from, to))
+	}
+	for _, defaulted := range missingElements(converted, tree, location) {
+		report = append(report, conversionWarning(defaulted, "defaulted converting %s to %s", from, to))
+	}
+	return report, nil
+}
+
+// Decode JSON keeping numbers as written
+func jsonTree(data []byte) (interface{}, error) {
+	var tree interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err := decoder.Decode(&tree)
+	return tree, err
+}
+
+// Return locations of elements set in a but not in b, without descending into missing elements
+func missingElements(a interface{}, b interface{}, location string) []string {
+	var missing []string
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, _ := b.(map[string]interface{})
+		names := make([]string, 0, len(a))
+		for name := range a {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if a[name] == nil {
+				continue
+			}
+			if b == nil || b[name] == nil {
+				missing = append(missing, location+"/"+name)
+				continue
+			}
+			missing = append(missing, missingElements(a[name], b[name], location+"/"+name)...)
+		}
+	case []interface{}:
+		b, _ := b.([]interface{})
+		for i, element := range a {
+			elementLocation := fmt.Sprintf("%s[%d]", location, i)
+			if i >= len(b) {
+				missing = append(missing, elementLocation)
+				continue
+			}
+			missing = append(missing, missingElements(element, b[i], elementLocation)...)
+		}
+	}
+	return missing
+}
+
+// Call fn with every CdtTrfTxInf of a credit transfer JSON tree and its location
+func eachTransaction(tree map[string]interface{}, location string, fn func(tx map[string]interface{}, location string)) {
+	txs, _ := tree["CdtTrfTxInf"].([]interface{})
+	for i, tx := range txs {
+		if tx, ok := tx.(map[string]interface{}); ok {
+			fn(tx, fmt.Sprintf("%s/CdtTrfTxInf[%d]", location, i))
+		}
+	}
+}
+
+// 001.08 has a single RltdRmtInf where 001.09 has a list
+func upgradeV08(tree map[string]interface{}, location string) ValidationErrors {
+	eachTransaction(tree, location, func(tx map[string]interface{}, location string) {
+		if related, ok := tx["RltdRmtInf"].(map[string]interface{}); ok {
+			tx["RltdRmtInf"] = []interface{}{related}
+		}
+	})
+	return nil
+}
+
+// Keep the first RltdRmtInf and creditor agent instruction codes 001.08 knows
+func downgradeV08(tree map[string]interface{}, location string) ValidationErrors {
+	var report ValidationErrors
+	eachTransaction(tree, location, func(tx map[string]interface{}, location string) {
+		if related, ok := tx["RltdRmtInf"].([]interface{}); ok && len(related) > 0 {
+			tx["RltdRmtInf"] = related[0]
+			for i := 1; i < len(related); i++ {
+				report = append(report, conversionWarning(fmt.Sprintf("%s/RltdRmtInf[%d]", location, i),
+					"dropped converting %s to pacs.008.001.08, which allows a single RltdRmtInf", canonicalPacs008))
+			}
+		}
+
+		instructions, _ := tx["InstrForCdtrAgt"].([]interface{})
+		for i, instruction := range instructions {
+			instruction, _ := instruction.(map[string]interface{})
+			code, ok := instruction["Cd"].(string)
+			if !ok || Instruction3Code(code).Validate() == nil {
+				continue
+			}
+			delete(instruction, "Cd")
+			report = append(report, conversionWarning(fmt.Sprintf("%s/InstrForCdtrAgt[%d]/Cd", location, i),
+				"dropped converting %s to pacs.008.001.08, which does not know code %s", canonicalPacs008, code))
+		}
+	})
+	return report
+}
+
+// Decode BusMsg payload into v, a versioned BusMsg
+// Document elements of the payload v has no room for are reported as dropped, decoding would skip them silently
+func unmarshalBusMsg(body []byte, format string, v interface{}, msgDefIdr string) (ValidationErrors, error) {
+	if err := unmarshalPayload(body, format, v); err != nil {
+		return nil, err
+	}
+
+	undecoded, err := undecodedElements(body, format, v)
+	if err != nil {
+		return nil, err
+	}
+	var report ValidationErrors
+	for _, dropped := range undecoded {
+		report = append(report, conversionWarning(dropped, "dropped decoding %s, the element is not modelled", msgDefIdr))
+	}
+	return report, nil
+}
+
+// Return locations of Document elements of payload body that v, the BusMsg decoded from it, does not carry
+func undecodedElements(body []byte, format string, v interface{}) ([]string, error) {
+	if format != formatXML {
+		received, err := jsonTree(body)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(struct {
+			BusMsg interface{} `json:"BusMsg"`
+		}{v})
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := jsonTree(data)
+		if err != nil {
+			return nil, err
+		}
+		return missingElements(jsonDocument(received), jsonDocument(decoded), "/Document"), nil
+	}
+
+	received, err := xmlElements(body)
+	if err != nil {
+		return nil, err
+	}
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := xmlElements(data)
+	if err != nil {
+		return nil, err
+	}
+	// only the outermost element missing is reported, as for JSON
+	var missing []string
+	for location := range received {
+		if strings.HasPrefix(location, "/Document/") && !decoded[location] && decoded[path.Dir(location)] {
+			missing = append(missing, location)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// Return Document of a BusMsg JSON tree
+func jsonDocument(tree interface{}) interface{} {
+	root, _ := tree.(map[string]interface{})
+	busMsg, _ := root["BusMsg"].(map[string]interface{})
+	return busMsg["Document"]
+}
+
+// xmlNode is an element of an XML payload decoded without a model
+type xmlNode struct {
+	name     string
+	children []*xmlNode
+}
+
+// Return locations of the elements below the root element of an XML payload, e.g. /Document/FIToFICstmrCdtTrf
+// Elements repeated under the same parent are indexed, e.g. /Document/FIToFICstmrCdtTrf/CdtTrfTxInf[1]
+func xmlElements(body []byte) (map[string]bool, error) {
+	top := &xmlNode{}
+	stack := []*xmlNode{top}
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: token.Name.Local}
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	elements := make(map[string]bool)
+	var walk func(node *xmlNode, location string)
+	walk = func(node *xmlNode, location string) {
+		count := make(map[string]int)
+		for _, child := range node.children {
+			count[child.name]++
+		}
+		index := make(map[string]int)
+		for _, child := range node.children {
+			childLocation := location + "/" + child.name
+			if count[child.name] > 1 {
+				childLocation = fmt.Sprintf("%s[%d]", childLocation, index[child.name])
+				index[child.name]++
+			}
+			elements[childLocation] = true
+			walk(child, childLocation)
+		}
+	}
+	for _, root := range top.children {
+		walk(root, "")
+	}
+	return elements, nil
+}
+
+// Decode pacs.008.001.08 payload into the canonical model
+// Document keeps its 001.08 namespace, so it is validated against the AppHdr
+func decodePacs008V08(body []byte, format string) (Iso20022, ValidationErrors, error) {
+	var msg BusMsgV08
+	report, err := unmarshalBusMsg(body, format, &msg, "pacs.008.001.08")
+	if err != nil {
+		return Iso20022{}, nil, err
+	}
+	request := Iso20022{BusMsg: BusMsg{AppHdr: msg.AppHdr}}
+	request.BusMsg.Document.XMLName = msg.Document.XMLName
+	if msg.Document.FIToFICstmrCdtTrf == nil {
+		return request, report, nil
+	}
+	converted, err := convertVersion(msg.Document.FIToFICstmrCdtTrf, &request.BusMsg.Document.FIToFICstmrCdtTrf,
+		"/Document/FIToFICstmrCdtTrf", "pacs.008.001.08", canonicalPacs008, upgradeV08)
+	return request, append(report, converted...), err
+}
+
+// Encode canonical message as pacs.008.001.08
+func encodePacs008V08(msg BusMsg) (interface{}, ValidationErrors, error) {
+	out := &BusMsgV08{AppHdr: msg.AppHdr}
+	out.AppHdr.MsgDefIdr = "pacs.008.001.08"
+	out.Document.XMLName = xml.Name{Space: pacs008v08Namespace, Local: "Document"}
+	report, err := convertVersion(msg.Document.FIToFICstmrCdtTrf, &out.Document.FIToFICstmrCdtTrf,
+		"/Document/FIToFICstmrCdtTrf", canonicalPacs008, "pacs.008.001.08", downgradeV08)
+	return out, report, err
+}
+
+// Decode pacs.008.001.10 payload into the canonical model
+// Document keeps its 001.10 namespace, so it is validated against the AppHdr
+func decodePacs008V10(body []byte, format string) (Iso20022, ValidationErrors, error) {
+	var msg BusMsgV10
+	report, err := unmarshalBusMsg(body, format, &msg, "pacs.008.001.10")
+	if err != nil {
+		return Iso20022{}, nil, err
+	}
+	request := Iso20022{BusMsg: BusMsg{AppHdr: msg.AppHdr}}
+	request.BusMsg.Document.XMLName = msg.Document.XMLName
+	if msg.Document.FIToFICstmrCdtTrf == nil {
+		return request, report, nil
+	}
+	converted, err := convertVersion(msg.Document.FIToFICstmrCdtTrf, &request.BusMsg.Document.FIToFICstmrCdtTrf,
+		"/Document/FIToFICstmrCdtTrf", "pacs.008.001.10", canonicalPacs008, nil)
+	return request, append(report, converted...), err
+}
+
+// Encode canonical message as pacs.008.001.10
+func encodePacs008V10(msg BusMsg) (interface{}, ValidationErrors, error) {
+	out := &BusMsgV10{AppHdr: msg.AppHdr}
+	out.AppHdr.MsgDefIdr = "pacs.008.001.10"
+	out.Document.XMLName = xml.Name{Space: pacs008v10Namespace, Local: "Document"}
+	report, err := convertVersion(msg.Document.FIToFICstmrCdtTrf, &out.Document.FIToFICstmrCdtTrf,
+		"/Document/FIToFICstmrCdtTrf", canonicalPacs008, "pacs.008.001.10", nil)
+	return out, report, err
+}
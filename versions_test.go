@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Elements of other versions the canonical model has no room for are reported as dropped, not lost silently
+func TestDecodeVersions(t *testing.T) {
+	const tx = "/Document/FIToFICstmrCdtTrf/CdtTrfTxInf[0]"
+
+	tests := []struct {
+		name        string
+		msgDefIdr   string
+		old, new    string
+		wantDropped []string
+	}{
+		{name: "001.10", msgDefIdr: "pacs.008.001.10"},
+		{
+			name:        "001.10 expiry time",
+			msgDefIdr:   "pacs.008.001.10",
+			old:         `"NbOfTxs": "1"`,
+			new:         `"XpryDtTm": "2021-03-02T19:00:00Z", "NbOfTxs": "1"`,
+			wantDropped: []string{"/Document/FIToFICstmrCdtTrf/GrpHdr/XpryDtTm"},
+		},
+		{
+			name:        "001.10 tax ultimate debtor",
+			msgDefIdr:   "pacs.008.001.10",
+			old:         `"ChrgBr": "DEBT"`,
+			new:         `"ChrgBr": "DEBT", "Tax": {"RefNb": "T1", "UltmtDbtr": {"TaxId": "123"}}`,
+			wantDropped: []string{tx + "/Tax/UltmtDbtr"},
+		},
+		{
+			name:        "001.10 element not modelled",
+			msgDefIdr:   "pacs.008.001.10",
+			old:         `"ChrgBr": "DEBT"`,
+			new:         `"ChrgBr": "DEBT", "RmtInf": {"Ustrd": ["INV 1"], "Unknown": "X"}`,
+			wantDropped: []string{tx + "/RmtInf/Unknown"},
+		},
+		{
+			name:      "001.08 single RltdRmtInf",
+			msgDefIdr: "pacs.008.001.08",
+			old:       `"ChrgBr": "DEBT"`,
+			new:       `"ChrgBr": "DEBT", "RltdRmtInf": {"RmtId": "R1"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := string(readSample(t, "pacs008.json"))
+			body = strings.Replace(body, `"MsgDefIdr": "pacs.008.001.09"`, `"MsgDefIdr": "`+tt.msgDefIdr+`"`, 1)
+			if tt.old != "" {
+				if !strings.Contains(body, tt.old) {
+					t.Fatalf("sample has no %s", tt.old)
+				}
+				body = strings.Replace(body, tt.old, tt.new, 1)
+			}
+
+			request, msgType, report, err := decodeMessage([]byte(body), formatJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msgType.MsgDefIdr != tt.msgDefIdr {
+				t.Errorf("decoded as %s, want %s", msgType.MsgDefIdr, tt.msgDefIdr)
+			}
+			var dropped []string
+			for _, issue := range report {
+				if issue.Severity != severityWarning {
+					t.Errorf("got %s issue, want only warnings: %s", issue.Severity, issue.Error())
+				}
+				dropped = append(dropped, issue.Location)
+			}
+			if strings.Join(dropped, " ") != strings.Join(tt.wantDropped, " ") {
+				t.Errorf("got dropped %v, want %v", dropped, tt.wantDropped)
+			}
+
+			txs := request.BusMsg.Document.FIToFICstmrCdtTrf.CdtTrfTxInf
+			if len(txs) != 1 || txs[0].IntrBkSttlmAmt.Value.String() != "1234.56" || textValue(txs[0].PmtId.TxId) != "20210301INDOIDJA01012345678" {
+				t.Errorf("transaction not carried over: %+v", txs)
+			}
+			if tt.msgDefIdr == "pacs.008.001.08" && tt.old != "" && (len(txs[0].RltdRmtInf) != 1 || textValue(txs[0].RltdRmtInf[0].RmtId) != "R1") {
+				t.Errorf("got RltdRmtInf %+v, want the single one of 001.08", txs[0].RltdRmtInf)
+			}
+		})
+	}
+}